@@ -0,0 +1,404 @@
+package epp
+
+import (
+	"encoding/xml"
+	"reflect"
+
+	"aqwari.net/xml/xmltree"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 응답을 단계적으로 만들기 위한 빌더입니다.
+//
+//	b, err := epp.NewResponse(epp.EppOk).
+//	    WithResData(types.DomainInfoDataType{...}).
+//	    WithExtension(types.IISExtensionInfoDataType{...}).
+//	    WithTrID("ABC-12345", "54321-XYZ").
+//	    Encode()
+type ResponseBuilder struct {
+	response   types.Response
+	extensions []interface{}
+	services   *types.LoginServices
+}
+
+// 주어진 결과 코드와 해당 코드의 메시지로 새로운 응답 빌더를 생성합니다.
+func NewResponse(code ResultCode) *ResponseBuilder {
+	return &ResponseBuilder{
+		response: types.Response{
+			Result: []types.Result{
+				{
+					Code:    code.Code(),
+					Message: code.Message(),
+				},
+			},
+		},
+	}
+}
+
+// 결과에 사유를 추가합니다.
+func (b *ResponseBuilder) WithReason(reason string) *ResponseBuilder {
+	b.response.Result[0].ExternalValue = []types.ExternalErrorValue{
+		{
+			Value:  types.UndefinedValue{Undefined: types.Empty()},
+			Reason: reason,
+		},
+	}
+
+	return b
+}
+
+// 응답의 resData 를 설정합니다.
+func (b *ResponseBuilder) WithResData(data interface{}) *ResponseBuilder {
+	b.response.ResultData = data
+
+	return b
+}
+
+// 응답에 확장을 추가합니다. 여러 번 호출하면 모든 확장이 하나의 <extension>
+// 태그 안에 순서대로 추가됩니다.
+func (b *ResponseBuilder) WithExtension(extensions ...interface{}) *ResponseBuilder {
+	b.extensions = append(b.extensions, extensions...)
+
+	return b
+}
+
+// 응답의 메시지 큐 정보를 설정합니다.
+func (b *ResponseBuilder) WithMsgQ(msgQ types.MessageQueue) *ResponseBuilder {
+	b.response.MessageQ = &msgQ
+
+	return b
+}
+
+// 응답의 클라이언트와 서버 트랜잭션 ID 를 설정합니다.
+func (b *ResponseBuilder) WithTrID(clientTransactionID, serverTransactionID string) *ResponseBuilder {
+	b.response.TransactionID = types.TransactionID{
+		ClientTransactionID: clientTransactionID,
+		ServerTransactionID: serverTransactionID,
+	}
+
+	return b
+}
+
+// 클라이언트가 로그인에서 협상한 서비스를 설정합니다. 클라이언트가
+// unhandled-namespaces-1.0 을 협상했다면 Encode 할 때 협상하지 않은 개체의 resData 와
+// 확장을 결과의 extValue 로 옮깁니다. (RFC 9038)
+func (b *ResponseBuilder) WithLoginServices(services *types.LoginServices) *ResponseBuilder {
+	b.services = services
+
+	return b
+}
+
+// 빌더로 만든 응답을 반환합니다.
+func (b *ResponseBuilder) Build() (types.Response, error) {
+	response := b.response
+
+	if len(b.extensions) > 0 {
+		extension, err := combine("", b.extensions)
+		if err != nil {
+			return types.Response{}, err
+		}
+
+		response.Extension = extension
+	}
+
+	return response, nil
+}
+
+// 빌더로 만든 응답을 서버 속성과 함께 XML 로 Encode 합니다.
+func (b *ResponseBuilder) Encode() ([]byte, error) {
+	response, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	var modify func(*xmltree.Element)
+
+	if handled := handledNamespaces(b.services); handled != nil {
+		modify = func(document *xmltree.Element) {
+			moveUnhandledNamespaces(document, handled)
+		}
+	}
+
+	return encode(response, ServerXMLAttributes(), modify)
+}
+
+// 명령어를 단계적으로 만들기 위한 빌더입니다. 객체에 맞는 빌더가 없는 경우
+// 명령어 타입으로 직접 생성할 수 있습니다.
+//
+//	b, err := epp.NewCommand(types.DomainInfoType{...}).
+//	    WithClientTransactionID("ABC-12345").
+//	    Encode()
+type CommandBuilder struct {
+	command             interface{}
+	extensions          []interface{}
+	clientTransactionID string
+}
+
+// 주어진 명령어 타입으로 새로운 명령어 빌더를 생성합니다.
+func NewCommand(command interface{}) *CommandBuilder {
+	return &CommandBuilder{
+		command: command,
+	}
+}
+
+// 명령어에 확장을 추가합니다.
+func (b *CommandBuilder) WithExtension(extensions ...interface{}) *CommandBuilder {
+	b.extensions = append(b.extensions, extensions...)
+
+	return b
+}
+
+// 명령어의 클라이언트 트랜잭션 ID 를 설정합니다.
+func (b *CommandBuilder) WithClientTransactionID(id string) *CommandBuilder {
+	b.clientTransactionID = id
+
+	return b
+}
+
+// 명령어, 확장과 클라이언트 트랜잭션 ID 를 하나의 값으로 합쳐서 반환합니다.
+// XSD 에 정의된 순서대로 명령어, 확장, 트랜잭션 ID 순서로 Marshal 됩니다.
+func (b *CommandBuilder) Build() (interface{}, error) {
+	values := append([]interface{}{b.command}, b.extensions...)
+
+	if b.clientTransactionID != "" {
+		values = append(values, types.ClientTransactionIDType{
+			ClientTransactionID: b.clientTransactionID,
+		})
+	}
+
+	return combine(rootLocalName, values)
+}
+
+// 빌더로 만든 명령어를 클라이언트 속성과 함께 XML 로 Encode 합니다.
+func (b *CommandBuilder) Encode() ([]byte, error) {
+	command, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return Encode(command, ClientXMLAttributes())
+}
+
+// 도메인 생성 명령어를 단계적으로 만들기 위한 빌더입니다.
+//
+//	b, err := epp.NewDomainCreate("example.se").
+//	    Period(1, "y").
+//	    NS("ns1.example.se", "ns2.example.se").
+//	    Registrant("registrant-00001").
+//	    Contact("admin", "contact-00001").
+//	    AuthInfo("some-password").
+//	    SecDNS(types.DNSSEC{...}).
+//	    Encode()
+type DomainCreateBuilder struct {
+	create  types.DomainCreateType
+	secDNS  *types.DNSSECExtensionCreateType
+	command CommandBuilder
+}
+
+// 주어진 도메인 이름으로 새로운 도메인 생성 빌더를 생성합니다.
+func NewDomainCreate(name string) *DomainCreateBuilder {
+	return &DomainCreateBuilder{
+		create: types.DomainCreateType{
+			Create: types.DomainCreate{
+				Name: name,
+			},
+		},
+	}
+}
+
+// 등록 기간을 설정합니다. 단위는 "y"(년) 또는 "m"(월) 입니다.
+func (b *DomainCreateBuilder) Period(value int, unit string) *DomainCreateBuilder {
+	b.create.Create.Period = &types.Period{
+		Value: value,
+		Unit:  unit,
+	}
+
+	return b
+}
+
+// 네임서버를 호스트 객체로 추가합니다.
+func (b *DomainCreateBuilder) NS(hosts ...string) *DomainCreateBuilder {
+	if b.create.Create.NameServer == nil {
+		b.create.Create.NameServer = &types.NameServer{}
+	}
+
+	b.create.Create.NameServer.HostObject = append(b.create.Create.NameServer.HostObject, hosts...)
+
+	return b
+}
+
+// 네임서버를 호스트 속성으로 추가합니다.
+func (b *DomainCreateBuilder) NSAttr(hostName string, addresses ...types.HostAddress) *DomainCreateBuilder {
+	if b.create.Create.NameServer == nil {
+		b.create.Create.NameServer = &types.NameServer{}
+	}
+
+	b.create.Create.NameServer.HostAttribute = append(b.create.Create.NameServer.HostAttribute, types.HostAttribute{
+		HostName:    hostName,
+		HostAddress: addresses,
+	})
+
+	return b
+}
+
+// 등록자를 설정합니다.
+func (b *DomainCreateBuilder) Registrant(id string) *DomainCreateBuilder {
+	b.create.Create.Registrant = id
+
+	return b
+}
+
+// 주어진 유형(admin, billing, tech)의 연락처를 추가합니다.
+func (b *DomainCreateBuilder) Contact(contactType, id string) *DomainCreateBuilder {
+	b.create.Create.Contacts = append(b.create.Create.Contacts, types.Contact{
+		Name: id,
+		Type: contactType,
+	})
+
+	return b
+}
+
+// 인증 정보(비밀번호)를 설정합니다.
+func (b *DomainCreateBuilder) AuthInfo(password string) *DomainCreateBuilder {
+	b.create.Create.AuthInfo = &types.AuthInfo{
+		Password: password,
+	}
+
+	return b
+}
+
+// secDNS-1.1 확장으로 DS 레코드를 추가합니다.
+func (b *DomainCreateBuilder) SecDNS(records ...types.DNSSEC) *DomainCreateBuilder {
+	if b.secDNS == nil {
+		b.secDNS = &types.DNSSECExtensionCreateType{}
+	}
+
+	b.secDNS.Create.DNSSECData = append(b.secDNS.Create.DNSSECData, records...)
+
+	return b
+}
+
+// secDNS-1.1 확장으로 키 데이터를 추가합니다.
+func (b *DomainCreateBuilder) SecDNSKeyData(keys ...types.DNSSECKeyData) *DomainCreateBuilder {
+	if b.secDNS == nil {
+		b.secDNS = &types.DNSSECExtensionCreateType{}
+	}
+
+	b.secDNS.Create.KeyData = append(b.secDNS.Create.KeyData, keys...)
+
+	return b
+}
+
+// 명령어에 다른 확장을 추가합니다.
+func (b *DomainCreateBuilder) WithExtension(extensions ...interface{}) *DomainCreateBuilder {
+	b.command.WithExtension(extensions...)
+
+	return b
+}
+
+// 명령어의 클라이언트 트랜잭션 ID 를 설정합니다.
+func (b *DomainCreateBuilder) WithClientTransactionID(id string) *DomainCreateBuilder {
+	b.command.WithClientTransactionID(id)
+
+	return b
+}
+
+// 빌더로 만든 도메인 생성 명령어를 확장과 함께 하나의 값으로 반환합니다.
+func (b *DomainCreateBuilder) Build() (interface{}, error) {
+	command := b.command
+	command.command = b.create
+
+	if b.secDNS != nil {
+		command.extensions = append([]interface{}{*b.secDNS}, command.extensions...)
+	}
+
+	return command.Build()
+}
+
+// 빌더로 만든 도메인 생성 명령어를 클라이언트 속성과 함께 XML 로 Encode 합니다.
+func (b *DomainCreateBuilder) Encode() ([]byte, error) {
+	command, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return Encode(command, ClientXMLAttributes())
+}
+
+// 여러 타입을 익명으로 포함하는 구조체를 만들어 하나의 값으로 합칩니다.
+// 이것은 아래와 같이 직접 구조체를 선언하는 것과 같습니다.
+//
+//	struct {
+//	    *types.IISExtensionInfoDataType
+//	    *types.DNSSECExtensionInfoDataType
+//	}{...}
+//
+// reflect 는 메소드가 있는 타입을 값으로 포함하는 구조체를 만들 수 없으므로 항상
+// 포인터로 포함합니다. 합쳐진 구조체는 이름이 없는 타입이므로 최상위 요소로
+// Marshal 하려면 요소 이름을 전달해야 합니다.
+func combine(name string, values []interface{}) (combined interface{}, err error) {
+	if len(values) == 1 {
+		return values[0], nil
+	}
+
+	fields := []reflect.StructField{}
+	offset := 0
+
+	if name != "" {
+		fields = append(fields, reflect.StructField{
+			Name: "XMLName",
+			Type: reflect.TypeOf(xml.Name{}),
+			Tag:  reflect.StructTag(`xml:"` + name + `"`),
+		})
+
+		offset = 1
+	}
+
+	seen := map[reflect.Type]struct{}{}
+
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		if t == nil {
+			return nil, errors.New("can not combine nil values")
+		}
+
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+
+		if t.Kind() != reflect.Struct || t.Name() == "" {
+			return nil, errors.Errorf("can not combine %s, only named struct types are supported", t)
+		}
+
+		if _, ok := seen[t]; ok {
+			return nil, errors.Errorf("can not combine multiple values of type %s", t)
+		}
+
+		seen[t] = struct{}{}
+
+		fields = append(fields, reflect.StructField{
+			Name:      t.Name(),
+			Type:      reflect.PtrTo(t),
+			Anonymous: true,
+		})
+	}
+
+	// 포인터 타입에 메소드가 있는 경우에도 reflect.StructOf 가 panic 을 발생시킵니다.
+	defer func() {
+		if r := recover(); r != nil {
+			combined, err = nil, errors.Errorf("can not combine values: %v", r)
+		}
+	}()
+
+	value := reflect.New(reflect.StructOf(fields)).Elem()
+
+	for i, v := range values {
+		ptr := reflect.New(fields[i+offset].Type.Elem())
+		ptr.Elem().Set(reflect.Indirect(reflect.ValueOf(v)))
+
+		value.Field(i + offset).Set(ptr)
+	}
+
+	return value.Interface(), nil
+}
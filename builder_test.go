@@ -0,0 +1,212 @@
+package epp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseBuilder(t *testing.T) {
+	validator, err := NewValidator("xml/index.xsd")
+	require.Nil(t, err)
+
+	defer validator.Free()
+
+	encoded, err := NewResponse(EppOkMessages).
+		WithResData(types.DomainInfoDataType{
+			InfoData: types.DomainInfoData{
+				Name:     "example.se",
+				ROID:     "EXAMPLE1-REP",
+				ClientID: "ClientX",
+			},
+		}).
+		WithExtension(types.IISExtensionInfoDataType{
+			InfoData: types.IISExtensionInfoData{State: "active"},
+		}).
+		WithExtension(&types.DNSSECExtensionInfoDataType{
+			InfoData: types.DNSSECOrKeyData{
+				DNSSECData: []types.DNSSEC{
+					{KeyTag: 12345, Algorithm: 3, DigestType: 1, Digest: "49FD46E6C4B45C55D4AC"},
+				},
+			},
+		}).
+		WithMsgQ(types.MessageQueue{Count: 5, ID: "12345"}).
+		WithTrID("ABC-12345", "54321-XYZ").
+		Encode()
+
+	require.Nil(t, err)
+	assert.Nil(t, validator.Validate(encoded))
+
+	for _, expected := range []string{
+		`<result code="1301">`,
+		`<msgQ count="5" id="12345"`,
+		`<domain:infData xmlns:domain="urn:ietf:params:xml:ns:domain-1.0"`,
+		`<iis:infData xmlns:iis="urn:se:iis:xml:epp:iis-1.2"`,
+		`<sec:infData xmlns:sec="urn:ietf:params:xml:ns:secDNS-1.1"`,
+		`<clTRID>ABC-12345</clTRID>`,
+		`<svTRID>54321-XYZ</svTRID>`,
+	} {
+		assert.Contains(t, string(encoded), expected)
+	}
+
+	_, err = NewResponse(EppOk).
+		WithExtension(types.IISExtensionInfoDataType{}, types.IISExtensionInfoDataType{}).
+		Encode()
+
+	assert.NotNil(t, err)
+
+	_, err = NewResponse(EppOk).
+		WithExtension(struct{}{}).
+		WithExtension(types.IISExtensionInfoDataType{}).
+		Encode()
+
+	assert.NotNil(t, err)
+}
+
+func TestDomainCreateBuilder(t *testing.T) {
+	validator, err := NewValidator("xml/index.xsd")
+	require.Nil(t, err)
+
+	defer validator.Free()
+
+	encoded, err := NewDomainCreate("example.se").
+		Period(1, "y").
+		NS("ns1.example.se", "ns2.example.se").
+		Registrant("jd1234").
+		Contact("admin", "sh8013").
+		Contact("tech", "sh8013").
+		AuthInfo("2fooBAR").
+		SecDNS(types.DNSSEC{
+			KeyTag:     12345,
+			Algorithm:  3,
+			DigestType: 1,
+			Digest:     "49FD46E6C4B45C55D4AC",
+		}).
+		WithClientTransactionID("ABC-12345").
+		Encode()
+
+	require.Nil(t, err)
+	assert.Nil(t, validator.Validate(encoded))
+
+	for _, expected := range []string{
+		`<domain:create xmlns:domain="urn:ietf:params:xml:ns:domain-1.0"`,
+		`<domain:period unit="y">1</domain:period>`,
+		`<domain:hostObj>ns2.example.se</domain:hostObj>`,
+		`<domain:contact type="tech">sh8013</domain:contact>`,
+		`<sec:create xmlns:sec="urn:ietf:params:xml:ns:secDNS-1.1"`,
+		`<sec:keyTag>12345</sec:keyTag>`,
+		`</extension>`,
+		`<clTRID>ABC-12345</clTRID>`,
+	} {
+		assert.Contains(t, string(encoded), expected)
+	}
+
+	decoded := struct {
+		types.DomainCreateType
+		types.DNSSECExtensionCreateType
+		types.ClientTransactionIDType
+	}{}
+
+	require.Nil(t, Decode(encoded, &decoded))
+	assert.Equal(t, "example.se", decoded.DomainCreateType.Create.Name)
+	assert.Equal(t, []string{"ns1.example.se", "ns2.example.se"}, decoded.DomainCreateType.Create.NameServer.HostObject)
+	assert.Equal(t, uint(12345), decoded.DNSSECExtensionCreateType.Create.DNSSECData[0].KeyTag)
+	assert.Equal(t, "ABC-12345", decoded.ClientTransactionID)
+}
+
+func TestCommandBuilder(t *testing.T) {
+	validator, err := NewValidator("xml/index.xsd")
+	require.Nil(t, err)
+
+	defer validator.Free()
+
+	encoded, err := NewCommand(types.DomainInfoType{
+		Info: types.DomainInfo{
+			Name: types.DomainInfoName{Name: "example.se", Hosts: types.DomainHostsAll},
+		},
+	}).
+		WithClientTransactionID("ABC-12345").
+		Encode()
+
+	require.Nil(t, err)
+	assert.Nil(t, validator.Validate(encoded))
+	assert.Contains(t, string(encoded), `<domain:name hosts="all">example.se</domain:name>`)
+	assert.Contains(t, string(encoded), `<clTRID>ABC-12345</clTRID>`)
+}
+
+func TestResponseBuilder_WithLoginServices(t *testing.T) {
+	validator, err := NewValidator("xml/index.xsd")
+	require.Nil(t, err)
+
+	defer validator.Free()
+
+	services := &types.LoginServices{
+		ObjectURI: []string{types.NameSpaceDomain},
+		ServiceExtension: &types.LoginServiceExtension{
+			ExtensionURI: []string{types.NameSpaceUnhandledNamespaces10, types.NameSpaceDNSSEC11},
+		},
+	}
+
+	response := func(services *types.LoginServices) string {
+		encoded, err := NewResponse(EppOk).
+			WithResData(types.HostInfoDataType{
+				InfoData: types.HostInfoData{
+					Name:       "ns1.example.se",
+					ROID:       "NS1-REP",
+					Status:     []types.HostStatus{{HostStatusType: types.HostStatusOk}},
+					ClientID:   "ClientX",
+					CreateID:   "ClientX",
+					CreateDate: time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC),
+				},
+			}).
+			WithExtension(types.IISExtensionInfoDataType{
+				InfoData: types.IISExtensionInfoData{State: "active"},
+			}).
+			WithExtension(&types.DNSSECExtensionInfoDataType{
+				InfoData: types.DNSSECOrKeyData{
+					MaxSignatureLife: 604800,
+					DNSSECData: []types.DNSSEC{
+						{KeyTag: 12345, Algorithm: 3, DigestType: 1, Digest: "49FD46E6C4B45C55D4AC"},
+					},
+				},
+			}).
+			WithLoginServices(services).
+			WithTrID("ABC-12345", "54321-XYZ").
+			Encode()
+
+		require.Nil(t, err)
+		require.Nil(t, validator.Validate(encoded), string(encoded))
+
+		return string(encoded)
+	}
+
+	// Objects and extensions not in the login services are moved to extValue.
+	encoded := response(services)
+
+	assert.NotContains(t, encoded, "<resData>")
+	assert.Contains(t, encoded, `<sec:infData xmlns:sec="urn:ietf:params:xml:ns:secDNS-1.1"`)
+
+	for _, expected := range []string{
+		`<host:infData xmlns:host="urn:ietf:params:xml:ns:host-1.0"`,
+		`<reason>urn:ietf:params:xml:ns:host-1.0 not in login services</reason>`,
+		`<iis:infData xmlns:iis="urn:se:iis:xml:epp:iis-1.2"`,
+		`<reason>urn:se:iis:xml:epp:iis-1.2 not in login services</reason>`,
+	} {
+		assert.Contains(t, encoded, expected)
+	}
+
+	decoded := types.Response{}
+	require.Nil(t, Decode([]byte(encoded), &decoded))
+	assert.Len(t, decoded.Result[0].ExternalValue, 2)
+
+	// Without unhandled-namespaces-1.0 in the login services nothing is moved.
+	services.ServiceExtension.ExtensionURI = []string{types.NameSpaceDNSSEC11}
+	encoded = response(services)
+
+	assert.Contains(t, encoded, "<resData>")
+	assert.NotContains(t, encoded, "<extValue>")
+	assert.Equal(t, encoded, response(nil))
+}
@@ -0,0 +1,279 @@
+package epp
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// 불러온 서버 인증서의 정보입니다. 인증서 교체를 모니터링 할 때 사용할 수 있습니다.
+type CertificateInfo struct {
+	// DER 로 인코딩 된 인증서의 SHA-256 지문입니다.
+	Fingerprint string
+	Subject     string
+	NotAfter    time.Time
+
+	// 신뢰하는 클라이언트 CA 인증서의 지문입니다.
+	ClientCAs []string
+
+	LoadedAt time.Time
+}
+
+// 서버 인증서, 키와 클라이언트 CA 를 파일에서 읽고 서버를 중단하지 않고 다시 불러올 수
+// 있게 합니다. 다시 불러온 인증서는 새로운 세션에만 적용되며 진행중인 세션은 기존의
+// 인증서로 계속 연결됩니다. 파일을 읽을 수 없거나 인증서가 잘못된 경우에는 기존의
+// 인증서를 계속 사용합니다.
+type CertificateReloader struct {
+	CertFile string
+	KeyFile  string
+
+	// 클라이언트 인증서를 검증할 CA 번들입니다. 설정되면 모든 클라이언트 인증서를 검증합니다.
+	CAFile string
+
+	// 파일이 변경되었는지 확인하는 주기입니다. 0 이면 SIGHUP 또는 Reload 로만 다시 불러옵니다.
+	Interval time.Duration
+
+	// 인증서를 불러올 때마다 실행되는 함수입니다. 메트릭을 기록할 때 사용할 수 있습니다.
+	OnReload func(CertificateInfo)
+
+	certificate *tls.Certificate
+	clientCAs   *x509.CertPool
+	info        CertificateInfo
+	modTimes    map[string]time.Time
+
+	// 인증서의 읽기, 쓰기 작업에 Thread Safe 접근을 보장하기 위한 Mutex 입니다.
+	mu sync.RWMutex
+}
+
+// 인증서를 처음으로 불러온 CertificateReloader 를 생성합니다.
+func NewCertificateReloader(certFile, keyFile, caFile string) (*CertificateReloader, error) {
+	r := &CertificateReloader{
+		CertFile: certFile,
+		KeyFile:  keyFile,
+		CAFile:   caFile,
+	}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// 파일에서 인증서와 CA 를 다시 불러옵니다.
+func (r *CertificateReloader) Reload() error {
+	modTimes := map[string]time.Time{}
+
+	for _, file := range []string{r.CertFile, r.KeyFile, r.CAFile} {
+		if file == "" {
+			continue
+		}
+
+		stat, err := os.Stat(file)
+		if err != nil {
+			return errors.Wrap(err, "could not read certificate")
+		}
+
+		modTimes[file] = stat.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+	if err != nil {
+		return errors.Wrap(err, "could not load certificate")
+	}
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return errors.Wrap(err, "could not parse certificate")
+	}
+
+	cert.Leaf = leaf
+
+	info := CertificateInfo{
+		Fingerprint: fingerprint(leaf.Raw),
+		Subject:     leaf.Subject.String(),
+		NotAfter:    leaf.NotAfter,
+		LoadedAt:    time.Now(),
+	}
+
+	var pool *x509.CertPool
+
+	if r.CAFile != "" {
+		pool, info.ClientCAs, err = loadCertPool(r.CAFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	r.certificate = &cert
+	r.clientCAs = pool
+	r.info = info
+	r.modTimes = modTimes
+	r.mu.Unlock()
+
+	log.Printf(
+		"loaded certificate %s (%s) expiring %s with %d client CAs",
+		info.Fingerprint, info.Subject, info.NotAfter.Format(time.RFC3339), len(info.ClientCAs),
+	)
+
+	if r.OnReload != nil {
+		r.OnReload(info)
+	}
+
+	return nil
+}
+
+// 현재 사용중인 인증서의 정보를 반환합니다.
+func (r *CertificateReloader) Info() CertificateInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.info
+}
+
+// tls.Config 의 GetCertificate 로 사용할 수 있는 현재 인증서를 반환합니다.
+func (r *CertificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.certificate, nil
+}
+
+// 주어진 설정을 기반으로 매 연결마다 현재 인증서와 CA 를 사용하는 TLS 설정을 반환합니다.
+func (r *CertificateReloader) TLSConfig(base *tls.Config) *tls.Config {
+	if base == nil {
+		base = &tls.Config{}
+	}
+
+	// GetConfigForClient 가 반환하는 설정이 다시 GetConfigForClient 를 가지지 않도록
+	// 원본 설정을 복사해서 사용합니다.
+	base = base.Clone()
+
+	cfg := base.Clone()
+	cfg.GetCertificate = r.GetCertificate
+	cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		c := base.Clone()
+		c.Certificates = []tls.Certificate{*r.certificate}
+
+		if r.clientCAs != nil {
+			c.ClientCAs = r.clientCAs
+			c.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		return c, nil
+	}
+
+	return cfg
+}
+
+// SIGHUP 을 받거나 Interval 마다 파일이 변경된 것을 확인하면 인증서를 다시 불러옵니다.
+// stop 채널이 닫힐 때까지 실행됩니다.
+func (r *CertificateReloader) Watch(stop <-chan struct{}) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	defer signal.Stop(sigs)
+
+	var tick <-chan time.Time
+
+	if r.Interval > 0 {
+		ticker := time.NewTicker(r.Interval)
+		defer ticker.Stop()
+
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-sigs:
+			if err := r.Reload(); err != nil {
+				log.Printf("could not reload certificate: %s", err.Error())
+			}
+		case <-tick:
+			if err := r.reloadIfModified(); err != nil {
+				log.Printf("could not reload certificate: %s", err.Error())
+			}
+		}
+	}
+}
+
+// 파일 중 하나라도 변경되었으면 인증서를 다시 불러옵니다.
+func (r *CertificateReloader) reloadIfModified() error {
+	r.mu.RLock()
+	modTimes := r.modTimes
+	r.mu.RUnlock()
+
+	for file, modTime := range modTimes {
+		stat, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+
+		if !stat.ModTime().Equal(modTime) {
+			return r.Reload()
+		}
+	}
+
+	return nil
+}
+
+// PEM 파일의 모든 인증서로 CertPool 을 생성하고 각 인증서의 지문을 반환합니다.
+func loadCertPool(file string) (*x509.CertPool, []string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not read CA")
+	}
+
+	pool := x509.NewCertPool()
+	fingerprints := []string{}
+
+	for len(data) > 0 {
+		var block *pem.Block
+
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "could not parse CA")
+		}
+
+		pool.AddCert(cert)
+		fingerprints = append(fingerprints, fingerprint(cert.Raw))
+	}
+
+	if len(fingerprints) == 0 {
+		return nil, nil, errors.Errorf("no certificates found in %s", file)
+	}
+
+	return pool, fingerprints, nil
+}
+
+func fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,148 @@
+package epp
+
+import (
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 새로운 인증서와 키를 <name>.crt, <name>.key 로 작성하고 인증서를 반환합니다.
+func writeCertificate(t *testing.T, dir, name string) tls.Certificate {
+	cert := generateCertificate()
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(cert.PrivateKey.(*rsa.PrivateKey)),
+	})
+
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+".crt"), certPEM, 0600))
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, name+".key"), keyPEM, 0600))
+
+	return cert
+}
+
+func TestCertificateReloader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epp-certificate")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	first := writeCertificate(t, dir, "server")
+	writeCertificate(t, dir, "ca")
+
+	reloaded := []CertificateInfo{}
+
+	r, err := NewCertificateReloader(certFile, keyFile, filepath.Join(dir, "ca.crt"))
+	require.Nil(t, err)
+
+	r.OnReload = func(info CertificateInfo) { reloaded = append(reloaded, info) }
+
+	assert.Equal(t, fingerprint(first.Certificate[0]), r.Info().Fingerprint)
+	assert.Len(t, r.Info().ClientCAs, 1)
+
+	// Nothing has changed so nothing should be reloaded.
+	require.Nil(t, r.reloadIfModified())
+	assert.Empty(t, reloaded)
+
+	second := writeCertificate(t, dir, "server")
+
+	// Make sure the modification time differs even on file systems with low
+	// resolution.
+	later := time.Now().Add(time.Minute)
+	require.Nil(t, os.Chtimes(certFile, later, later))
+	require.Nil(t, r.reloadIfModified())
+
+	require.Len(t, reloaded, 1)
+	assert.Equal(t, fingerprint(second.Certificate[0]), reloaded[0].Fingerprint)
+
+	tlsConfig, err := r.TLSConfig(&tls.Config{}).GetConfigForClient(nil)
+	require.Nil(t, err)
+	assert.Equal(t, second.Certificate[0], tlsConfig.Certificates[0].Certificate[0])
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+
+	// A broken certificate keeps the current one.
+	require.Nil(t, ioutil.WriteFile(certFile, []byte("broken"), 0600))
+	assert.NotNil(t, r.Reload())
+	assert.Equal(t, fingerprint(second.Certificate[0]), r.Info().Fingerprint)
+}
+
+func TestServer_reloadCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epp-certificate")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	first := writeCertificate(t, dir, "server")
+
+	certificates, err := NewCertificateReloader(filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key"), "")
+	require.Nil(t, err)
+
+	didStart := make(chan struct{})
+
+	srv := Server{
+		Addr:         ":9890",
+		Certificates: certificates,
+		SessionConfig: SessionConfig{
+			IdleTimeout:    time.Minute,
+			SessionTimeout: time.Minute,
+			Handler: func(s *Session, in []byte) ([]byte, error) {
+				return in, nil
+			},
+			Greeting: func(s *Session) ([]byte, error) {
+				return []byte("hello"), nil
+			},
+		},
+		OnStarteds: []func(){
+			func() {
+				didStart <- struct{}{}
+			},
+		},
+	}
+
+	defer srv.Stop()
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil {
+			panic(err)
+		}
+	}()
+
+	<-didStart
+
+	// 연결된 서버의 인증서를 반환합니다.
+	connect := func() (*Client, []byte) {
+		client := &Client{TLSConfig: &tls.Config{InsecureSkipVerify: true}}
+
+		_, err := client.Connect(":9890")
+		require.Nil(t, err)
+
+		return client, client.conn.(*tls.Conn).ConnectionState().PeerCertificates[0].Raw
+	}
+
+	client, peer := connect()
+	assert.Equal(t, first.Certificate[0], peer)
+
+	second := writeCertificate(t, dir, "server")
+	require.Nil(t, certificates.Reload())
+
+	// New sessions use the new certificate while the existing session is still
+	// usable.
+	_, peer = connect()
+	assert.Equal(t, second.Certificate[0], peer)
+
+	response, err := client.Send([]byte("ping"))
+	require.Nil(t, err)
+	assert.Equal(t, "ping", string(response))
+}
@@ -0,0 +1,65 @@
+package epp
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+)
+
+var notConnected = errors.New("client is not connected")
+
+// EPP 서버에 연결하여 요청을 보내는 클라이언트를 나타냅니다.
+type Client struct {
+	// 서버에 연결할 때 사용할 TLS 설정입니다.
+	TLSConfig *tls.Config
+
+	// 서버와 연결된 TLS 연결입니다.
+	conn net.Conn
+}
+
+// 지정된 주소의 서버에 연결하고 서버가 보낸 greeting 을 반환합니다.
+func (c *Client) Connect(addr string) ([]byte, error) {
+	tlsConfig := c.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	}
+
+	conn, err := tls.Dial("tcp", addr, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// 연결이 되면 서버는 항상 greeting 을 먼저 보냅니다. (RFC5730 2.4)
+	greeting, err := ReadMessage(conn)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	c.conn = conn
+
+	return greeting, nil
+}
+
+// 서버에 메시지를 보내고 그에 대한 응답을 반환합니다.
+func (c *Client) Send(data []byte) ([]byte, error) {
+	if c.conn == nil {
+		return nil, notConnected
+	}
+
+	if err := WriteMessage(c.conn, data); err != nil {
+		return nil, err
+	}
+
+	return ReadMessage(c.conn)
+}
+
+// 서버와의 연결을 닫습니다.
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return notConnected
+	}
+
+	return c.conn.Close()
+}
@@ -0,0 +1,362 @@
+package epp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+// 클라이언트 인증서를 검증하는 정책입니다. 연결할 때 인증서의 유효 기간과 폐기 여부를
+// CRL 과 OCSP 응답으로 확인하고, 로그인 할 때 인증서와 접속한 IP 가 로그인하는 clID
+// 에 등록된 것인지 확인합니다. OCSP 응답은 클라이언트가 TLS 핸드셰이크에서 전달한
+// 응답을 먼저 사용하고, 없으면 파일에서 불러온 응답을 사용합니다. CRL 과 OCSP 응답은
+// Issuers 에 있는 발급자의 서명을 검증한 후에만 사용됩니다.
+//
+//	policy := epp.NewClientCertPolicy(caCertificate)
+//	policy.RequirePinning = true
+//
+//	policy.PinCertificate("ClientX", "5d41402abc4b2a76b9719d911017c592...")
+//	policy.AllowNetwork("ClientX", "192.0.2.0/24")
+//	policy.LoadCRL("ca.crl")
+type ClientCertPolicy struct {
+	// 클라이언트 인증서의 발급자입니다. CRL 과 OCSP 응답의 서명을 검증할 때 사용됩니다.
+	Issuers []*x509.Certificate
+
+	// 설정되면 유효한 OCSP 응답이 없는 인증서는 거부합니다.
+	RequireOCSP bool
+
+	// 설정되면 인증서 지문이 등록되지 않은 clID 의 로그인을 거부합니다. 설정하지 않으면
+	// 지문이 등록되지 않은 clID 에는 다른 clID 에 등록되지 않은 인증서로 로그인 할 수
+	// 있습니다.
+	RequirePinning bool
+
+	// 현재 시간을 반환하는 함수입니다. 테스트에서 시간을 고정하기 위해 사용할 수 있습니다.
+	Now func() time.Time
+
+	fingerprints map[string][]string
+	networks     map[string][]*net.IPNet
+	revoked      map[string]time.Time
+	ocsp         map[string]ocspStatus
+
+	// 정책의 읽기, 쓰기 작업에 Thread Safe 접근을 보장하기 위한 Mutex 입니다.
+	mu sync.RWMutex
+}
+
+// 주어진 발급자의 CRL 과 OCSP 응답을 사용하는 정책을 생성합니다.
+func NewClientCertPolicy(issuers ...*x509.Certificate) *ClientCertPolicy {
+	return &ClientCertPolicy{
+		Issuers:      issuers,
+		Now:          time.Now,
+		fingerprints: map[string][]string{},
+		networks:     map[string][]*net.IPNet{},
+		revoked:      map[string]time.Time{},
+		ocsp:         map[string]ocspStatus{},
+	}
+}
+
+// clID 가 사용할 수 있는 인증서의 SHA-256 지문을 등록합니다. 지문이 등록된 clID 는
+// 등록된 인증서로만 로그인 할 수 있고, 등록된 인증서로 다른 clID 에 로그인 할 수
+// 없습니다.
+func (p *ClientCertPolicy) PinCertificate(clientID string, fingerprints ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, f := range fingerprints {
+		// openssl 과 같이 콜론으로 구분된 지문도 사용할 수 있습니다.
+		f = strings.ToLower(strings.Replace(f, ":", "", -1))
+		p.fingerprints[clientID] = append(p.fingerprints[clientID], f)
+	}
+}
+
+// clID 가 접속할 수 있는 네트워크를 CIDR 로 등록합니다. 네트워크가 등록된 clID 는
+// 등록된 네트워크에서만 로그인 할 수 있습니다.
+func (p *ClientCertPolicy) AllowNetwork(clientID string, cidrs ...string) error {
+	networks := []*net.IPNet{}
+
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return errors.Wrapf(err, "invalid network for %s", clientID)
+		}
+
+		networks = append(networks, network)
+	}
+
+	p.mu.Lock()
+	p.networks[clientID] = append(p.networks[clientID], networks...)
+	p.mu.Unlock()
+
+	return nil
+}
+
+// PEM 또는 DER 로 인코딩 된 CRL 파일을 읽어 폐기된 인증서를 등록합니다.
+func (p *ClientCertPolicy) LoadCRL(file string) error {
+	data, err := readPEMOrDER(file, "X509 CRL")
+	if err != nil {
+		return err
+	}
+
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		return errors.Wrap(err, "could not parse CRL")
+	}
+
+	issuer := p.issuerOf(func(issuer *x509.Certificate) bool {
+		return crl.CheckSignatureFrom(issuer) == nil
+	})
+
+	if issuer == nil {
+		return errors.Errorf("CRL %s is not signed by a known issuer", file)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, entry := range crl.RevokedCertificates {
+		p.revoked[certificateKey(issuer.RawSubject, entry.SerialNumber.String())] = entry.RevocationTime
+	}
+
+	return nil
+}
+
+// PEM 또는 DER 로 인코딩 된 OCSP 응답 파일을 읽어 인증서 상태를 등록합니다. 파일에는
+// 하나의 인증서 상태만 있어야 합니다. 등록된 응답은 클라이언트가 TLS 핸드셰이크에서 OCSP 응답을 전달하지 않았을 때 사용됩니다.
+func (p *ClientCertPolicy) LoadOCSPResponse(file string) error {
+	data, err := readPEMOrDER(file, "OCSP RESPONSE")
+	if err != nil {
+		return err
+	}
+
+	var status *ocspStatus
+
+	issuer := p.issuerOf(func(issuer *x509.Certificate) bool {
+		status, err = parseOCSPResponse(data, nil, issuer, p.Now())
+		return err == nil
+	})
+
+	if issuer == nil {
+		return errors.Errorf("OCSP response %s is not signed by a known issuer", file)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.ocsp[certificateKey(issuer.RawSubject, status.SerialNumber.String())] = *status
+
+	return nil
+}
+
+// 연결의 클라이언트 인증서를 확인합니다. 클라이언트가 TLS 1.3 핸드셰이크에서 인증서와
+// 함께 OCSP 응답을 전달했으면 (RFC8446 4.4.2.1) 응답을 검증한 후 등록된 응답 대신
+// 사용합니다.
+func (p *ClientCertPolicy) VerifyConnection(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("no client certificate")
+	}
+
+	cert := state.PeerCertificates[0]
+
+	if len(state.OCSPResponse) == 0 {
+		return p.VerifyCertificate(cert)
+	}
+
+	status, err := p.stapledStatus(cert, state.OCSPResponse)
+	if err != nil {
+		return err
+	}
+
+	return p.verifyCertificate(cert, status)
+}
+
+// 인증서의 유효 기간과 폐기 여부를 등록된 CRL 과 OCSP 응답으로 확인합니다.
+func (p *ClientCertPolicy) VerifyCertificate(cert *x509.Certificate) error {
+	return p.verifyCertificate(cert, nil)
+}
+
+// 인증서의 유효 기간과 폐기 여부를 확인합니다. stapled 가 nil 이 아니면 등록된 OCSP
+// 응답 대신 사용합니다.
+func (p *ClientCertPolicy) verifyCertificate(cert *x509.Certificate, stapled *ocspStatus) error {
+	if cert == nil {
+		return errors.New("no client certificate")
+	}
+
+	now := p.Now()
+
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return errors.New("client certificate is not valid at this time")
+	}
+
+	key := certificateKey(cert.RawIssuer, cert.SerialNumber.String())
+
+	p.mu.RLock()
+	revokedAt, revoked := p.revoked[key]
+	status, hasOCSP := p.ocsp[key]
+	p.mu.RUnlock()
+
+	if stapled != nil {
+		status, hasOCSP = *stapled, true
+	}
+
+	if revoked && !now.Before(revokedAt) {
+		return errors.New("client certificate is revoked by CRL")
+	}
+
+	if hasOCSP && status.Status == ocsp.Revoked && !now.Before(status.RevokedAt) {
+		return errors.New("client certificate is revoked by OCSP")
+	}
+
+	if !p.RequireOCSP {
+		return nil
+	}
+
+	if !hasOCSP || status.Status != ocsp.Good {
+		return errors.New("no valid OCSP response for client certificate")
+	}
+
+	if !status.NextUpdate.IsZero() && now.After(status.NextUpdate) {
+		return errors.New("OCSP response for client certificate is expired")
+	}
+
+	return nil
+}
+
+// 인증서와 접속한 주소가 로그인하는 clID 에 등록된 것인지 확인합니다.
+func (p *ClientCertPolicy) VerifyLogin(clientID string, cert *x509.Certificate, addr net.Addr) error {
+	p.mu.RLock()
+	fingerprints := p.fingerprints[clientID]
+	networks := p.networks[clientID]
+	owner := p.ownerOf(cert)
+	p.mu.RUnlock()
+
+	switch {
+	case len(fingerprints) > 0 || p.RequirePinning:
+		if cert == nil || indexOfString(fingerprints, fingerprint(cert.Raw)) < 0 {
+			return errors.Errorf("client certificate does not belong to %s", clientID)
+		}
+	case owner != "":
+		return errors.Errorf("client certificate belongs to %s, not %s", owner, clientID)
+	}
+
+	if len(networks) > 0 {
+		ip := addressIP(addr)
+
+		allowed := false
+
+		for _, n := range networks {
+			if ip != nil && n.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return errors.Errorf("%s may not connect from %s", clientID, addr)
+		}
+	}
+
+	return nil
+}
+
+// 인증서의 지문이 등록된 clID 를 반환합니다. 호출하는 쪽에서 읽기 잠금을 가지고 있어야
+// 합니다.
+func (p *ClientCertPolicy) ownerOf(cert *x509.Certificate) string {
+	if cert == nil {
+		return ""
+	}
+
+	f := fingerprint(cert.Raw)
+
+	for clientID, fingerprints := range p.fingerprints {
+		if indexOfString(fingerprints, f) >= 0 {
+			return clientID
+		}
+	}
+
+	return ""
+}
+
+// 인증서와 함께 전달된 OCSP 응답을 인증서 발급자로 검증하고 인증서의 상태를 반환합니다.
+func (p *ClientCertPolicy) stapledStatus(cert *x509.Certificate, der []byte) (*ocspStatus, error) {
+	issuer := p.issuerOf(func(issuer *x509.Certificate) bool {
+		return bytes.Equal(issuer.RawSubject, cert.RawIssuer) && cert.CheckSignatureFrom(issuer) == nil
+	})
+
+	if issuer == nil {
+		return nil, errors.New("stapled OCSP response can not be verified without the certificate issuer")
+	}
+
+	status, err := parseOCSPResponse(der, cert, issuer, p.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid stapled OCSP response")
+	}
+
+	return status, nil
+}
+
+// 조건을 만족하는 첫 번째 발급자를 반환합니다.
+func (p *ClientCertPolicy) issuerOf(match func(*x509.Certificate) bool) *x509.Certificate {
+	for _, issuer := range p.Issuers {
+		if match(issuer) {
+			return issuer
+		}
+	}
+
+	return nil
+}
+
+func certificateKey(rawIssuer []byte, serial string) string {
+	return string(rawIssuer) + "/" + serial
+}
+
+func addressIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		return a.IP
+	case nil:
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+
+	return net.ParseIP(host)
+}
+
+func indexOfString(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// PEM 파일이면 주어진 타입의 블록을, 아니면 파일 내용을 DER 로 반환합니다.
+func readPEMOrDER(file, blockType string) ([]byte, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	if block, _ := pem.Decode(data); block != nil {
+		if block.Type != blockType {
+			return nil, errors.Errorf("expected %s in %s, got %s", blockType, file, block.Type)
+		}
+
+		return block.Bytes, nil
+	}
+
+	return data, nil
+}
@@ -0,0 +1,414 @@
+package epp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *rsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "epp-go test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+// CA 가 발급한 클라이언트 인증서를 생성합니다.
+func (ca *testCA) issue(t *testing.T, serial int64, clientID string) tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: clientID},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(0, 1, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	require.Nil(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func (ca *testCA) crl(t *testing.T, serials ...int64) []byte {
+	revoked := []pkix.RevokedCertificate{}
+
+	for _, s := range serials {
+		revoked = append(revoked, pkix.RevokedCertificate{
+			SerialNumber:   big.NewInt(s),
+			RevocationTime: time.Now().Add(-time.Minute),
+		})
+	}
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(1),
+		ThisUpdate:          time.Now().Add(-time.Hour),
+		NextUpdate:          time.Now().Add(time.Hour),
+		RevokedCertificates: revoked,
+	}, ca.cert, ca.key)
+	require.Nil(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+}
+
+// CA 가 위임한 OCSP 응답자의 인증서를 생성합니다.
+func (ca *testCA) responder(t *testing.T, usage ...x509.ExtKeyUsage) tls.Certificate {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "epp-go test OCSP responder"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(0, 1, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  usage,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	require.Nil(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.Nil(t, err)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// 인증서의 상태를 가진 OCSP 응답을 생성합니다.
+func (ca *testCA) ocsp(t *testing.T, serial int64, revoked bool, nextUpdate time.Time) []byte {
+	return ocspResponse(t, ca.cert, serial, revoked, nextUpdate, tls.Certificate{Leaf: ca.cert, PrivateKey: ca.key}, false)
+}
+
+// issuer 가 발급한 인증서의 상태를 가진 OCSP 응답을 responder 로 서명합니다. embed 가
+// 설정되면 응답자의 인증서를 응답에 포함합니다.
+func ocspResponse(t *testing.T, issuer *x509.Certificate, serial int64, revoked bool, nextUpdate time.Time, responder tls.Certificate, embed bool) []byte {
+	template := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: big.NewInt(serial),
+		ThisUpdate:   time.Now().Add(-time.Hour),
+		NextUpdate:   nextUpdate,
+		IssuerHash:   crypto.SHA256,
+	}
+
+	if revoked {
+		template.Status = ocsp.Revoked
+		template.RevokedAt = time.Now().Add(-time.Minute)
+	}
+
+	if embed {
+		template.Certificate = responder.Leaf
+	}
+
+	response, err := ocsp.CreateResponse(issuer, responder.Leaf, template, responder.PrivateKey.(crypto.Signer))
+	require.Nil(t, err)
+
+	return response
+}
+
+func TestParseOCSPResponse(t *testing.T) {
+	ca := newTestCA(t)
+	other := newTestCA(t)
+	now := time.Now()
+	hour := now.Add(time.Hour)
+
+	caResponder := tls.Certificate{Leaf: ca.cert, PrivateKey: ca.key}
+	delegated := ca.responder(t, x509.ExtKeyUsageOCSPSigning)
+	unauthorized := ca.responder(t, x509.ExtKeyUsageClientAuth)
+	foreign := other.responder(t, x509.ExtKeyUsageOCSPSigning)
+
+	status, err := parseOCSPResponse(ca.ocsp(t, 10, false, hour), nil, ca.cert, now)
+	require.Nil(t, err)
+	assert.Equal(t, ocsp.Good, status.Status)
+	assert.Equal(t, int64(10), status.SerialNumber.Int64())
+
+	_, err = parseOCSPResponse(ocspResponse(t, ca.cert, 10, false, hour, delegated, true), nil, ca.cert, now)
+	require.Nil(t, err)
+
+	// Delegated responders must have the id-kp-OCSPSigning extended key usage
+	// and be valid.
+	_, err = parseOCSPResponse(ocspResponse(t, ca.cert, 10, false, hour, unauthorized, true), nil, ca.cert, now)
+	assert.NotNil(t, err)
+
+	_, err = parseOCSPResponse(ocspResponse(t, ca.cert, 10, false, hour, delegated, true), nil, ca.cert, now.AddDate(1, 0, 0))
+	assert.NotNil(t, err)
+
+	_, err = parseOCSPResponse(ocspResponse(t, ca.cert, 10, false, hour, foreign, true), nil, ca.cert, now)
+	assert.NotNil(t, err)
+
+	// A responder of another issuer can't answer for certificates of this issuer.
+	_, err = parseOCSPResponse(ocspResponse(t, ca.cert, 10, false, hour, foreign, true), nil, other.cert, now)
+	assert.NotNil(t, err)
+
+	// The responder ID must match the signer.
+	misnamed := tls.Certificate{Leaf: delegated.Leaf, PrivateKey: ca.key}
+	_, err = parseOCSPResponse(ocspResponse(t, ca.cert, 10, false, hour, misnamed, false), nil, ca.cert, now)
+	assert.NotNil(t, err)
+
+	// The CertID must match the issuer even if the signature is valid.
+	_, err = parseOCSPResponse(ocspResponse(t, other.cert, 10, false, hour, caResponder, false), nil, ca.cert, now)
+	assert.NotNil(t, err)
+
+	// Responses produced for a later time are rejected.
+	_, err = parseOCSPResponse(ca.ocsp(t, 10, false, hour), nil, ca.cert, now.Add(-2*time.Hour))
+	assert.NotNil(t, err)
+
+	// A certificate selects its status from the response.
+	_, err = parseOCSPResponse(ca.ocsp(t, 10, false, hour), ca.issue(t, 11, "ClientX").Leaf, ca.cert, now)
+	assert.NotNil(t, err)
+}
+
+func TestClientCertPolicy_VerifyCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "epp-clientcert")
+	require.Nil(t, err)
+
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	other := newTestCA(t)
+
+	write := func(name string, data []byte) string {
+		file := filepath.Join(dir, name)
+		require.Nil(t, ioutil.WriteFile(file, data, 0600))
+
+		return file
+	}
+
+	good := ca.issue(t, 10, "ClientX")
+	revokedByCRL := ca.issue(t, 11, "ClientX")
+	revokedByOCSP := ca.issue(t, 12, "ClientX")
+	expiredOCSP := ca.issue(t, 13, "ClientX")
+
+	policy := NewClientCertPolicy(ca.cert)
+
+	require.Nil(t, policy.LoadCRL(write("ca.crl", ca.crl(t, 11))))
+	require.Nil(t, policy.LoadOCSPResponse(write("10.der", ca.ocsp(t, 10, false, time.Now().Add(time.Hour)))))
+	require.Nil(t, policy.LoadOCSPResponse(write("12.der", ca.ocsp(t, 12, true, time.Now().Add(time.Hour)))))
+	require.Nil(t, policy.LoadOCSPResponse(write("13.der", ca.ocsp(t, 13, false, time.Now().Add(-time.Minute)))))
+
+	// Revocation lists and responses must be signed by a known issuer.
+	assert.NotNil(t, policy.LoadCRL(write("other.crl", other.crl(t, 10))))
+	assert.NotNil(t, policy.LoadOCSPResponse(write("other.der", other.ocsp(t, 10, true, time.Now()))))
+
+	assert.Nil(t, policy.VerifyCertificate(good.Leaf))
+	assert.Nil(t, policy.VerifyCertificate(expiredOCSP.Leaf))
+	assert.NotNil(t, policy.VerifyCertificate(revokedByCRL.Leaf))
+	assert.NotNil(t, policy.VerifyCertificate(revokedByOCSP.Leaf))
+	assert.NotNil(t, policy.VerifyCertificate(nil))
+
+	policy.RequireOCSP = true
+
+	assert.Nil(t, policy.VerifyCertificate(good.Leaf))
+	assert.NotNil(t, policy.VerifyCertificate(expiredOCSP.Leaf))
+	assert.NotNil(t, policy.VerifyCertificate(ca.issue(t, 14, "ClientX").Leaf))
+
+	policy.Now = func() time.Time { return time.Now().AddDate(1, 0, 0) }
+
+	assert.NotNil(t, policy.VerifyCertificate(good.Leaf))
+}
+
+func TestClientCertPolicy_VerifyConnection(t *testing.T) {
+	ca := newTestCA(t)
+	other := newTestCA(t)
+	cert := ca.issue(t, 10, "ClientX")
+
+	state := func(stapled []byte) tls.ConnectionState {
+		return tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert.Leaf}, OCSPResponse: stapled}
+	}
+
+	policy := NewClientCertPolicy(ca.cert)
+	policy.RequireOCSP = true
+
+	assert.NotNil(t, policy.VerifyConnection(tls.ConnectionState{}))
+	assert.NotNil(t, policy.VerifyConnection(state(nil)))
+
+	// A stapled response is used instead of the loaded responses but must be
+	// signed by the issuer and be for the presented certificate.
+	assert.Nil(t, policy.VerifyConnection(state(ca.ocsp(t, 10, false, time.Now().Add(time.Hour)))))
+	assert.NotNil(t, policy.VerifyConnection(state(ca.ocsp(t, 10, true, time.Now().Add(time.Hour)))))
+	assert.NotNil(t, policy.VerifyConnection(state(ca.ocsp(t, 10, false, time.Now().Add(-time.Minute)))))
+	assert.NotNil(t, policy.VerifyConnection(state(ca.ocsp(t, 11, false, time.Now().Add(time.Hour)))))
+	assert.NotNil(t, policy.VerifyConnection(state(other.ocsp(t, 10, false, time.Now().Add(time.Hour)))))
+	assert.NotNil(t, policy.VerifyConnection(state([]byte("not a response"))))
+}
+
+func TestClientCertPolicy_VerifyLogin(t *testing.T) {
+	ca := newTestCA(t)
+	certX := ca.issue(t, 10, "ClientX")
+	certY := ca.issue(t, 11, "ClientY")
+	addr := &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 54321}
+
+	policy := NewClientCertPolicy(ca.cert)
+	policy.PinCertificate("ClientX", fingerprint(certX.Leaf.Raw))
+
+	require.Nil(t, policy.AllowNetwork("ClientY", "198.51.100.0/24", "192.0.2.0/28"))
+	assert.NotNil(t, policy.AllowNetwork("ClientY", "192.0.2.300/24"))
+
+	assert.Nil(t, policy.VerifyLogin("ClientX", certX.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientX", certY.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientX", nil, addr))
+
+	assert.Nil(t, policy.VerifyLogin("ClientY", certY.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientY", certY.Leaf, &net.TCPAddr{IP: net.ParseIP("203.0.113.1")}))
+
+	// Client IDs without any policy are allowed, but not with a certificate
+	// pinned to another client.
+	assert.Nil(t, policy.VerifyLogin("ClientZ", certY.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientZ", certX.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientY", certX.Leaf, addr))
+
+	policy.RequirePinning = true
+
+	assert.Nil(t, policy.VerifyLogin("ClientX", certX.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientY", certY.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientZ", certY.Leaf, addr))
+	assert.NotNil(t, policy.VerifyLogin("ClientZ", nil, addr))
+}
+
+func TestServer_clientCertPolicy(t *testing.T) {
+	ca := newTestCA(t)
+	certX := ca.issue(t, 10, "ClientX")
+
+	policy := NewClientCertPolicy(ca.cert)
+	policy.PinCertificate("ClientX", fingerprint(certX.Leaf.Raw))
+
+	didStart := make(chan struct{})
+
+	srv := Server{
+		Addr: ":9891",
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{generateCertificate()},
+			ClientAuth:   tls.RequireAnyClientCert,
+		},
+		SessionConfig: SessionConfig{
+			IdleTimeout:      time.Minute,
+			SessionTimeout:   time.Minute,
+			ClientCertPolicy: policy,
+			Handler: func(s *Session, in []byte) ([]byte, error) {
+				return NewResponse(EppOk).WithTrID("ABC-12345", "54321-XYZ").Encode()
+			},
+			Greeting: func(s *Session) ([]byte, error) {
+				return []byte("hello"), nil
+			},
+		},
+		OnStarteds: []func(){
+			func() {
+				didStart <- struct{}{}
+			},
+		},
+	}
+
+	defer srv.Stop()
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil {
+			panic(err)
+		}
+	}()
+
+	<-didStart
+
+	login := func(clientID string) types.Response {
+		client := &Client{
+			TLSConfig: &tls.Config{
+				Certificates:       []tls.Certificate{certX},
+				InsecureSkipVerify: true,
+			},
+		}
+
+		_, err := client.Connect(":9891")
+		require.Nil(t, err)
+
+		defer client.Close()
+
+		command, err := NewCommand(types.Login{
+			ClientID: clientID,
+			Password: "secret",
+			Options:  types.LoginOptions{Version: "1.0", Language: "en"},
+			Services: types.LoginServices{ObjectURI: []string{types.NameSpaceDomain}},
+		}).WithClientTransactionID("ABC-12345").Encode()
+		require.Nil(t, err)
+
+		data, err := client.Send(command)
+		require.Nil(t, err)
+
+		response := types.Response{}
+		require.Nil(t, Decode(data, &response))
+
+		return response
+	}
+
+	assert.Equal(t, EppOk.Code(), login("ClientX").Result[0].Code)
+
+	// ClientY has no pinned certificates yet but the certificate of ClientX
+	// can't be used to log in as another client.
+	rejected := login("ClientY")
+	assert.Equal(t, EppAuthFailedBye.Code(), rejected.Result[0].Code)
+	assert.Equal(t, "ABC-12345", rejected.TransactionID.ClientTransactionID)
+
+	policy.PinCertificate("ClientY", "00")
+
+	assert.Equal(t, EppAuthFailedBye.Code(), login("ClientY").Result[0].Code)
+
+	policy.RequirePinning = true
+
+	assert.Equal(t, EppOk.Code(), login("ClientX").Result[0].Code)
+	assert.Equal(t, EppAuthFailedBye.Code(), login("ClientZ").Result[0].Code)
+
+	// A revoked status stapled to the certificate closes the session before
+	// the greeting.
+	revoked := certX
+	revoked.OCSPStaple = ca.ocsp(t, 10, true, time.Now().Add(time.Hour))
+
+	client := &Client{
+		TLSConfig: &tls.Config{
+			Certificates:       []tls.Certificate{revoked},
+			InsecureSkipVerify: true,
+		},
+	}
+
+	_, err := client.Connect(":9891")
+	assert.NotNil(t, err)
+}
@@ -52,13 +52,21 @@ type typeStruct struct {
 
 func main() {
 	var (
-		args  []string
-		help  bool
-		files = []string{}
+		args    []string
+		help    bool
+		files   = []string{}
+		xsdFile string
+		output  string
+		prefix  string
+		object  bool
 	)
 
 	flag.BoolVar(&help, "h", false, "Show this help text")
 	flag.BoolVar(&help, "help", false, "")
+	flag.StringVar(&xsdFile, "xsd", "", "Generate types from the XSD file instead of existing types")
+	flag.StringVar(&output, "o", "", "Output file when generating from XSD, defaults to types/<name>_xsd_auto_generated.go")
+	flag.StringVar(&prefix, "prefix", "", "Prefix for all generated types when generating from XSD, defaults to the schema name")
+	flag.BoolVar(&object, "object", false, "The XSD is an object mapping (like domain-1.0) and not a command extension")
 
 	flag.Parse()
 
@@ -68,6 +76,15 @@ func main() {
 		return
 	}
 
+	if xsdFile != "" {
+		if err := ProcessXSD(xsdFile, output, prefix, object); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+
+		return
+	}
+
 	args = flag.Args()
 	if len(args) == 0 {
 		args = []string{"./types/..."}
@@ -93,23 +110,26 @@ func main() {
 	}
 
 	for _, f := range files {
-		Process(f)
+		if err := Process(f); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
 	}
 
 	return
 }
 
-func Process(filename string) {
+func Process(filename string) error {
 	fileData, err := ioutil.ReadFile(filename)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	fset := token.NewFileSet()
 
 	file, err := parser.ParseFile(fset, filename, fileData, parser.ParseComments)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
 	typeStructs := typeData{
@@ -148,21 +168,36 @@ func Process(filename string) {
 
 			firstFieldInStruct := structType.Fields.List[0]
 
+			// Types without a name space in the tag is already namespace
+			// agnostic.
+			if firstFieldInStruct.Tag == nil || !strings.Contains(firstFieldInStruct.Tag.Value, " ") {
+				continue
+			}
+
 			typeStructs.Types = append(typeStructs.Types, typeStruct{
 				OriginalStructName: spec.Name.Name,
 				FieldName:          firstFieldInStruct.Names[0].Name,
-				FieldType:          firstFieldInStruct.Type.(*ast.Ident).Name,
+				FieldType:          fieldType(firstFieldInStruct.Type),
 				FieldTag:           firstFieldInStruct.Tag.Value,
 			})
 		}
 	}
 
-	createFile(typeStructs)
+	return createFile(typeStructs)
 }
 
-func createFile(data typeData) {
+// fieldType returns the name of the field type, prefixed with * for pointers.
+func fieldType(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return "*" + fieldType(star.X)
+	}
+
+	return expr.(*ast.Ident).Name
+}
+
+func createFile(data typeData) error {
 	if len(data.Types) < 1 {
-		return
+		return nil
 	}
 
 	for i, d := range data.Types {
@@ -177,12 +212,12 @@ func createFile(data typeData) {
 	buf := bytes.Buffer{}
 
 	if err := tmpl.Execute(&buf, data); err != nil {
-		panic(err)
+		return err
 	}
 
 	fileBytes, err := format.Source(buf.Bytes())
 	if err != nil {
-		panic(err)
+		return fmt.Errorf("%s: invalid generated code: %v", data.Filename, err)
 	}
 
 	dir, file := filepath.Split(data.Filename)
@@ -190,8 +225,13 @@ func createFile(data typeData) {
 	newFilename := fmt.Sprintf("%s_auto_generated.go", strings.Join(filenameParts[:len(filenameParts)-1], "."))
 	newFilepath := filepath.Join(dir, newFilename)
 
-	_ = ioutil.WriteFile(newFilepath, fileBytes, 0644)
+	if err := ioutil.WriteFile(newFilepath, fileBytes, 0644); err != nil {
+		return err
+	}
+
 	fmt.Printf("Generated file: %s\n", newFilename)
+
+	return nil
 }
 
 func expandGoWildcard(root string) []string {
@@ -218,9 +258,14 @@ func expandGoWildcard(root string) []string {
 
 func showHelp() {
 	helpText := `Usage: type-generator <file> [files...]
+       type-generator -xsd <file.xsd> [-o output.go] [-prefix Prefix] [-object]
 
 Will default to all files in ./types
 
+When passing an XSD file all types, enumerations and elements in the schema will
+be generated as Go types. All XSD files in the same directory is used to resolve
+imported namespaces.
+
 Flags:`
 
 	fmt.Println(helpText)
@@ -0,0 +1,814 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"aqwari.net/xml/xmltree"
+	"aqwari.net/xml/xsd"
+)
+
+const schemaNS = "http://www.w3.org/2001/XMLSchema"
+
+var xsdTemplateData = `package {{.Package}}
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in {{.Filename}}. All types, enumerations
+and elements from the schema with the target namespace {{.NameSpace}}
+is being added here.
+*/
+{{ if .Imports }}
+import (
+{{ range .Imports }}	"{{.}}"
+{{ end }})
+{{ end }}
+// Name space constant for the schema.
+const (
+	{{.NameSpaceConstant}} = "{{.NameSpace}}"
+)
+{{ range $e := .Enums }}
+// {{$e.Name}} represents available values for {{$e.XSDName}}.
+type {{$e.Name}} {{$e.Base}}
+
+// Constants representing the string value of {{$e.XSDName}}.
+const (
+{{ range $v := $e.Values }}	{{$v.Name}} {{$e.Name}} = "{{$v.Value}}"
+{{ end }})
+{{ end }}
+{{- range $t := .Wrappers }}
+// {{$t.Name}} implements {{$t.XSDName}} from {{$.Alias}}.
+type {{$t.Name}} struct {
+	{{$t.FieldName}} {{$t.FieldType}} ` + "`" + `xml:"{{$.NameSpace}} {{$t.Path}}"` + "`" + `
+}
+{{ end }}
+{{- range $s := .Structs }}
+// {{$s.Name}} represents the {{$s.XSDName}} type from {{$.Alias}}.
+type {{$s.Name}} struct {
+{{ range $f := $s.Fields }}	{{$f.Name}} {{$f.Type}} ` + "`" + `xml:"{{$f.Tag}}"` + "`" + `
+{{ end }}}
+{{ end }}`
+
+// xsdData holds everything that should be generated from one XSD.
+type xsdData struct {
+	Package           string
+	Filename          string
+	NameSpace         string
+	NameSpaceConstant string
+	Alias             string
+	Imports           []string
+	Enums             []xsdEnum
+	Wrappers          []xsdWrapper
+	Structs           []xsdStruct
+}
+
+type xsdEnum struct {
+	Name    string
+	XSDName string
+	Base    string
+	Values  []xsdEnumValue
+}
+
+type xsdEnumValue struct {
+	Name  string
+	Value string
+}
+
+type xsdWrapper struct {
+	Name      string
+	XSDName   string
+	FieldName string
+	FieldType string
+	Path      string
+}
+
+type xsdStruct struct {
+	Name    string
+	XSDName string
+	Fields  []xsdField
+}
+
+type xsdField struct {
+	Name string
+	Type string
+	Tag  string
+}
+
+// knownTypes maps types from the bundled schemas to the hand written types
+// already defined in the types package. Types from imported schemas not listed
+// here will be generated in the same file with the alias of their namespace as
+// prefix.
+var knownTypes = map[xml.Name]string{
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "authInfoType"}:     "AuthInfo",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "contactType"}:      "Contact",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "creDataType"}:      "DomainCreateData",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "infDataType"}:      "DomainInfoData",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "nsType"}:           "NameServer",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "periodType"}:       "Period",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "statusType"}:       "DomainStatus",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "statusValueType"}:  "DomainStatusType",
+	{Space: "urn:ietf:params:xml:ns:domain-1.0", Local: "trnDataType"}:      "DomainTransferData",
+	{Space: "urn:ietf:params:xml:ns:contact-1.0", Local: "authInfoType"}:    "AuthInfo",
+	{Space: "urn:ietf:params:xml:ns:contact-1.0", Local: "e164Type"}:        "E164Type",
+	{Space: "urn:ietf:params:xml:ns:contact-1.0", Local: "infDataType"}:     "ContactInfoData",
+	{Space: "urn:ietf:params:xml:ns:contact-1.0", Local: "postalInfoType"}:  "PostalInfo",
+	{Space: "urn:ietf:params:xml:ns:contact-1.0", Local: "statusType"}:      "ContactStatus",
+	{Space: "urn:ietf:params:xml:ns:contact-1.0", Local: "statusValueType"}: "ContactStatusType",
+	{Space: "urn:ietf:params:xml:ns:host-1.0", Local: "addrType"}:           "HostAddress",
+	{Space: "urn:ietf:params:xml:ns:host-1.0", Local: "infDataType"}:        "HostInfoData",
+	{Space: "urn:ietf:params:xml:ns:host-1.0", Local: "statusType"}:         "HostStatus",
+	{Space: "urn:ietf:params:xml:ns:epp-1.0", Local: "trIDType"}:            "TransactionID",
+	{Space: "urn:ietf:params:xml:ns:eppcom-1.0", Local: "pwAuthInfoType"}:   "string",
+	{Space: "urn:ietf:params:xml:ns:eppcom-1.0", Local: "extAuthInfoType"}:  "string",
+	{Space: "urn:ietf:params:xml:ns:eppcom-1.0", Local: "trStatusType"}:     "DomainTransferStatusType",
+}
+
+// abbreviations expands the short element names used in the EPP schemas to
+// the names used in the types package.
+var abbreviations = map[string]string{
+	"acDate":  "ActingDate",
+	"acID":    "ActingID",
+	"chg":     "Change",
+	"chkData": "CheckData",
+	"clID":    "ClientID",
+	"clTRID":  "ClientTransactionID",
+	"crDate":  "CreateDate",
+	"crID":    "CreateID",
+	"creData": "CreateData",
+	"exDate":  "ExpireDate",
+	"id":      "ID",
+	"infData": "InfoData",
+	"lang":    "Language",
+	"msg":     "Message",
+	"ns":      "NameServer",
+	"panData": "PendingActivationNotificationData",
+	"pw":      "Password",
+	"reDate":  "RequestingDate",
+	"reID":    "RequestingID",
+	"rem":     "Remove",
+	"renData": "RenewData",
+	"roid":    "ROID",
+	"s":       "Status",
+	"svTRID":  "ServerTransactionID",
+	"trDate":  "TransferDate",
+	"trnData": "TransferData",
+	"upData":  "UpdateData",
+	"upDate":  "UpdateDate",
+	"upID":    "UpdateID",
+}
+
+// xsdGenerator keeps track of the schemas and the Go types created while
+// generating a file.
+type xsdGenerator struct {
+	schemas  map[string]xsd.Schema
+	prefixes map[string]string
+	target   string
+	object   bool
+	imports  map[string]struct{}
+	enums    map[xml.Name]xsdEnum
+	structs  map[xml.Name]xsdStruct
+	queue    []xml.Name
+	choices  map[xml.Name]map[string]struct{}
+}
+
+// ProcessXSD generates Go types from the XSD file. All XSD files in the same
+// directory is used to resolve imported namespaces.
+func ProcessXSD(filename, output, prefix string, object bool) error {
+	dir := filepath.Dir(filename)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.xsd"))
+	if err != nil {
+		return err
+	}
+
+	docs := map[string][]byte{}
+	fileForNameSpace := map[string]string{}
+
+	for _, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return err
+		}
+
+		data, err = defaultAttributeTypes(data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", f, err)
+		}
+
+		root, err := xmltree.Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %v", f, err)
+		}
+
+		if ns := root.Attr("", "targetNamespace"); ns != "" {
+			fileForNameSpace[ns] = f
+		}
+
+		docs[f] = data
+	}
+
+	if _, ok := docs[filename]; !ok {
+		return fmt.Errorf("%s is not a XSD file", filename)
+	}
+
+	targetRoot, err := xmltree.Parse(docs[filename])
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+
+	target := targetRoot.Attr("", "targetNamespace")
+	if target == "" {
+		return fmt.Errorf("%s has no target namespace", filename)
+	}
+
+	// Collect the target schema and all (transitive) imports by namespace
+	// since the bundled schemas doesn't specify any schema location.
+	selected := [][]byte{}
+	seen := map[string]struct{}{}
+	pending := []string{filename}
+
+	for len(pending) > 0 {
+		f := pending[0]
+		pending = pending[1:]
+
+		if _, ok := seen[f]; ok {
+			continue
+		}
+
+		seen[f] = struct{}{}
+		selected = append(selected, docs[f])
+
+		refs, err := xsd.Imports(docs[f])
+		if err != nil {
+			return fmt.Errorf("%s: %v", f, err)
+		}
+
+		for _, ref := range refs {
+			if imported, ok := fileForNameSpace[ref.Namespace]; ok {
+				pending = append(pending, imported)
+			}
+		}
+	}
+
+	parsed, err := xsd.Parse(selected...)
+	if err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+
+	g := &xsdGenerator{
+		schemas:  map[string]xsd.Schema{},
+		prefixes: map[string]string{},
+		target:   target,
+		object:   object,
+		imports:  map[string]struct{}{},
+		enums:    map[xml.Name]xsdEnum{},
+		structs:  map[xml.Name]xsdStruct{},
+	}
+
+	for _, s := range parsed {
+		g.schemas[s.TargetNS] = s
+	}
+
+	// The xsd package doesn't tell if an element is a part of a choice so all
+	// elements in a choice is treated as optional.
+	g.choices = choiceElements(targetRoot, target)
+
+	alias := nameSpaceAlias(target)
+	if prefix == "" {
+		prefix = defaultPrefix(alias)
+	}
+
+	g.prefixes[target] = prefix
+
+	data := xsdData{
+		Package:           "types",
+		Filename:          filename,
+		NameSpace:         target,
+		NameSpaceConstant: "NameSpace" + prefix + nameSpaceVersion(target),
+		Alias:             filepath.Base(filename),
+	}
+
+	elementTypes := map[string]struct{}{}
+
+	// Each global element will get a wrapper type with the namespace, just
+	// like the hand written types.
+	for _, el := range targetRoot.Children {
+		if el.Name.Space != schemaNS || el.Name.Local != "element" {
+			continue
+		}
+
+		name := el.Attr("", "name")
+		typeName := xml.Name{Space: target, Local: name}
+
+		if ref := el.Attr("", "type"); ref != "" {
+			typeName = el.Resolve(ref)
+			elementTypes[name] = struct{}{}
+		}
+
+		data.Wrappers = append(data.Wrappers, g.wrapper(name, typeName))
+	}
+
+	// Generate all named types in the target schema, skipping the types the
+	// xsd package adds as aliases for global elements.
+	schema := g.schemas[target]
+	names := []xml.Name{}
+
+	for name := range schema.Types {
+		if _, ok := elementTypes[name.Local]; ok {
+			continue
+		}
+
+		// The xsd package adds a type for the schema itself.
+		if name.Local == "_self" {
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].Local < names[j].Local
+	})
+
+	for _, name := range names {
+		g.goType(schema.Types[name])
+	}
+
+	// Foreign types without a hand written version is added to the queue
+	// while generating.
+	for len(g.queue) > 0 {
+		name := g.queue[0]
+		g.queue = g.queue[1:]
+
+		g.generateStruct(g.schemas[name.Space].Types[name].(*xsd.ComplexType))
+	}
+
+	for _, e := range g.enums {
+		data.Enums = append(data.Enums, e)
+	}
+
+	for _, s := range g.structs {
+		data.Structs = append(data.Structs, s)
+	}
+
+	for i := range g.imports {
+		data.Imports = append(data.Imports, i)
+	}
+
+	sort.Slice(data.Enums, func(i, j int) bool { return data.Enums[i].Name < data.Enums[j].Name })
+	sort.Slice(data.Structs, func(i, j int) bool { return data.Structs[i].Name < data.Structs[j].Name })
+	sort.Strings(data.Imports)
+
+	tmpl := template.Must(template.New("").Parse(xsdTemplateData))
+	buf := bytes.Buffer{}
+
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	fileBytes, err := format.Source(buf.Bytes())
+	if err != nil {
+		return fmt.Errorf("%s: invalid generated code: %v", filename, err)
+	}
+
+	if output == "" {
+		output = filepath.Join("types", fmt.Sprintf("%s_xsd_auto_generated.go", alias))
+	}
+
+	if err := ioutil.WriteFile(output, fileBytes, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Generated file: %s\n", output)
+
+	return nil
+}
+
+// defaultAttributeTypes sets the type for attributes declared without a type
+// or an inline simple type, e.g. <attribute name="description"/> in fee-1.0.
+// Such attributes are of the type xs:anySimpleType which the xsd package can't
+// resolve so they're declared as xs:string instead, which is the Go type used
+// for simple types anyway.
+func defaultAttributeTypes(data []byte) ([]byte, error) {
+	root, err := xmltree.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	untyped := root.SearchFunc(func(el *xmltree.Element) bool {
+		if el.Name.Space != schemaNS || el.Name.Local != "attribute" {
+			return false
+		}
+
+		if el.Attr("", "name") == "" || el.Attr("", "type") != "" {
+			return false
+		}
+
+		return len(el.Search(schemaNS, "simpleType")) == 0
+	})
+
+	if len(untyped) == 0 {
+		return data, nil
+	}
+
+	for _, el := range untyped {
+		el.SetAttr("", "type", el.Prefix(xml.Name{Space: schemaNS, Local: "string"}))
+	}
+
+	return xmltree.Marshal(root), nil
+}
+
+// wrapper creates the type with the namespace in the tag for a global element.
+// Elements ending with Data is used in responses, all other elements are
+// commands found either directly in the command (object mappings) or in the
+// command extension.
+func (g *xsdGenerator) wrapper(name string, typeName xml.Name) xsdWrapper {
+	fieldName := exportedName(name)
+	prefix := g.prefixes[g.target]
+
+	w := xsdWrapper{
+		XSDName:   name,
+		FieldName: fieldName,
+		FieldType: g.goType(g.lookup(typeName)),
+		Path:      name,
+	}
+
+	switch {
+	case strings.HasSuffix(name, "Data") && g.object:
+		w.Name = fmt.Sprintf("%s%sType", prefix, fieldName)
+	case strings.HasSuffix(name, "Data"):
+		w.Name = fmt.Sprintf("%sExtension%sType", prefix, fieldName)
+	case g.object:
+		w.Name = fmt.Sprintf("%s%sType", prefix, fieldName)
+		w.Path = fmt.Sprintf("command>%s>%s", name, name)
+	default:
+		w.Name = fmt.Sprintf("%sExtension%sType", prefix, fieldName)
+		w.Path = fmt.Sprintf("command>extension>%s", name)
+	}
+
+	// Pointers to structs isn't needed for the wrapper types.
+	w.FieldType = strings.TrimPrefix(w.FieldType, "*")
+
+	return w
+}
+
+// lookup finds a type by name in any of the parsed schemas.
+func (g *xsdGenerator) lookup(name xml.Name) xsd.Type {
+	if name.Space == schemaNS {
+		if b, err := xsd.ParseBuiltin(name); err == nil {
+			return b
+		}
+	}
+
+	if s, ok := g.schemas[name.Space]; ok {
+		if t, ok := s.Types[name]; ok {
+			return t
+		}
+	}
+
+	return xsd.String
+}
+
+// goType returns the Go type to use for a XSD type. Enumerations and complex
+// types are generated if needed.
+func (g *xsdGenerator) goType(t xsd.Type) string {
+	if known, ok := knownTypes[xsd.XMLName(t)]; ok {
+		return known
+	}
+
+	switch t := t.(type) {
+	case xsd.Builtin:
+		return g.builtinType(t)
+	case *xsd.SimpleType:
+		if t.List {
+			return "[]string"
+		}
+
+		if len(t.Restriction.Enum) > 0 && !t.Anonymous {
+			return g.generateEnum(t)
+		}
+
+		if t.Base == nil {
+			return "string"
+		}
+
+		return g.goType(t.Base)
+	case *xsd.ComplexType:
+		if len(t.Elements) == 0 && len(t.Attributes) == 0 && !isSimpleContent(t) {
+			return "*EmptyTag"
+		}
+
+		name := g.structName(t.Name)
+
+		if _, ok := g.structs[t.Name]; !ok {
+			if t.Name.Space == g.target {
+				g.generateStruct(t)
+			} else {
+				g.structs[t.Name] = xsdStruct{Name: name}
+				g.queue = append(g.queue, t.Name)
+			}
+		}
+
+		return name
+	}
+
+	return "string"
+}
+
+func (g *xsdGenerator) builtinType(b xsd.Builtin) string {
+	switch b {
+	case xsd.Boolean:
+		return "bool"
+	case xsd.Int, xsd.Integer, xsd.Long, xsd.Short, xsd.Byte, xsd.NegativeInteger, xsd.NonPositiveInteger:
+		return "int"
+	case xsd.UnsignedInt, xsd.UnsignedLong, xsd.UnsignedShort, xsd.UnsignedByte, xsd.PositiveInteger, xsd.NonNegativeInteger:
+		return "uint"
+	case xsd.Decimal, xsd.Double, xsd.Float:
+		return "float64"
+	case xsd.DateTime:
+		g.imports["time"] = struct{}{}
+
+		return "time.Time"
+	case xsd.Date:
+		return "Date"
+	case xsd.AnyType:
+		return "*EmptyTag"
+	}
+
+	return "string"
+}
+
+func (g *xsdGenerator) generateEnum(t *xsd.SimpleType) string {
+	if e, ok := g.enums[t.Name]; ok {
+		return e.Name
+	}
+
+	name := g.prefixFor(t.Name.Space) + exportedName(t.Name.Local)
+	if !strings.HasSuffix(name, "Type") {
+		name += "Type"
+	}
+
+	// All values are added as quoted strings, even if the base is numeric.
+	e := xsdEnum{
+		Name:    name,
+		XSDName: t.Name.Local,
+		Base:    "string",
+	}
+
+	valuePrefix := strings.TrimSuffix(strings.TrimSuffix(name, "Type"), "Value")
+
+	for _, v := range t.Restriction.Enum {
+		e.Values = append(e.Values, xsdEnumValue{
+			Name:  valuePrefix + exportedName(v),
+			Value: v,
+		})
+	}
+
+	g.enums[t.Name] = e
+
+	return name
+}
+
+func (g *xsdGenerator) generateStruct(t *xsd.ComplexType) {
+	s := xsdStruct{
+		Name:    g.structName(t.Name),
+		XSDName: t.Name.Local,
+	}
+
+	// Add the struct before resolving the fields to support recursive types.
+	g.structs[t.Name] = s
+
+	fieldNames := map[string]int{}
+	addField := func(f xsdField) {
+		if n, ok := fieldNames[f.Name]; ok {
+			fieldNames[f.Name] = n + 1
+			f.Name = fmt.Sprintf("%s%d", f.Name, n+1)
+		} else {
+			fieldNames[f.Name] = 1
+		}
+
+		s.Fields = append(s.Fields, f)
+	}
+
+	if isSimpleContent(t) {
+		addField(xsdField{
+			Name: "Value",
+			Type: strings.TrimPrefix(g.goType(t.Base), "*"),
+			Tag:  ",chardata",
+		})
+	}
+
+	elements, attributes := t.Elements, t.Attributes
+
+	if base, ok := t.Base.(*xsd.ComplexType); ok && t.Extends {
+		elements = append(append([]xsd.Element{}, base.Elements...), elements...)
+		attributes = append(append([]xsd.Attribute{}, base.Attributes...), attributes...)
+	}
+
+	seen := map[xml.Name]struct{}{}
+
+	for _, el := range elements {
+		if _, ok := seen[el.Name]; ok {
+			continue
+		}
+
+		seen[el.Name] = struct{}{}
+
+		if el.Wildcard {
+			addField(xsdField{Name: "InnerXML", Type: "string", Tag: ",innerxml"})
+
+			continue
+		}
+
+		fieldType := g.goType(el.Type)
+		tag := el.Name.Local
+		_, inChoice := g.choices[t.Name][el.Name.Local]
+
+		switch {
+		case el.Plural:
+			fieldType = "[]" + strings.TrimPrefix(fieldType, "*")
+			tag += ",omitempty"
+		case el.Optional, inChoice:
+			if g.isStruct(fieldType) {
+				fieldType = "*" + fieldType
+			}
+
+			tag += ",omitempty"
+		}
+
+		addField(xsdField{
+			Name: exportedName(el.Name.Local),
+			Type: fieldType,
+			Tag:  tag,
+		})
+	}
+
+	for _, attr := range attributes {
+		tag := attr.Name.Local + ",attr"
+		if attr.Optional {
+			tag += ",omitempty"
+		}
+
+		addField(xsdField{
+			Name: exportedName(attr.Name.Local),
+			Type: strings.TrimPrefix(g.goType(attr.Type), "*"),
+			Tag:  tag,
+		})
+	}
+
+	g.structs[t.Name] = s
+}
+
+// choiceElements returns the name of all elements within a choice for each
+// named complex type in the schema.
+func choiceElements(root *xmltree.Element, ns string) map[xml.Name]map[string]struct{} {
+	result := map[xml.Name]map[string]struct{}{}
+
+	for _, t := range root.Search(schemaNS, "complexType") {
+		name := t.Attr("", "name")
+		if name == "" {
+			continue
+		}
+
+		for _, choice := range t.Search(schemaNS, "choice") {
+			for _, el := range choice.Search(schemaNS, "element") {
+				typeName := xml.Name{Space: ns, Local: name}
+				if _, ok := result[typeName]; !ok {
+					result[typeName] = map[string]struct{}{}
+				}
+
+				elName := el.Attr("", "name")
+				if elName == "" {
+					elName = el.Attr("", "ref")
+					if i := strings.Index(elName, ":"); i >= 0 {
+						elName = elName[i+1:]
+					}
+				}
+
+				result[typeName][elName] = struct{}{}
+			}
+		}
+	}
+
+	return result
+}
+
+// structName returns the Go name for a complex type. The suffix Type is
+// removed since single field structs ending with Type is treated as wrappers.
+func (g *xsdGenerator) structName(name xml.Name) string {
+	return g.prefixFor(name.Space) + exportedName(strings.TrimSuffix(name.Local, "Type"))
+}
+
+func (g *xsdGenerator) prefixFor(ns string) string {
+	if prefix, ok := g.prefixes[ns]; ok {
+		return prefix
+	}
+
+	// Types from imported schemas is prefixed with the target prefix as well
+	// to not collide with the hand written types.
+	prefix := g.prefixes[g.target] + defaultPrefix(nameSpaceAlias(ns))
+	g.prefixes[ns] = prefix
+
+	return prefix
+}
+
+// isSimpleContent returns true if the complex type only holds character data
+// (and attributes), e.g. <domain:period unit="y">1</domain:period>.
+func isSimpleContent(t *xsd.ComplexType) bool {
+	if len(t.Elements) > 0 {
+		return false
+	}
+
+	switch b := t.Base.(type) {
+	case *xsd.SimpleType:
+		return true
+	case xsd.Builtin:
+		return b != xsd.AnyType
+	case *xsd.ComplexType:
+		return isSimpleContent(b)
+	}
+
+	return false
+}
+
+// isStruct returns true if the Go type is a struct which should be a pointer
+// when the element is optional.
+func (g *xsdGenerator) isStruct(goType string) bool {
+	switch goType {
+	case "string", "bool", "int", "uint", "float64", "DomainStatusType", "ContactStatusType", "DomainTransferStatusType":
+		return false
+	}
+
+	if strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") {
+		return false
+	}
+
+	for _, e := range g.enums {
+		if e.Name == goType {
+			return false
+		}
+	}
+
+	return true
+}
+
+// nameSpaceAlias returns the name of the schema in the namespace, e.g. rgp for
+// urn:ietf:params:xml:ns:rgp-1.0.
+func nameSpaceAlias(ns string) string {
+	name := ns[strings.LastIndexAny(ns, ":/")+1:]
+
+	if i := strings.LastIndex(name, "-"); i > 0 {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// nameSpaceVersion returns the version digits of the namespace, e.g. 10 for
+// urn:ietf:params:xml:ns:rgp-1.0.
+func nameSpaceVersion(ns string) string {
+	i := strings.LastIndex(ns, "-")
+	if i < 0 {
+		return ""
+	}
+
+	return strings.Replace(ns[i+1:], ".", "", -1)
+}
+
+// defaultPrefix uses short aliases as acronyms (RGP, IIS) and longer as
+// camel case (Launch, LoginSec).
+func defaultPrefix(alias string) string {
+	if len(alias) <= 4 && strings.ToLower(alias) == alias {
+		return strings.ToUpper(alias)
+	}
+
+	return exportedName(alias)
+}
+
+// exportedName converts a XML name to an exported Go name.
+func exportedName(name string) string {
+	if expanded, ok := abbreviations[name]; ok {
+		return expanded
+	}
+
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for i, p := range parts {
+		r := []rune(p)
+		r[0] = unicode.ToUpper(r[0])
+		parts[i] = string(r)
+	}
+
+	return strings.Join(parts, "")
+}
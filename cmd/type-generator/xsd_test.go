@@ -0,0 +1,58 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessXSD(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "..", "xml", "*.xsd"))
+	require.Nil(t, err)
+	require.NotEmpty(t, files)
+
+	for _, f := range files {
+		// The index only imports the other schemas.
+		if filepath.Base(f) == "index.xsd" {
+			continue
+		}
+
+		t.Run(filepath.Base(f), func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "generated.go")
+
+			require.Nil(t, ProcessXSD(f, output, "", false))
+
+			_, err := parser.ParseFile(token.NewFileSet(), output, nil, parser.AllErrors)
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestProcessXSD_errors(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "generated.go")
+	index := filepath.Join("..", "..", "xml", "index.xsd")
+
+	err := ProcessXSD(index, output, "", false)
+	require.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "no target namespace"), err.Error())
+
+	assert.NotNil(t, ProcessXSD(filepath.Join("..", "..", "xml", "missing.xsd"), output, "", false))
+}
+
+func TestDefaultAttributeTypes(t *testing.T) {
+	data, err := defaultAttributeTypes([]byte(`<schema xmlns="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:example">
+  <complexType name="creditType">
+    <attribute name="description"/>
+    <attribute name="lang" type="language"/>
+  </complexType>
+</schema>`))
+	require.Nil(t, err)
+
+	assert.Contains(t, string(data), `name="description" type="string"`)
+	assert.Contains(t, string(data), `name="lang" type="language"`)
+}
@@ -1,28 +1,367 @@
 package epp
 
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// 설정 파일의 값을 덮어쓰는 환경 변수입니다.
+const (
+	EnvEnvironment    = "EPP_ENV"
+	EnvHost           = "EPP_HOST"
+	EnvPort           = "EPP_PORT"
+	EnvTLSCert        = "EPP_TLS_CERT"
+	EnvTLSKey         = "EPP_TLS_KEY"
+	EnvTLSCA          = "EPP_TLS_CA"
+	EnvTLSReload      = "EPP_TLS_RELOAD_INTERVAL"
+	EnvIdleTimeout    = "EPP_IDLE_TIMEOUT"
+	EnvSessionTimeout = "EPP_SESSION_TIMEOUT"
+	EnvMaxFrameSize   = "EPP_MAX_FRAME_SIZE"
+	EnvXSD            = "EPP_XSD"
+	EnvExtensions     = "EPP_EXTENSIONS"
+)
+
+// 데이터베이스 설정을 선택하는 환경입니다.
+const (
+	EnvironmentProduction  = "production"
+	EnvironmentDevelopment = "development"
+)
+
+// 설정되지 않은 값에 사용하는 기본값입니다. 포트는 EPP 기본 포트입니다. (RFC5734 2)
+const (
+	defaultPort           = 700
+	defaultIdleTimeout    = 5 * time.Minute
+	defaultSessionTimeout = 10 * time.Minute
+)
+
 type Config struct {
-        Database DatabaseConfig `yaml:"database"`
-        Server   ServerConfig   `yaml:"server"`
+	// 사용할 데이터베이스 설정의 환경입니다. 설정되지 않으면 production 을 사용합니다.
+	Environment string         `yaml:"environment"`
+	Database    DatabaseConfig `yaml:"database"`
+	Server      ServerConfig   `yaml:"server"`
 }
 
 type DatabaseConfig struct {
-        Production  DatabaseEnv `yaml:"production"`
-        Development DatabaseEnv `yaml:"development"`
+	Production  DatabaseEnv `yaml:"production"`
+	Development DatabaseEnv `yaml:"development"`
 }
 
 type DatabaseEnv struct {
-        Oracle DatabaseInfo `yaml:"oracle"`
+	Oracle DatabaseInfo `yaml:"oracle"`
 }
 
 type DatabaseInfo struct {
-        Type     string `yaml:"type"`
-        User     string `yaml:"user"`
-        Password string `yaml:"password"`
-        Host     string `yaml:"host"`
-        Port     int    `yaml:"port"`
-        Database string `yaml:"database"`
+	Type     string `yaml:"type"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Database string `yaml:"database"`
 }
 
 type ServerConfig struct {
-        Port int `yaml:"port"`
+	Host string    `yaml:"host"`
+	Port int       `yaml:"port"`
+	TLS  TLSConfig `yaml:"tls"`
+
+	// 세션의 유휴 제한시간과 최대 지속 시간입니다. 5m 과 같은 형식으로 설정하며
+	// 설정되지 않으면 5분, 10분을 사용합니다.
+	IdleTimeout    time.Duration `yaml:"idle_timeout"`
+	SessionTimeout time.Duration `yaml:"session_timeout"`
+
+	// 헤더를 포함한 메시지의 최대 크기입니다. 0 이면 제한하지 않습니다.
+	MaxFrameSize int `yaml:"max_frame_size"`
+
+	// 메시지를 검증할 때 사용하는 최상위 XSD 파일입니다. 설정되지 않으면 검증하지 않습니다.
+	XSD string `yaml:"xsd"`
+
+	// 서버가 지원하는 확장의 네임스페이스입니다. 별칭이 등록된 네임스페이스만 사용할 수 있습니다.
+	Extensions []string `yaml:"extensions"`
+}
+
+// 서버 인증서와 클라이언트 인증서를 검증할 CA 파일의 경로입니다.
+// CA 가 설정되면 클라이언트 인증서를 CA 로 검증합니다.
+type TLSConfig struct {
+	Cert string `yaml:"cert"`
+	Key  string `yaml:"key"`
+	CA   string `yaml:"ca"`
+
+	// 파일이 변경되었는지 확인하는 주기입니다. 0 이면 SIGHUP 을 받았을 때만 다시 불러옵니다.
+	ReloadInterval time.Duration `yaml:"reload_interval"`
+}
+
+// 설정의 잘못된 모든 값을 가진 오류입니다.
+type ConfigError struct {
+	Fields []string
+}
+
+// 오류를 문자열로 반환합니다.
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("invalid configuration: %s", strings.Join(e.Fields, "; "))
+}
+
+// 잘못된 값이 없으면 nil 을, 있으면 항상 같은 순서로 정렬된 오류를 반환합니다.
+func (e *ConfigError) sorted() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	sort.Strings(e.Fields)
+
+	return e
+}
+
+func (e *ConfigError) add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, fmt.Sprintf("%s: %s", field, fmt.Sprintf(format, args...)))
+}
+
+// 설정 파일을 읽은 후 환경 변수로 값을 덮어쓰고 검증합니다. 설정 파일의 상대 경로는
+// 설정 파일이 있는 디렉토리를 기준으로 합니다.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read configuration")
+	}
+
+	cfg := &Config{}
+
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, errors.Wrap(err, "could not parse configuration")
+	}
+
+	dir := filepath.Dir(path)
+
+	for _, p := range []*string{&cfg.Server.TLS.Cert, &cfg.Server.TLS.Key, &cfg.Server.TLS.CA, &cfg.Server.XSD} {
+		if *p != "" && !filepath.IsAbs(*p) {
+			*p = filepath.Join(dir, *p)
+		}
+	}
+
+	cfgErr := &ConfigError{}
+
+	cfg.applyEnv(os.LookupEnv, cfgErr)
+	cfg.validate(cfgErr)
+
+	if err := cfgErr.sorted(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// 환경 변수로 설정을 덮어씁니다. 숫자나 시간으로 변환할 수 없는 값은 오류에 추가합니다.
+func (c *Config) applyEnv(lookup func(string) (string, bool), cfgErr *ConfigError) {
+	for env, target := range map[string]*string{
+		EnvEnvironment: &c.Environment,
+		EnvHost:        &c.Server.Host,
+		EnvTLSCert:     &c.Server.TLS.Cert,
+		EnvTLSKey:      &c.Server.TLS.Key,
+		EnvTLSCA:       &c.Server.TLS.CA,
+		EnvXSD:         &c.Server.XSD,
+	} {
+		if v, ok := lookup(env); ok {
+			*target = v
+		}
+	}
+
+	for env, target := range map[string]*int{
+		EnvPort:         &c.Server.Port,
+		EnvMaxFrameSize: &c.Server.MaxFrameSize,
+	} {
+		if v, ok := lookup(env); ok {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				cfgErr.add(env, "'%s' is not a number", v)
+				continue
+			}
+
+			*target = i
+		}
+	}
+
+	for env, target := range map[string]*time.Duration{
+		EnvIdleTimeout:    &c.Server.IdleTimeout,
+		EnvSessionTimeout: &c.Server.SessionTimeout,
+		EnvTLSReload:      &c.Server.TLS.ReloadInterval,
+	} {
+		if v, ok := lookup(env); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				cfgErr.add(env, "'%s' is not a duration", v)
+				continue
+			}
+
+			*target = d
+		}
+	}
+
+	if v, ok := lookup(EnvExtensions); ok {
+		c.Server.Extensions = nil
+
+		for _, ns := range strings.Split(v, ",") {
+			if ns = strings.TrimSpace(ns); ns != "" {
+				c.Server.Extensions = append(c.Server.Extensions, ns)
+			}
+		}
+	}
+}
+
+// 설정을 검증합니다. 잘못된 값이 있으면 잘못된 모든 값을 가진 *ConfigError 를 반환합니다.
+func (c *Config) Validate() error {
+	cfgErr := &ConfigError{}
+
+	c.validate(cfgErr)
+
+	return cfgErr.sorted()
+}
+
+func (c *Config) validate(cfgErr *ConfigError) {
+	switch c.Environment {
+	case "", EnvironmentProduction, EnvironmentDevelopment:
+	default:
+		cfgErr.add("environment", "must be %s or %s", EnvironmentProduction, EnvironmentDevelopment)
+	}
+
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		cfgErr.add("server.port", "%d is not a valid port", c.Server.Port)
+	}
+
+	tlsCfg := c.Server.TLS
+
+	if (tlsCfg.Cert == "") != (tlsCfg.Key == "") {
+		cfgErr.add("server.tls", "both cert and key must be set")
+	}
+
+	if tlsCfg.CA != "" && tlsCfg.Cert == "" {
+		cfgErr.add("server.tls.ca", "requires a server certificate")
+	}
+
+	for field, path := range map[string]string{
+		"server.tls.cert": tlsCfg.Cert,
+		"server.tls.key":  tlsCfg.Key,
+		"server.tls.ca":   tlsCfg.CA,
+		"server.xsd":      c.Server.XSD,
+	} {
+		if path == "" {
+			continue
+		}
+
+		if _, err := os.Stat(path); err != nil {
+			cfgErr.add(field, "%s does not exist", path)
+		}
+	}
+
+	if c.Server.IdleTimeout < 0 {
+		cfgErr.add("server.idle_timeout", "must not be negative")
+	}
+
+	if c.Server.SessionTimeout < 0 {
+		cfgErr.add("server.session_timeout", "must not be negative")
+	}
+
+	if tlsCfg.ReloadInterval < 0 {
+		cfgErr.add("server.tls.reload_interval", "must not be negative")
+	}
+
+	// 헤더만 있는 메시지는 의미가 없으므로 헤더보다 커야 합니다.
+	if c.Server.MaxFrameSize < 0 || (c.Server.MaxFrameSize > 0 && c.Server.MaxFrameSize <= 4) {
+		cfgErr.add("server.max_frame_size", "%d is not a valid size", c.Server.MaxFrameSize)
+	}
+
+	for _, ns := range c.Server.Extensions {
+		if _, ok := aliasForNameSpace(ns); !ok {
+			cfgErr.add("server.extensions", "unknown namespace %s", ns)
+		}
+	}
+}
+
+// 선택된 환경의 데이터베이스 설정을 반환합니다.
+func (c *Config) DatabaseEnv() DatabaseEnv {
+	if c.Environment == EnvironmentDevelopment {
+		return c.Database.Development
+	}
+
+	return c.Database.Production
+}
+
+// 서버가 수신할 주소를 반환합니다.
+func (c *Config) Addr() string {
+	port := c.Server.Port
+	if port == 0 {
+		port = defaultPort
+	}
+
+	return fmt.Sprintf("%s:%d", c.Server.Host, port)
+}
+
+// 설정으로 서버를 생성합니다. Greeting, Handler 와 같은 함수는 전달받은 세션 설정의
+// 값을 사용하며 제한시간, 최대 메시지 크기와 Validator 는 설정으로 덮어씁니다. 확장이
+// 설정되어 있으면 세션 설정의 Extensions 도 덮어씁니다. 클라이언트 인증서를 검증할
+// CA 가 없으면 ClientCertPolicy 의 RequirePinning 으로 등록된 인증서만 허용해야
+// 합니다.
+func NewServerFromConfig(cfg *Config, sessionConfig SessionConfig) (*Server, error) {
+	sessionConfig.IdleTimeout = cfg.Server.IdleTimeout
+	if sessionConfig.IdleTimeout == 0 {
+		sessionConfig.IdleTimeout = defaultIdleTimeout
+	}
+
+	sessionConfig.SessionTimeout = cfg.Server.SessionTimeout
+	if sessionConfig.SessionTimeout == 0 {
+		sessionConfig.SessionTimeout = defaultSessionTimeout
+	}
+
+	sessionConfig.MaxFrameSize = cfg.Server.MaxFrameSize
+
+	if len(cfg.Server.Extensions) > 0 {
+		sessionConfig.Extensions = cfg.Server.Extensions
+	}
+
+	if cfg.Server.XSD != "" {
+		validator, err := NewValidator(cfg.Server.XSD)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not load XSD")
+		}
+
+		sessionConfig.Validator = validator
+	}
+
+	server := &Server{
+		Addr:          cfg.Addr(),
+		SessionConfig: sessionConfig,
+	}
+
+	if cfg.Server.TLS.Cert == "" {
+		return server, nil
+	}
+
+	// CA 가 없으면 어떤 인증서도 검증되지 않으므로 등록된 지문으로만 클라이언트를
+	// 확인할 수 있습니다.
+	policy := sessionConfig.ClientCertPolicy
+	if cfg.Server.TLS.CA == "" && (policy == nil || !policy.RequirePinning) {
+		return nil, errors.New("server.tls.ca is required unless ClientCertPolicy.RequirePinning is set")
+	}
+
+	certificates, err := NewCertificateReloader(cfg.Server.TLS.Cert, cfg.Server.TLS.Key, cfg.Server.TLS.CA)
+	if err != nil {
+		return nil, err
+	}
+
+	certificates.Interval = cfg.Server.TLS.ReloadInterval
+
+	server.Certificates = certificates
+	server.TLSConfig = &tls.Config{
+		ClientAuth: tls.RequireAnyClientCert,
+	}
+
+	return server, nil
 }
@@ -0,0 +1,150 @@
+package epp
+
+import (
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 설정 파일과 인증서를 임시 디렉토리에 작성하고 디렉토리를 반환합니다.
+func writeTestConfig(t *testing.T, config string) string {
+	dir, err := ioutil.TempDir("", "epp-config")
+	require.Nil(t, err)
+
+	writeCertificate(t, dir, "server")
+	require.Nil(t, ioutil.WriteFile(filepath.Join(dir, "config.yml"), []byte(config), 0600))
+
+	return dir
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := writeTestConfig(t, `
+environment: development
+database:
+  development:
+    oracle:
+      type: oracle
+      host: 127.0.0.1
+      port: 1521
+server:
+  port: 9091
+  tls:
+    cert: server.crt
+    key: server.key
+    ca: server.crt
+  idle_timeout: 1m
+  max_frame_size: 65536
+  extensions:
+    - urn:ietf:params:xml:ns:secDNS-1.1
+`)
+	defer os.RemoveAll(dir)
+
+	cfg, err := LoadConfig(filepath.Join(dir, "config.yml"))
+	require.Nil(t, err)
+
+	assert.Equal(t, ":9091", cfg.Addr())
+	assert.Equal(t, filepath.Join(dir, "server.crt"), cfg.Server.TLS.Cert)
+	assert.Equal(t, 1521, cfg.DatabaseEnv().Oracle.Port)
+	assert.Equal(t, []string{types.NameSpaceDNSSEC11}, cfg.Server.Extensions)
+
+	server, err := NewServerFromConfig(cfg, SessionConfig{})
+	require.Nil(t, err)
+
+	assert.Equal(t, time.Minute, server.SessionConfig.IdleTimeout)
+	assert.Equal(t, defaultSessionTimeout, server.SessionConfig.SessionTimeout)
+	assert.Equal(t, 65536, server.SessionConfig.MaxFrameSize)
+	require.NotNil(t, server.Certificates)
+
+	tlsConfig, err := server.Certificates.TLSConfig(server.TLSConfig).GetConfigForClient(nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, tls.RequireAndVerifyClientCert, tlsConfig.ClientAuth)
+	assert.NotNil(t, tlsConfig.ClientCAs)
+	assert.Equal(t, []string{types.NameSpaceDNSSEC11}, server.SessionConfig.Extensions)
+
+	// Without a CA client certificates can only be accepted when they are pinned.
+	cfg.Server.TLS.CA = ""
+
+	_, err = NewServerFromConfig(cfg, SessionConfig{})
+	assert.NotNil(t, err)
+
+	policy := NewClientCertPolicy()
+	policy.RequirePinning = true
+
+	server, err = NewServerFromConfig(cfg, SessionConfig{ClientCertPolicy: policy})
+	require.Nil(t, err)
+
+	tlsConfig, err = server.Certificates.TLSConfig(server.TLSConfig).GetConfigForClient(nil)
+	require.Nil(t, err)
+
+	assert.Equal(t, tls.RequireAnyClientCert, tlsConfig.ClientAuth)
+}
+
+func TestConfig_applyEnv(t *testing.T) {
+	env := map[string]string{
+		EnvEnvironment:  EnvironmentDevelopment,
+		EnvHost:         "127.0.0.1",
+		EnvPort:         "700",
+		EnvIdleTimeout:  "30s",
+		EnvMaxFrameSize: "many",
+		EnvExtensions:   "urn:ietf:params:xml:ns:secDNS-1.1, urn:se:iis:xml:epp:iis-1.2",
+	}
+
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	cfg := &Config{Server: ServerConfig{Port: 9091, MaxFrameSize: 1024, Extensions: []string{"urn:example"}}}
+	cfgErr := &ConfigError{}
+
+	cfg.applyEnv(lookup, cfgErr)
+
+	assert.Equal(t, []string{"EPP_MAX_FRAME_SIZE: 'many' is not a number"}, cfgErr.Fields)
+	assert.Equal(t, EnvironmentDevelopment, cfg.Environment)
+	assert.Equal(t, "127.0.0.1:700", cfg.Addr())
+	assert.Equal(t, 30*time.Second, cfg.Server.IdleTimeout)
+	assert.Equal(t, 1024, cfg.Server.MaxFrameSize)
+	assert.Equal(t, []string{types.NameSpaceDNSSEC11, types.NameSpaceIIS12}, cfg.Server.Extensions)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.Nil(t, (&Config{}).Validate())
+
+	cfg := &Config{
+		Environment: "staging",
+		Server: ServerConfig{
+			Port:           70000,
+			TLS:            TLSConfig{Cert: "missing.crt", CA: "missing-ca.crt"},
+			IdleTimeout:    -time.Second,
+			SessionTimeout: -time.Second,
+			MaxFrameSize:   4,
+			Extensions:     []string{"urn:example"},
+		},
+	}
+
+	err := cfg.Validate()
+	require.NotNil(t, err)
+
+	cfgErr, ok := err.(*ConfigError)
+	require.True(t, ok)
+
+	assert.Equal(t, []string{
+		"environment: must be production or development",
+		"server.extensions: unknown namespace urn:example",
+		"server.idle_timeout: must not be negative",
+		"server.max_frame_size: 4 is not a valid size",
+		"server.port: 70000 is not a valid port",
+		"server.session_timeout: must not be negative",
+		"server.tls.ca: missing-ca.crt does not exist",
+		"server.tls.cert: missing.crt does not exist",
+		"server.tls: both cert and key must be set",
+	}, cfgErr.Fields)
+}
@@ -0,0 +1,359 @@
+// dnssec 패키지는 secDNS-1.1 확장(RFC 5910)의 DS 와 키 데이터를 검증하고, 키
+// 데이터로 DS 레코드를 계산합니다. 알고리즘과 다이제스트 타입은 IANA 레지스트리의
+// 번호를 사용하며, 검증에 실패하면 응답할 EPP 결과 코드를 가진 오류를 반환합니다.
+//
+//	ds, err := dnssec.ComputeDS("example.se", key, dnssec.DigestSHA256)
+//	if err != nil {
+//	    return err
+//	}
+//
+//	err = dnssec.DSDataInterface.ValidateCreate("example.se", create)
+package dnssec
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+)
+
+// DNSSEC 알고리즘 번호입니다. (IANA DNS Security Algorithm Numbers)
+const (
+	AlgorithmRSAMD5           uint = 1
+	AlgorithmDH               uint = 2
+	AlgorithmDSA              uint = 3
+	AlgorithmRSASHA1          uint = 5
+	AlgorithmDSANSEC3SHA1     uint = 6
+	AlgorithmRSASHA1NSEC3SHA1 uint = 7
+	AlgorithmRSASHA256        uint = 8
+	AlgorithmRSASHA512        uint = 10
+	AlgorithmECCGOST          uint = 12
+	AlgorithmECDSAP256SHA256  uint = 13
+	AlgorithmECDSAP384SHA384  uint = 14
+	AlgorithmED25519          uint = 15
+	AlgorithmED448            uint = 16
+	AlgorithmIndirect         uint = 252
+	AlgorithmPrivateDNS       uint = 253
+	AlgorithmPrivateOID       uint = 254
+)
+
+// DS 다이제스트 타입입니다. (IANA Delegation Signer (DS) Resource Record Digest
+// Algorithms)
+const (
+	DigestSHA1   uint = 1
+	DigestSHA256 uint = 2
+	DigestGOST   uint = 3
+	DigestSHA384 uint = 4
+)
+
+// DNSKEY 플래그입니다. (RFC 4034 2.1.1, RFC 5011 7)
+const (
+	FlagZone   uint = 256
+	FlagRevoke uint = 128
+	FlagSEP    uint = 1
+)
+
+// DNSKEY 의 프로토콜 값은 항상 3 입니다. (RFC 4034 2.1.2)
+const Protocol uint = 3
+
+// 서명의 최대 유효 기간(maxSigLife)의 최댓값(초)입니다. 유효 기간은 1 이상이어야
+// 합니다. (RFC 5910 4.1)
+const MaxSignatureLife = 2147483647
+
+// 영역 서명에 사용할 수 있는 알고리즘입니다. IANA 레지스트리의 Zone Signing 이 Y 인
+// 알고리즘만 DS 와 DNSKEY 에 사용할 수 있습니다.
+var algorithms = map[uint]string{
+	AlgorithmDSA:              "DSA",
+	AlgorithmRSASHA1:          "RSASHA1",
+	AlgorithmDSANSEC3SHA1:     "DSA-NSEC3-SHA1",
+	AlgorithmRSASHA1NSEC3SHA1: "RSASHA1-NSEC3-SHA1",
+	AlgorithmRSASHA256:        "RSASHA256",
+	AlgorithmRSASHA512:        "RSASHA512",
+	AlgorithmECCGOST:          "ECC-GOST",
+	AlgorithmECDSAP256SHA256:  "ECDSAP256SHA256",
+	AlgorithmECDSAP384SHA384:  "ECDSAP384SHA384",
+	AlgorithmED25519:          "ED25519",
+	AlgorithmED448:            "ED448",
+	AlgorithmPrivateDNS:       "PRIVATEDNS",
+	AlgorithmPrivateOID:       "PRIVATEOID",
+}
+
+// 다이제스트 타입별 다이제스트의 바이트 길이입니다.
+var digestLengths = map[uint]int{
+	DigestSHA1:   sha1.Size,
+	DigestSHA256: sha256.Size,
+	DigestGOST:   32,
+	DigestSHA384: sha512.Size384,
+}
+
+// 다이제스트 타입별 해시 함수입니다. GOST R 34.11-94 는 표준 라이브러리에 없으므로
+// 검증만 하고 계산하지 않습니다.
+var digestHashes = map[uint]func() hash.Hash{
+	DigestSHA1:   sha1.New,
+	DigestSHA256: sha256.New,
+	DigestSHA384: sha512.New384,
+}
+
+// 검증에 실패한 이유와 클라이언트에게 응답할 EPP 결과 코드를 가진 오류입니다. 값의
+// 형식이 잘못되었으면 2005, 서버의 정책에 맞지 않으면 2306 을 사용합니다.
+type Error struct {
+	Code   epp.ResultCode
+	Reason string
+}
+
+// 오류를 문자열로 반환합니다.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code.Code(), e.Reason)
+}
+
+func syntaxError(format string, args ...interface{}) *Error {
+	return &Error{Code: epp.EppParamSyntaxError, Reason: fmt.Sprintf(format, args...)}
+}
+
+func policyError(format string, args ...interface{}) *Error {
+	return &Error{Code: epp.EppParamPolicyError, Reason: fmt.Sprintf(format, args...)}
+}
+
+// 알고리즘이 영역 서명에 사용할 수 있는 알고리즘인지 확인합니다.
+func ValidAlgorithm(algorithm uint) bool {
+	_, ok := algorithms[algorithm]
+	return ok
+}
+
+// 다이제스트 타입이 등록된 타입인지 확인합니다.
+func ValidDigestType(digestType uint) bool {
+	_, ok := digestLengths[digestType]
+	return ok
+}
+
+// 서명의 최대 유효 기간(초)을 검증합니다. 0 은 유효 기간이 없는 것으로 봅니다.
+func ValidateMaxSigLife(seconds int) error {
+	if seconds < 0 || seconds > MaxSignatureLife {
+		return syntaxError("maxSigLife must be between 1 and %d", MaxSignatureLife)
+	}
+
+	return nil
+}
+
+// 키 데이터의 플래그, 프로토콜, 알고리즘과 공개 키를 검증합니다. 영역 키 플래그가
+// 있어야 하고 SEP 와 REVOKE 외의 플래그는 사용할 수 없습니다.
+func ValidateKeyData(key types.DNSSECKeyData) error {
+	if key.Flags&FlagZone == 0 || key.Flags&^(FlagZone|FlagRevoke|FlagSEP) != 0 {
+		return syntaxError("invalid DNSKEY flags %d", key.Flags)
+	}
+
+	if key.Protocol != Protocol {
+		return syntaxError("invalid DNSKEY protocol %d", key.Protocol)
+	}
+
+	if !ValidAlgorithm(key.Algorithm) {
+		return policyError("unsupported DNSKEY algorithm %d", key.Algorithm)
+	}
+
+	if _, err := publicKey(key); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DS 데이터의 알고리즘, 다이제스트 타입과 다이제스트를 검증합니다. 키 데이터가 있으면
+// 키 데이터도 검증하고, 키 태그와 다이제스트가 키 데이터로 계산한 값과 같은지
+// 확인합니다. 다이제스트는 도메인 이름으로 계산하므로 DS 가 속한 도메인의 이름이
+// 필요합니다.
+func ValidateDS(name string, ds types.DNSSEC) error {
+	if !ValidAlgorithm(ds.Algorithm) {
+		return policyError("unsupported DS algorithm %d", ds.Algorithm)
+	}
+
+	length, ok := digestLengths[ds.DigestType]
+	if !ok {
+		return policyError("unsupported DS digest type %d", ds.DigestType)
+	}
+
+	digest, err := hex.DecodeString(ds.Digest)
+	if err != nil {
+		return syntaxError("DS digest must be hex encoded")
+	}
+
+	if len(digest) != length {
+		return syntaxError("DS digest type %d must be %d bytes", ds.DigestType, length)
+	}
+
+	if ds.KeyData == nil {
+		return nil
+	}
+
+	if err := ValidateKeyData(*ds.KeyData); err != nil {
+		return err
+	}
+
+	if ds.KeyData.Algorithm != ds.Algorithm {
+		return policyError("DS algorithm %d does not match DNSKEY algorithm %d", ds.Algorithm, ds.KeyData.Algorithm)
+	}
+
+	if err := VerifyKeyTag(ds); err != nil {
+		return err
+	}
+
+	// 계산할 수 없는 다이제스트 타입은 키 태그까지만 확인합니다.
+	if _, ok := digestHashes[ds.DigestType]; !ok {
+		return nil
+	}
+
+	computed, err := ComputeDS(name, *ds.KeyData, ds.DigestType)
+	if err != nil {
+		return err
+	}
+
+	if !strings.EqualFold(computed.Digest, ds.Digest) {
+		return policyError("DS digest does not match DNSKEY")
+	}
+
+	return nil
+}
+
+// DS 데이터의 키 태그가 키 데이터의 공개 키로 계산한 키 태그와 같은지 확인합니다.
+// 키 데이터가 없으면 확인하지 않습니다.
+func VerifyKeyTag(ds types.DNSSEC) error {
+	if ds.KeyData == nil {
+		return nil
+	}
+
+	keyTag, err := KeyTag(*ds.KeyData)
+	if err != nil {
+		return err
+	}
+
+	if keyTag != ds.KeyTag {
+		return policyError("DS key tag %d does not match DNSKEY key tag %d", ds.KeyTag, keyTag)
+	}
+
+	return nil
+}
+
+// 키 데이터의 키 태그를 계산합니다. (RFC 4034 부록 B)
+func KeyTag(key types.DNSSECKeyData) (uint, error) {
+	rdata, err := keyRData(key)
+	if err != nil {
+		return 0, err
+	}
+
+	// RSA/MD5 는 공개 키의 모듈러스에서 마지막 세 번째와 두 번째 바이트를 사용합니다.
+	if key.Algorithm == AlgorithmRSAMD5 {
+		if len(rdata) < 3 {
+			return 0, syntaxError("DNSKEY public key is too short")
+		}
+
+		return uint(rdata[len(rdata)-3])<<8 | uint(rdata[len(rdata)-2]), nil
+	}
+
+	var ac uint32
+
+	for i, b := range rdata {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+
+	ac += ac >> 16 & 0xFFFF
+
+	return uint(ac & 0xFFFF), nil
+}
+
+// 도메인 이름과 키 데이터로 주어진 다이제스트 타입의 DS 데이터를 계산합니다.
+// (RFC 4034 5.1.4) 다이제스트는 대문자 16진수로 반환합니다.
+func ComputeDS(name string, key types.DNSSECKeyData, digestType uint) (*types.DNSSEC, error) {
+	if err := ValidateKeyData(key); err != nil {
+		return nil, err
+	}
+
+	newHash, ok := digestHashes[digestType]
+	if !ok {
+		return nil, policyError("unsupported DS digest type %d", digestType)
+	}
+
+	owner, err := wireName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rdata, err := keyRData(key)
+	if err != nil {
+		return nil, err
+	}
+
+	keyTag, err := KeyTag(key)
+	if err != nil {
+		return nil, err
+	}
+
+	h := newHash()
+	h.Write(owner)
+	h.Write(rdata)
+
+	return &types.DNSSEC{
+		KeyTag:     keyTag,
+		Algorithm:  key.Algorithm,
+		DigestType: digestType,
+		Digest:     strings.ToUpper(hex.EncodeToString(h.Sum(nil))),
+	}, nil
+}
+
+// DNSKEY 의 RDATA 를 wire 형식으로 반환합니다.
+func keyRData(key types.DNSSECKeyData) ([]byte, error) {
+	pubKey, err := publicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	rdata := []byte{byte(key.Flags >> 8), byte(key.Flags), byte(key.Protocol), byte(key.Algorithm)}
+
+	return append(rdata, pubKey...), nil
+}
+
+// base64 로 인코딩된 공개 키를 반환합니다. base64Binary 는 공백을 허용하므로 공백을
+// 제거한 후 디코딩합니다.
+func publicKey(key types.DNSSECKeyData) ([]byte, error) {
+	encoded := strings.Join(strings.Fields(key.PublicKey), "")
+
+	pubKey, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(pubKey) == 0 {
+		return nil, syntaxError("DNSKEY public key must be base64 encoded")
+	}
+
+	return pubKey, nil
+}
+
+// 도메인 이름을 소문자로 바꾼 wire 형식으로 반환합니다. (RFC 4034 6.2)
+func wireName(name string) ([]byte, error) {
+	name = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+
+	var wire []byte
+
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if label == "" || len(label) > 63 {
+				return nil, syntaxError("invalid domain name %s", name)
+			}
+
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+
+	if len(wire)+1 > 255 {
+		return nil, syntaxError("invalid domain name %s", name)
+	}
+
+	return append(wire, 0), nil
+}
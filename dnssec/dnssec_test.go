@@ -0,0 +1,306 @@
+package dnssec
+
+import (
+	"testing"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RFC 4034 5.4 과 RFC 4509 의 예제 키입니다.
+var rsaKey = types.DNSSECKeyData{
+	Flags:     256,
+	Protocol:  3,
+	Algorithm: AlgorithmRSASHA1,
+	PublicKey: `AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/
+		2pHm822aJ5iI9BMzNXxeYCmZDRD99WYwYqUSdjMmmAphXdvx
+		egXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2wwjM9Xzc
+		nOf+EPbtG9DMBmADjFDc2w/rljwvFw==`,
+}
+
+// RFC 6605 6 의 예제 키입니다.
+var ecdsaKey = types.DNSSECKeyData{
+	Flags:     257,
+	Protocol:  3,
+	Algorithm: AlgorithmECDSAP384SHA384,
+	PublicKey: "xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40",
+}
+
+func assertCode(t *testing.T, code epp.ResultCode, err error) {
+	t.Helper()
+
+	e, ok := err.(*Error)
+	require.True(t, ok, "expected *Error, got %v", err)
+	assert.Equal(t, code, e.Code, e.Reason)
+}
+
+func TestComputeDS(t *testing.T) {
+	cases := []struct {
+		description string
+		name        string
+		key         types.DNSSECKeyData
+		digestType  uint
+		keyTag      uint
+		digest      string
+	}{
+		{
+			description: "SHA-1",
+			name:        "dskey.example.com",
+			key:         rsaKey,
+			digestType:  DigestSHA1,
+			keyTag:      60485,
+			digest:      "2BB183AF5F22588179A53B0A98631FAD1A292118",
+		},
+		{
+			description: "SHA-256 with trailing dot and upper case name",
+			name:        "DSKEY.example.com.",
+			key:         rsaKey,
+			digestType:  DigestSHA256,
+			keyTag:      60485,
+			digest:      "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A",
+		},
+		{
+			description: "SHA-384",
+			name:        "example.net",
+			key:         ecdsaKey,
+			digestType:  DigestSHA384,
+			keyTag:      10771,
+			digest:      "72D7B62976CE06438E9C0BF319013CF801F09ECC84B8D7E9495F27E305C6A9B0563A9B5F4D288405C3008A946DF983D6",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			ds, err := ComputeDS(tc.name, tc.key, tc.digestType)
+			require.Nil(t, err)
+
+			assert.Equal(t, tc.keyTag, ds.KeyTag)
+			assert.Equal(t, tc.key.Algorithm, ds.Algorithm)
+			assert.Equal(t, tc.digestType, ds.DigestType)
+			assert.Equal(t, tc.digest, ds.Digest)
+		})
+	}
+
+	_, err := ComputeDS("example.net", ecdsaKey, DigestGOST)
+	assertCode(t, epp.EppParamPolicyError, err)
+
+	_, err = ComputeDS("example..net", ecdsaKey, DigestSHA256)
+	assertCode(t, epp.EppParamSyntaxError, err)
+}
+
+func TestValidateKeyData(t *testing.T) {
+	assert.Nil(t, ValidateKeyData(rsaKey))
+	assert.Nil(t, ValidateKeyData(ecdsaKey))
+
+	cases := []struct {
+		description string
+		modify      func(k *types.DNSSECKeyData)
+		code        epp.ResultCode
+	}{
+		{
+			description: "missing zone flag",
+			modify:      func(k *types.DNSSECKeyData) { k.Flags = FlagSEP },
+			code:        epp.EppParamSyntaxError,
+		},
+		{
+			description: "unknown flag",
+			modify:      func(k *types.DNSSECKeyData) { k.Flags = FlagZone | 2 },
+			code:        epp.EppParamSyntaxError,
+		},
+		{
+			description: "invalid protocol",
+			modify:      func(k *types.DNSSECKeyData) { k.Protocol = 2 },
+			code:        epp.EppParamSyntaxError,
+		},
+		{
+			description: "deprecated algorithm",
+			modify:      func(k *types.DNSSECKeyData) { k.Algorithm = AlgorithmRSAMD5 },
+			code:        epp.EppParamPolicyError,
+		},
+		{
+			description: "unassigned algorithm",
+			modify:      func(k *types.DNSSECKeyData) { k.Algorithm = 200 },
+			code:        epp.EppParamPolicyError,
+		},
+		{
+			description: "invalid public key",
+			modify:      func(k *types.DNSSECKeyData) { k.PublicKey = "not base64!" },
+			code:        epp.EppParamSyntaxError,
+		},
+		{
+			description: "empty public key",
+			modify:      func(k *types.DNSSECKeyData) { k.PublicKey = " " },
+			code:        epp.EppParamSyntaxError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			key := ecdsaKey
+			tc.modify(&key)
+
+			assertCode(t, tc.code, ValidateKeyData(key))
+		})
+	}
+}
+
+func TestValidateDS(t *testing.T) {
+	valid := types.DNSSEC{
+		KeyTag:     60485,
+		Algorithm:  AlgorithmRSASHA1,
+		DigestType: DigestSHA256,
+		Digest:     "d4b7d520e7bb5f0f67674a0cceb1e3e0614b93c4f9e99b8383f6a1e4469da50a",
+	}
+
+	assert.Nil(t, ValidateDS("dskey.example.com", valid))
+
+	withKey := valid
+	withKey.KeyData = &rsaKey
+	assert.Nil(t, ValidateDS("dskey.example.com", withKey))
+
+	// GOST digests can't be computed but the length is still verified.
+	gost := withKey
+	gost.DigestType = DigestGOST
+	assert.Nil(t, ValidateDS("dskey.example.com", gost))
+
+	cases := []struct {
+		description string
+		name        string
+		modify      func(ds *types.DNSSEC)
+		code        epp.ResultCode
+	}{
+		{
+			description: "unsupported algorithm",
+			modify:      func(ds *types.DNSSEC) { ds.Algorithm = AlgorithmIndirect },
+			code:        epp.EppParamPolicyError,
+		},
+		{
+			description: "unknown digest type",
+			modify:      func(ds *types.DNSSEC) { ds.DigestType = 5 },
+			code:        epp.EppParamPolicyError,
+		},
+		{
+			description: "digest not hex",
+			modify:      func(ds *types.DNSSEC) { ds.Digest = "XYZ" },
+			code:        epp.EppParamSyntaxError,
+		},
+		{
+			description: "digest length does not match type",
+			modify:      func(ds *types.DNSSEC) { ds.DigestType = DigestSHA1 },
+			code:        epp.EppParamSyntaxError,
+		},
+		{
+			description: "key tag does not match key",
+			modify: func(ds *types.DNSSEC) {
+				ds.KeyTag = 12345
+				ds.KeyData = &rsaKey
+			},
+			code: epp.EppParamPolicyError,
+		},
+		{
+			description: "algorithm does not match key",
+			modify: func(ds *types.DNSSEC) {
+				ds.Algorithm = AlgorithmRSASHA256
+				ds.KeyData = &rsaKey
+			},
+			code: epp.EppParamPolicyError,
+		},
+		{
+			description: "digest computed for another name",
+			name:        "example.com",
+			modify:      func(ds *types.DNSSEC) { ds.KeyData = &rsaKey },
+			code:        epp.EppParamPolicyError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			name := tc.name
+			if name == "" {
+				name = "dskey.example.com"
+			}
+
+			ds := valid
+			tc.modify(&ds)
+
+			assertCode(t, tc.code, ValidateDS(name, ds))
+		})
+	}
+}
+
+func TestKeyTag(t *testing.T) {
+	keyTag, err := KeyTag(types.DNSSECKeyData{
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: AlgorithmECDSAP256SHA256,
+		PublicKey: "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==",
+	})
+	require.Nil(t, err)
+	assert.Equal(t, uint(55648), keyTag)
+
+	// RSA/MD5 uses the modulus instead of the checksum.
+	md5 := rsaKey
+	md5.Algorithm = AlgorithmRSAMD5
+	md5.PublicKey = "AQIDBAU="
+
+	keyTag, err = KeyTag(md5)
+	require.Nil(t, err)
+	assert.Equal(t, uint(0x0304), keyTag)
+
+	assert.Nil(t, VerifyKeyTag(types.DNSSEC{KeyTag: 60485, KeyData: &rsaKey}))
+	assertCode(t, epp.EppParamPolicyError, VerifyKeyTag(types.DNSSEC{KeyTag: 1, KeyData: &rsaKey}))
+}
+
+func TestInterface(t *testing.T) {
+	ds, err := ComputeDS("example.net", ecdsaKey, DigestSHA384)
+	require.Nil(t, err)
+
+	dsData := types.DNSSECOrKeyData{DNSSECData: []types.DNSSEC{*ds}}
+	keyData := types.DNSSECOrKeyData{KeyData: []types.DNSSECKeyData{ecdsaKey}}
+
+	assert.Nil(t, DSDataInterface.ValidateCreate("example.net", dsData))
+	assert.Nil(t, KeyDataInterface.ValidateCreate("example.net", keyData))
+	assertCode(t, epp.EppParamPolicyError, DSDataInterface.ValidateCreate("example.net", keyData))
+	assertCode(t, epp.EppParamPolicyError, KeyDataInterface.ValidateCreate("example.net", dsData))
+
+	// The DS data is validated even if the interface is supported.
+	invalid := types.DNSSECOrKeyData{DNSSECData: []types.DNSSEC{*ds}}
+	invalid.DNSSECData[0].Digest = "ABC"
+	assertCode(t, epp.EppParamSyntaxError, DSDataInterface.ValidateCreate("example.net", invalid))
+
+	// Removed data must use the supported interface as well.
+	assert.Nil(t, KeyDataInterface.ValidateUpdate("example.net", types.DNSSECExtensionUpdate{
+		Remove: &types.DNSSECRemove{KeyData: keyData.KeyData},
+		Add:    &keyData,
+	}))
+	assertCode(t, epp.EppParamPolicyError, KeyDataInterface.ValidateUpdate("example.net", types.DNSSECExtensionUpdate{
+		Remove: &types.DNSSECRemove{DNSSECdata: dsData.DNSSECData},
+	}))
+	assertCode(t, epp.EppParamPolicyError, DSDataInterface.ValidateUpdate("example.net", types.DNSSECExtensionUpdate{
+		Remove: &types.DNSSECRemove{All: true},
+		Add:    &keyData,
+	}))
+	assertCode(t, epp.EppParamSyntaxError, DSDataInterface.ValidateUpdate("example.net", types.DNSSECExtensionUpdate{
+		Remove: &types.DNSSECRemove{All: true, DNSSECdata: dsData.DNSSECData},
+	}))
+
+	// maxSigLife must be within 1 to 2147483647 seconds.
+	withSigLife := types.DNSSECOrKeyData{DNSSECData: dsData.DNSSECData, MaxSignatureLife: 604800}
+	assert.Nil(t, DSDataInterface.ValidateCreate("example.net", withSigLife))
+
+	withSigLife.MaxSignatureLife = MaxSignatureLife + 1
+	assertCode(t, epp.EppParamSyntaxError, DSDataInterface.ValidateCreate("example.net", withSigLife))
+
+	withSigLife.MaxSignatureLife = -1
+	assertCode(t, epp.EppParamSyntaxError, DSDataInterface.ValidateCreate("example.net", withSigLife))
+
+	assert.Nil(t, DSDataInterface.ValidateUpdate("example.net", types.DNSSECExtensionUpdate{ChangeMaxSignatureLife: MaxSignatureLife}))
+	assertCode(t, epp.EppParamSyntaxError, DSDataInterface.ValidateUpdate("example.net", types.DNSSECExtensionUpdate{
+		ChangeMaxSignatureLife: MaxSignatureLife + 1,
+	}))
+
+	assert.Equal(t, "dsData", DSDataInterface.String())
+	assert.Equal(t, "keyData", KeyDataInterface.String())
+}
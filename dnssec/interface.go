@@ -0,0 +1,95 @@
+package dnssec
+
+import (
+	"github.com/bombsimon/epp-go/types"
+)
+
+// 서버가 지원하는 secDNS-1.1 인터페이스입니다. 서버는 DS 데이터 인터페이스와 키 데이터
+// 인터페이스 중 하나만 지원해야 합니다. (RFC 5910 4)
+type Interface int
+
+// 인터페이스의 종류입니다.
+const (
+	// DS 데이터(dsData)로 위임 정보를 관리하며, DS 데이터에 키 데이터를 포함할 수
+	// 있습니다.
+	DSDataInterface Interface = iota
+
+	// 키 데이터(keyData)로 위임 정보를 관리하며, 서버가 DS 데이터를 생성합니다.
+	KeyDataInterface
+)
+
+// 인터페이스 이름을 반환합니다.
+func (i Interface) String() string {
+	if i == KeyDataInterface {
+		return "keyData"
+	}
+
+	return "dsData"
+}
+
+// 도메인 생성 확장의 DNSSEC 데이터를 검증합니다. 지원하지 않는 인터페이스의 데이터가
+// 있으면 2306 을 반환합니다.
+func (i Interface) ValidateCreate(name string, create types.DNSSECOrKeyData) error {
+	return i.validate(name, create)
+}
+
+// 도메인 수정 확장의 DNSSEC 데이터를 검증합니다. 추가하는 데이터는 생성과 같이
+// 검증하고, 삭제하는 데이터는 인터페이스만 확인합니다.
+func (i Interface) ValidateUpdate(name string, update types.DNSSECExtensionUpdate) error {
+	if err := ValidateMaxSigLife(update.ChangeMaxSignatureLife); err != nil {
+		return err
+	}
+
+	if update.Remove != nil {
+		if err := i.validInterface(len(update.Remove.DNSSECdata), len(update.Remove.KeyData)); err != nil {
+			return err
+		}
+
+		if update.Remove.All && (len(update.Remove.DNSSECdata) > 0 || len(update.Remove.KeyData) > 0) {
+			return syntaxError("all can not be combined with dsData or keyData")
+		}
+	}
+
+	if update.Add != nil {
+		if err := i.validate(name, *update.Add); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i Interface) validate(name string, data types.DNSSECOrKeyData) error {
+	if err := i.validInterface(len(data.DNSSECData), len(data.KeyData)); err != nil {
+		return err
+	}
+
+	if err := ValidateMaxSigLife(data.MaxSignatureLife); err != nil {
+		return err
+	}
+
+	for _, ds := range data.DNSSECData {
+		if err := ValidateDS(name, ds); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range data.KeyData {
+		if err := ValidateKeyData(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i Interface) validInterface(dsData, keyData int) error {
+	switch {
+	case i == DSDataInterface && keyData > 0:
+		return policyError("the keyData interface is not supported, use dsData")
+	case i == KeyDataInterface && dsData > 0:
+		return policyError("the dsData interface is not supported, use keyData")
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/registry"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func main() {
+	addr := flag.String("addr", ":700", "address to listen on")
+	certFile := flag.String("cert", "../../cert/server.crt", "server certificate")
+	keyFile := flag.String("key", "../../cert/server.key", "server key")
+	sqlite := flag.String("sqlite", "", "SQLite database file, objects are kept in memory if not set")
+	transferPeriod := flag.Duration("transfer-period", 5*24*time.Hour, "time until pending transfers are approved, 0 approves immediately")
+	autoRenew := flag.Bool("auto-renew", true, "renew expired domains automatically instead of deleting them")
+	flag.Parse()
+
+	var repo registry.Repository = registry.NewMemoryRepository()
+
+	if *sqlite != "" {
+		sqlRepo, err := registry.OpenSQLRepository(epp.DatabaseInfo{Type: "sqlite", Database: *sqlite})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		defer sqlRepo.Close()
+
+		repo = sqlRepo
+	}
+
+	// 레지스트리를 초기화하고 모든 명령어를 Mux 에 등록합니다.
+	r := registry.New(repo)
+	r.TransferPeriod = *transferPeriod
+	r.Lifecycle.AutoRenew = *autoRenew
+	mux := epp.NewMux()
+
+	r.Register(mux)
+
+	// 이전 요청, 만료된 도메인과 삭제중인 도메인을 주기적으로 처리합니다.
+	go r.RunLifecycle(time.Minute, nil)
+
+	validator, err := epp.NewValidator("../../xml/index.xsd")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	server := epp.Server{
+		Addr: *addr,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.RequireAnyClientCert,
+		},
+		SessionConfig: epp.SessionConfig{
+			IdleTimeout:    5 * time.Minute,
+			SessionTimeout: 10 * time.Minute,
+			Greeting:       r.Greeting,
+			Handler:        mux.Handle,
+			Validator:      validator,
+		},
+	}
+
+	// Graceful 서버 종료 지원
+	go func() {
+		sigs := make(chan os.Signal, 1)
+		signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+		<-sigs
+		server.Stop()
+	}()
+
+	log.Printf("Listening registry on %s...", server.Addr)
+
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatal(err.Error())
+	}
+}
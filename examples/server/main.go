@@ -6,27 +6,20 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
-	"encoding/xml"
 	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"math/big"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 	"time"
 
-	"github.com/pkg/errors"
-
 	epp "github.com/bombsimon/epp-go"
 	"github.com/bombsimon/epp-go/types"
 
 	"database/sql"
 
-	"gopkg.in/yaml.v2"
-
 	_ "github.com/godror/godror"
 )
 
@@ -34,34 +27,30 @@ func main() {
 	// Mux 초기화
 	mux := epp.NewMux()
 
+	// Flag
+	configPtr := flag.String("config", "../../config.yml", "configuration file")
+	envPtr := flag.String("env", "", "application environment, overrides the configuration. [production|development]")
+	flag.Parse()
+
+	if *envPtr != "" {
+		os.Setenv(epp.EnvEnvironment, *envPtr)
+	}
+
 	// Config 로드
-	filename, _ := filepath.Abs("../../config.yml")
-	yamlFile, err := ioutil.ReadFile(filename)
-	var config epp.Config
-	if err := yaml.Unmarshal(yamlFile, &config); err != nil {
-		panic(err)
+	config, err := epp.LoadConfig(*configPtr)
+	if err != nil {
+		log.Fatal(err)
 	}
-	var dbConfig epp.DatabaseEnv
 
-	// Flag
-	envPtr := flag.String("env", "production", "application environment. [production|development]")
-	flag.Parse()
-	if *envPtr == "development" {
-		dbConfig = config.Database.Development
+	dbConfig := config.DatabaseEnv()
+
+	if config.Environment == epp.EnvironmentDevelopment {
 		log.Println(fmt.Sprintf("# This will be runned in the development environment. Debug statements may appear on this console."))
-	} else {
-		dbConfig = config.Database.Production
 	}
 
 	// 로드 시간 초기화
 	startTime := time.Now()
 
-	// XSD Validator 초기화
-	validator, err := epp.NewValidator("../../xml/index.xsd")
-	if err != nil {
-		panic(err)
-	}
-
 	// MySQL 연결 초기화
 	log.Println(fmt.Sprintf("Initializing Oracle Database..."))
 	db, err := sql.Open("godror", fmt.Sprintf("%s:%s@tcp(%s:%d)/%s", "root", dbConfig.Oracle.User, dbConfig.Oracle.Host, dbConfig.Oracle.Port, dbConfig.Oracle.Database))
@@ -71,46 +60,38 @@ func main() {
 	}
 	defer db.Close()
 
-	// 인증서 조회
-	cert, err := tls.LoadX509KeyPair("../../cert/server.crt", "../../cert/server.key")
+	// 클라이언트 인증서 정책. 연결할 때 인증서를 검증하고 로그인 할 때 인증서가
+	// clID 에 등록된 것인지 확인합니다.
+	certPolicy := epp.NewClientCertPolicy()
+
+	if fp := os.Getenv("EPP_CLIENTX_FINGERPRINT"); fp != "" {
+		certPolicy.PinCertificate("ClientX", fp)
+	}
+
+	// 포트, 인증서, 제한시간, XSD Validator 는 설정 파일로 초기화됩니다.
+	server, err := epp.NewServerFromConfig(config, epp.SessionConfig{
+		// 클라이언트 인증서 정책
+		ClientCertPolicy: certPolicy,
+		// Greeting
+		Greeting: greeting,
+		// 커맨드 핸들러
+		Handler: mux.Handle,
+		// 명령어를 전달받았을 때 실행될 콜백
+		OnCommands: []func(sess *epp.Session){
+			func(sess *epp.Session) {
+				log.Printf("this command was brought to you by %s", sess.SessionID)
+			},
+		},
+	})
 	if err != nil {
 		panic(err)
 	}
 	//cert := generateCertificate()
 
-	server := epp.Server{
-		// 포트
-		Addr: fmt.Sprintf(":%d", config.Server.Port),
-		// TLS 설정
-		TLSConfig: &tls.Config{
-			// 인증서
-			Certificates: []tls.Certificate{cert},
-			// 클라이언트 인증 타입 (https://golang.org/src/crypto/tls/common.go?s=9726:9749#L283)
-			ClientAuth: tls.RequireAnyClientCert, // tls.RequireAnyClientCert,
-		},
-		// 세션 설정
-		SessionConfig: epp.SessionConfig{
-			// 유휴 제한시간
-			IdleTimeout: 5 * time.Minute,
-			// 세션 제한시간
-			SessionTimeout: 10 * time.Minute,
-			// Greeting
-			Greeting: greeting,
-			// 커맨드 핸들러
-			Handler: mux.Handle,
-			// 명령어를 전달받았을 때 실행될 콜백
-			OnCommands: []func(sess *epp.Session){
-				func(sess *epp.Session) {
-					log.Printf("this command was brought to you by %s", sess.SessionID)
-				},
-			},
-			Validator: validator,
-		},
-		OnStarteds: []func() {
-			func() {
-				estTime := time.Since(startTime)
-				log.Printf("Done! Estimated Time: %s", estTime)
-			},
+	server.OnStarteds = []func(){
+		func() {
+			estTime := time.Since(startTime)
+			log.Printf("Done! Estimated Time: %s", estTime)
 		},
 	}
 
@@ -136,15 +117,6 @@ func main() {
 }
 
 func greeting(s *epp.Session) ([]byte, error) {
-	err := verifyClientCertificate(s.ConnectionState().PeerCertificates)
-	if err != nil {
-		_ = s.Close()
-
-		fmt.Println("could not verify peer certificates")
-
-		return nil, errors.New("could not verify certificates")
-	}
-
 	greeting := types.EPPGreeting{
 		Greeting: types.Greeting{
 			ServerID:   "default-server",
@@ -178,40 +150,34 @@ func greeting(s *epp.Session) ([]byte, error) {
 		},
 	}
 
+	// 확장 네임스페이스는 설정 파일로 초기화됩니다.
+	if len(s.Extensions) > 0 {
+		greeting.Greeting.ServiceMenu.ServiceExtension = &types.ServiceExtension{
+			ExtensionURI: s.Extensions,
+		}
+	}
+
 	return epp.Encode(greeting, epp.ServerXMLAttributes())
 }
 
 func login(s *epp.Session, data []byte) ([]byte, error) {
 	login := types.Login{}
 
-	if err := xml.Unmarshal(data, &login); err != nil {
+	if err := epp.Decode(data, &login); err != nil {
 		return nil, err
 	}
 
 	// 로그인 타입에서 찾은 유저를 인증합니다.
 
-	response := types.Response{
-		Result: []types.Result{
-			{
-				Code:    epp.EppOk.Code(),
-				Message: epp.EppOk.Message(),
-			},
-		},
-		TransactionID: types.TransactionID{
-			ServerTransactionID: "ABC-123",
-		},
-	}
-
-	return epp.Encode(
-		response,
-		epp.ServerXMLAttributes(),
-	)
+	return epp.NewResponse(epp.EppOk).
+		WithTrID("", "ABC-123").
+		Encode()
 }
 
 func infoDomainWithExtension(s *epp.Session, data []byte) ([]byte, error) {
-	di := types.DomainInfoTypeIn{}
+	di := types.DomainInfoType{}
 
-	if err := xml.Unmarshal(data, &di); err != nil {
+	if err := epp.Decode(data, &di); err != nil {
 		return nil, err
 	}
 
@@ -260,37 +226,17 @@ func infoDomainWithExtension(s *epp.Session, data []byte) ([]byte, error) {
 	}
 
 	// Generate the response with the default result data and two extensions.
-	response := types.Response{
-		Result: []types.Result{
-			{
-				Code:    epp.EppOk.Code(),
-				Message: epp.EppOk.Message(),
-			},
-		},
-		ResultData: diResponse,
-		// Inline construct an extension type that holds both DNSSEC and IIS.
-		Extension: struct {
-			types.IISExtensionInfoDataType
-			types.DNSSECExtensionInfoDataType
-		}{
-			diIISExtensionResponse,
-			diDNSSECExtensionResponse,
-		},
-		TransactionID: types.TransactionID{
-			ServerTransactionID: "ABC-123",
-		},
-	}
-
-	return epp.Encode(
-		response,
-		epp.ServerXMLAttributes(),
-	)
+	return epp.NewResponse(epp.EppOk).
+		WithResData(diResponse).
+		WithExtension(diIISExtensionResponse, diDNSSECExtensionResponse).
+		WithTrID("", "ABC-123").
+		Encode()
 }
 
 func createDomain(s *epp.Session, data []byte) ([]byte, error) {
-	dc := types.DomainCreateTypeIn{}
+	dc := types.DomainCreateType{}
 
-	if err := xml.Unmarshal(data, &dc); err != nil {
+	if err := epp.Decode(data, &dc); err != nil {
 		return nil, err
 	}
 
@@ -304,11 +250,11 @@ func createDomain(s *epp.Session, data []byte) ([]byte, error) {
 
 func createContactWithExtension(s *epp.Session, data []byte) ([]byte, error) {
 	cc := struct {
-		types.ContactCreate
-		types.IISExtensionCreate
+		types.ContactCreateType
+		types.IISExtensionCreateType
 	}{}
 
-	if err := xml.Unmarshal(data, &cc); err != nil {
+	if err := epp.Decode(data, &cc); err != nil {
 		return nil, err
 	}
 
@@ -320,21 +266,6 @@ func createContactWithExtension(s *epp.Session, data []byte) ([]byte, error) {
 	)
 }
 
-func verifyClientCertificate(certs []*x509.Certificate) error {
-	if len(certs) != 1 {
-		return errors.New("dind't find one single client ceritficate")
-	}
-
-	cert := certs[0]
-	_, ok := cert.PublicKey.(*rsa.PublicKey)
-	if !ok {
-		return errors.New("could not convert public key")
-	}
-
-	// Do something with public key.
-	return nil
-}
-
 func generateCertificate() tls.Certificate {
 	key, _ := rsa.GenerateKey(rand.Reader, 2048)
 
@@ -1,19 +1,27 @@
 module github.com/bombsimon/epp-go
 
-go 1.12
+go 1.19
 
 require (
 	aqwari.net/xml v0.0.0-20190411173135-9e2dd5ec99d1
 	github.com/go-sql-driver/mysql v1.5.0
-	github.com/godror/godror v0.25.3 // indirect
+	github.com/godror/godror v0.25.3
 	github.com/google/uuid v1.1.1
 	github.com/lestrrat-go/libxml2 v0.0.0-20180810110639-f24a389bbd76
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/pkg/errors v0.8.1
 	github.com/satori/go.uuid v1.2.1-0.20181028125025-b2ce2384e17b
-	github.com/stretchr/objx v0.2.0 // indirect
 	github.com/stretchr/testify v1.3.0
-	golang.org/x/crypto v0.0.0-20190411191339-88737f569e3a // indirect
+	golang.org/x/crypto v0.0.0-20190411191339-88737f569e3a
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logfmt/logfmt v0.5.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.2.0 // indirect
 	golang.org/x/net v0.0.0-20190404232315-eb5bcb51f2a3 // indirect
 	golang.org/x/sys v0.0.0-20190412213103-97732733099d // indirect
-	gopkg.in/yaml.v2 v2.4.0 // indirect
+	golang.org/x/text v0.3.0 // indirect
 )
@@ -0,0 +1,404 @@
+package epp
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"aqwari.net/xml/xmltree"
+	"github.com/bombsimon/epp-go/types"
+	xsd "github.com/lestrrat-go/libxml2/xsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Run the tests with -update to regenerate all golden files, e.g. with
+// go test -run TestGolden -update.
+var updateGolden = flag.Bool("update", false, "update golden files")
+
+type roundTripTest struct {
+	input string
+	value func() interface{}
+
+	// invalid is set to the reason if the encoded XML is known to not
+	// validate against the XSD.
+	invalid string
+}
+
+type contactCreateWithIIS struct {
+	types.ContactCreateType
+	types.IISExtensionCreateType
+}
+
+type domainTransferWithIIS struct {
+	types.DomainTransferType
+	types.IISExtensionTransferType
+}
+
+type domainUpdateWithDNSSEC struct {
+	types.DomainUpdateType
+	types.DNSSECExtensionUpdateType
+}
+
+type domainUpdateWithRGP struct {
+	types.DomainUpdateType
+	types.RGPExtensionUpdateType
+}
+
+type domainCheckWithLaunch struct {
+	types.DomainCheckType
+	types.LaunchExtensionCheckType
+}
+
+type domainCreateWithLaunch struct {
+	types.DomainCreateType
+	types.LaunchExtensionCreateType
+}
+
+type domainInfoWithLaunch struct {
+	types.DomainInfoType
+	types.LaunchExtensionInfoType
+}
+
+type domainUpdateWithLaunch struct {
+	types.DomainUpdateType
+	types.LaunchExtensionUpdateType
+}
+
+type domainDeleteWithLaunch struct {
+	types.DomainDeleteType
+	types.LaunchExtensionDeleteType
+}
+
+type domainCheckWithFee struct {
+	types.DomainCheckType
+	types.FeeExtensionCheckType
+}
+
+type domainCreateWithFee struct {
+	types.DomainCreateType
+	types.FeeExtensionCreateType
+}
+
+type domainCreateWithAllocationToken struct {
+	types.DomainCreateType
+	types.AllocationTokenExtensionType
+}
+
+type domainInfoWithAllocationToken struct {
+	types.DomainInfoType
+	types.AllocationTokenExtensionInfoType
+}
+
+type domainCreateWithOrg struct {
+	types.DomainCreateType
+	types.OrgExtensionCreateType
+}
+
+type domainUpdateWithOrg struct {
+	types.DomainUpdateType
+	types.OrgExtensionUpdateType
+}
+
+type loginWithSecurity struct {
+	types.Login
+	types.LoginSecExtensionType
+}
+
+type domainInfoExtensions struct {
+	types.DNSSECExtensionInfoDataType
+	types.IISExtensionInfoDataType
+	types.RGPExtensionInfoDataType
+}
+
+// commandTests holds the type to use for each file in xml/commands.
+var commandTests = []roundTripTest{
+	{input: "ack-poll.xml", value: func() interface{} { return &types.Poll{} }},
+	{input: "check-contact.xml", value: func() interface{} { return &types.ContactCheckType{} }},
+	{input: "check-domain.xml", value: func() interface{} { return &types.DomainCheckType{} }},
+	{input: "check-domain-fee.xml", value: func() interface{} { return &domainCheckWithFee{} }},
+	{input: "check-domain-launch.xml", value: func() interface{} { return &domainCheckWithLaunch{} }},
+	{input: "check-host.xml", value: func() interface{} { return &types.HostCheckType{} }},
+	{input: "check-org.xml", value: func() interface{} { return &types.OrgCheckType{} }},
+	{input: "create-contact.xml", value: func() interface{} { return &contactCreateWithIIS{} }},
+	{input: "create-domain.xml", value: func() interface{} { return &types.DomainCreateType{} }},
+	{input: "create-domain-allocation-token.xml", value: func() interface{} { return &domainCreateWithAllocationToken{} }},
+	{input: "create-domain-fee.xml", value: func() interface{} { return &domainCreateWithFee{} }},
+	{input: "create-domain-launch.xml", value: func() interface{} { return &domainCreateWithLaunch{} }},
+	{input: "create-domain-orgext.xml", value: func() interface{} { return &domainCreateWithOrg{} }},
+	{input: "create-host.xml", value: func() interface{} { return &types.HostCreateType{} }},
+	{input: "create-org.xml", value: func() interface{} { return &types.OrgCreateType{} }},
+	{input: "delete-contact.xml", value: func() interface{} { return &types.ContactDeleteType{} }},
+	{input: "delete-domain.xml", value: func() interface{} { return &types.DomainDeleteType{} }},
+	{input: "delete-domain-launch.xml", value: func() interface{} { return &domainDeleteWithLaunch{} }},
+	{input: "delete-host.xml", value: func() interface{} { return &types.HostDeleteType{} }},
+	{input: "delete-org.xml", value: func() interface{} { return &types.OrgDeleteType{} }},
+	{input: "domain-renew.xml", value: func() interface{} { return &types.DomainRenewType{} }},
+	{input: "hello.xml", value: func() interface{} { return &types.Hello{} }},
+	{input: "info-contact.xml", value: func() interface{} { return &types.ContactInfoType{} }},
+	{input: "info-domain.xml", value: func() interface{} { return &types.DomainInfoType{} }},
+	{input: "info-domain-allocation-token.xml", value: func() interface{} { return &domainInfoWithAllocationToken{} }},
+	{input: "info-domain-launch.xml", value: func() interface{} { return &domainInfoWithLaunch{} }},
+	{input: "info-host.xml", value: func() interface{} { return &types.HostInfoType{} }},
+	{input: "info-maintenance.xml", value: func() interface{} { return &types.MaintenanceInfoType{} }},
+	{input: "info-maintenance-list.xml", value: func() interface{} { return &types.MaintenanceInfoType{} }},
+	{input: "info-org.xml", value: func() interface{} { return &types.OrgInfoType{} }},
+	{input: "keyrelay.xml", value: func() interface{} { return &types.KeyRelayType{} }},
+	{input: "login.xml", value: func() interface{} { return &types.Login{} }},
+	{input: "login-security.xml", value: func() interface{} { return &loginWithSecurity{} }},
+	{input: "logout.xml", value: func() interface{} { return &types.Logout{} }},
+	{input: "req-poll.xml", value: func() interface{} { return &types.Poll{} }},
+	{input: "restore-domain.xml", value: func() interface{} { return &domainUpdateWithRGP{} }},
+	{input: "transfer-contact.xml", value: func() interface{} { return &types.ContactTransferType{} }},
+	{input: "transfer-domain.xml", value: func() interface{} { return &domainTransferWithIIS{} }},
+	{input: "update-contact.xml", value: func() interface{} { return &types.ContactUpdateType{} }},
+	{input: "update-domain.xml", value: func() interface{} { return &domainUpdateWithDNSSEC{} }},
+	{input: "update-domain-launch.xml", value: func() interface{} { return &domainUpdateWithLaunch{} }},
+	{input: "update-domain-null-authinfo.xml", value: func() interface{} { return &types.DomainUpdateType{} }},
+	{input: "update-domain-orgext.xml", value: func() interface{} { return &domainUpdateWithOrg{} }},
+	{input: "update-host.xml", value: func() interface{} { return &types.HostUpdateType{} }},
+	{input: "update-org.xml", value: func() interface{} { return &types.OrgUpdateType{} }},
+}
+
+// responseTests holds the type to use for each file in xml/responses.
+var responseTests = []roundTripTest{
+	{input: "ack-poll.xml", value: func() interface{} { return &types.Response{} }},
+	{input: "check-contact.xml", value: func() interface{} { return response(&types.ContactCheckDataType{}, nil) }},
+	{input: "check-domain.xml", value: func() interface{} { return response(&types.DomainChekDataType{}, nil) }},
+	{input: "check-domain-fee.xml", value: func() interface{} {
+		return response(&types.DomainChekDataType{}, &types.FeeExtensionCheckDataType{})
+	}},
+	{input: "check-domain-launch.xml", value: func() interface{} {
+		return response(nil, &types.LaunchExtensionCheckDataType{})
+	}},
+	{input: "check-host.xml", value: func() interface{} { return response(&types.HostCheckDataType{}, nil) }},
+	{input: "check-org.xml", value: func() interface{} { return response(&types.OrgCheckDataType{}, nil) }},
+	{input: "create-contact.xml", value: func() interface{} { return response(&types.ContactCreateDataType{}, nil) }},
+	{input: "create-domain.xml", value: func() interface{} { return response(&types.DomainCreateDataType{}, nil) }},
+	{input: "create-domain-fee.xml", value: func() interface{} {
+		return response(&types.DomainCreateDataType{}, &types.FeeExtensionCreateDataType{})
+	}},
+	{input: "create-domain-launch.xml", value: func() interface{} {
+		return response(&types.DomainCreateDataType{}, &types.LaunchExtensionCreateDataType{})
+	}},
+	{input: "create-host.xml", value: func() interface{} { return response(&types.HostCreateDataType{}, nil) }},
+	{input: "create-org.xml", value: func() interface{} { return response(&types.OrgCreateDataType{}, nil) }},
+	{input: "error.xml", value: func() interface{} { return &types.Response{} }},
+	{input: "greeting.xml", value: func() interface{} { return &types.EPPGreeting{} }},
+	{input: "info-contact.xml", value: func() interface{} { return response(&types.ContactInfoDataType{}, nil) }},
+	{input: "info-domain.xml", value: func() interface{} {
+		return response(&types.DomainInfoDataType{}, &domainInfoExtensions{})
+	}},
+	{input: "info-domain-allocation-token.xml", value: func() interface{} {
+		return response(&types.DomainInfoDataType{}, &types.AllocationTokenExtensionInfoDataType{})
+	}},
+	{input: "info-domain-launch.xml", value: func() interface{} {
+		return response(&types.DomainInfoDataType{}, &types.LaunchExtensionInfoDataType{})
+	}},
+	{input: "info-domain-orgext.xml", value: func() interface{} {
+		return response(&types.DomainInfoDataType{}, &types.OrgExtensionInfoDataType{})
+	}},
+	{input: "info-host.xml", value: func() interface{} { return response(&types.HostInfoDataType{}, nil) }},
+	{input: "info-maintenance.xml", value: func() interface{} { return response(&types.MaintenanceInfoDataType{}, nil) }},
+	{input: "info-maintenance-list.xml", value: func() interface{} {
+		return response(&types.MaintenanceInfoDataType{}, nil)
+	}},
+	{input: "info-org.xml", value: func() interface{} { return response(&types.OrgInfoDataType{}, nil) }},
+	{input: "login.xml", value: func() interface{} { return &types.Response{} }},
+	{input: "login-security.xml", value: func() interface{} {
+		return response(nil, &types.LoginSecExtensionDataType{})
+	}},
+	{input: "renew-domain.xml", value: func() interface{} { return response(&types.DomainRenewDataType{}, nil) }},
+	{input: "req-poll.xml", value: func() interface{} { return response(&types.PollResultData{}, nil) }},
+	{input: "req-poll-change-domain.xml", value: func() interface{} {
+		return response(&types.PollResultData{}, &types.ChangePollExtensionChangeDataType{})
+	}},
+	{input: "req-poll-keyrelay.xml", value: func() interface{} { return response(&types.PollResultData{}, nil) }},
+	{input: "req-poll-maintenance.xml", value: func() interface{} { return response(&types.PollResultData{}, nil) }},
+	{input: "req-poll-pan-contact.xml", value: func() interface{} { return response(&types.PollResultData{}, nil) }},
+	{input: "req-poll-pan-domain.xml", value: func() interface{} { return response(&types.PollResultData{}, nil) }},
+	{input: "req-poll-trn-contact.xml", value: func() interface{} { return response(&types.PollResultData{}, nil) }},
+	{input: "restore-domain.xml", value: func() interface{} { return response(nil, &types.RGPExtensionUpdateDataType{}) }},
+	{input: "transfer-contact.xml", value: func() interface{} { return response(&types.ContactTransferDataType{}, nil) }},
+	{input: "transfer-domain.xml", value: func() interface{} { return response(&types.DomainTransferDataType{}, nil) }},
+}
+
+// response returns a response which will decode the result data and extension
+// to the passed pointers.
+func response(resData, extension interface{}) *types.Response {
+	r := &types.Response{ResultData: resData}
+
+	if extension != nil {
+		r.Extension = extension
+	}
+
+	return r
+}
+
+func TestGolden(t *testing.T) {
+	validator, err := NewValidator("xml/index.xsd")
+	require.Nil(t, err)
+
+	defer validator.Free()
+
+	suites := []struct {
+		dir   string
+		tests []roundTripTest
+	}{
+		{dir: "commands", tests: commandTests},
+		{dir: "responses", tests: responseTests},
+	}
+
+	for _, suite := range suites {
+		files, err := filepath.Glob(filepath.Join("xml", suite.dir, "*.xml"))
+		require.Nil(t, err)
+		require.Len(t, suite.tests, len(files), "all files in %s should be tested", suite.dir)
+
+		for _, tt := range suite.tests {
+			tt := tt
+			inputFile := filepath.Join("xml", suite.dir, tt.input)
+			goldenFile := filepath.Join("xml", "golden", suite.dir, tt.input)
+
+			t.Run(filepath.Join(suite.dir, tt.input), func(t *testing.T) {
+				fileData, err := ioutil.ReadFile(inputFile)
+				require.Nil(t, err)
+
+				decoded := tt.value()
+
+				require.Nil(t, Decode(fileData, decoded))
+				assert.NotEqual(t, tt.value(), decoded, "nothing was decoded")
+
+				encoded, err := Encode(reflect.ValueOf(decoded).Elem().Interface(), ServerXMLAttributes())
+				require.Nil(t, err)
+
+				if tt.invalid != "" {
+					t.Logf("skipping validation: %s", tt.invalid)
+				} else if err := validator.Validate(encoded); err != nil {
+					t.Logf("encoded XML:\n%s", encoded)
+
+					if xErr, ok := err.(xsd.SchemaValidationError); ok {
+						for _, e := range xErr.Errors() {
+							t.Log(e.Error())
+						}
+					}
+
+					require.Nil(t, err, "encoded XML is not valid")
+				}
+
+				// Make sure no element from the input was dropped while
+				// decoding or encoding.
+				encodedPaths := map[string]struct{}{}
+				for _, p := range elementPaths(t, encoded) {
+					encodedPaths[p] = struct{}{}
+				}
+
+				lost := []string{}
+
+				for _, p := range elementPaths(t, fileData) {
+					_, ignored := ignoredPaths[p]
+					_, found := encodedPaths[p]
+
+					if !ignored && !found {
+						lost = append(lost, p)
+					}
+				}
+
+				assert.Empty(t, lost, "elements lost in round trip")
+
+				reDecoded := tt.value()
+
+				require.Nil(t, Decode(encoded, reDecoded))
+				assert.Equal(t, decoded, reDecoded, "round trip should not change the value")
+
+				if *updateGolden {
+					require.Nil(t, os.MkdirAll(filepath.Dir(goldenFile), 0755))
+					require.Nil(t, ioutil.WriteFile(goldenFile, encoded, 0644))
+				}
+
+				golden, err := ioutil.ReadFile(goldenFile)
+				require.Nil(t, err, "missing golden file, run with -update to create it")
+
+				assert.Equal(t, canonicalXML(t, golden), canonicalXML(t, encoded))
+			})
+		}
+	}
+}
+
+// ignoredPaths holds element paths which isn't a part of any type and is
+// expected to be lost in a round trip.
+var ignoredPaths = map[string]struct{}{
+	"{urn:ietf:params:xml:ns:epp-1.0}epp/{urn:ietf:params:xml:ns:epp-1.0}command/{urn:ietf:params:xml:ns:epp-1.0}clTRID": {},
+}
+
+// elementPaths returns the path with the full name space for every element in
+// the XML.
+func elementPaths(t *testing.T, data []byte) []string {
+	root, err := xmltree.Parse(data)
+	require.Nil(t, err)
+
+	paths := []string{}
+
+	var walk func(el *xmltree.Element, parent string)
+	walk = func(el *xmltree.Element, parent string) {
+		path := fmt.Sprintf("{%s}%s", el.Name.Space, el.Name.Local)
+		if parent != "" {
+			path = parent + "/" + path
+		}
+
+		paths = append(paths, path)
+
+		for i := range el.Children {
+			walk(&el.Children[i], path)
+		}
+	}
+
+	walk(root, "")
+
+	return paths
+}
+
+// canonicalXML returns a string representation of the XML which doesn't
+// depend on namespace prefixes, namespace declarations, attribute order or
+// whitespace.
+func canonicalXML(t *testing.T, data []byte) string {
+	root, err := xmltree.Parse(data)
+	require.Nil(t, err)
+
+	buf := bytes.Buffer{}
+	writeCanonical(&buf, root, 0)
+
+	return buf.String()
+}
+
+func writeCanonical(buf *bytes.Buffer, el *xmltree.Element, depth int) {
+	attrs := []string{}
+
+	for _, attr := range el.StartElement.Attr {
+		if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Local == "schemaLocation" {
+			continue
+		}
+
+		attrs = append(attrs, fmt.Sprintf("{%s}%s=%q", attr.Name.Space, attr.Name.Local, attr.Value))
+	}
+
+	sort.Strings(attrs)
+
+	fmt.Fprintf(buf, "%s{%s}%s %s", strings.Repeat("  ", depth), el.Name.Space, el.Name.Local, strings.Join(attrs, " "))
+
+	if len(el.Children) == 0 {
+		fmt.Fprintf(buf, " %q", strings.TrimSpace(string(el.Content)))
+	}
+
+	buf.WriteString("\n")
+
+	for i := range el.Children {
+		writeCanonical(buf, &el.Children[i], depth+1)
+	}
+}
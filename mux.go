@@ -20,6 +20,16 @@ const nsEPP = "urn:ietf:params:xml:ns:epp-1.0"
 //  m.AddHandler("command/login", handleLogin)
 //  m.AddHandler("command/check/urn:ietf:params:xml:ns:contact-1.0", handleCheckContact)
 //  m.AddHandler("command/check/domain", handleCheckDomain)
+//
+// 명령어에 확장이 있으면 확장의 네임스페이스(또는 별칭)가 붙은 라우트가 먼저 사용되고,
+// 등록된 핸들러가 없으면 확장이 없는 라우트가 사용됩니다. 여러 확장이 있어도 처음 찾은
+// 하나의 라우트만 사용되므로, 함께 사용할 수 있는 확장들의 라우트에는 모든 확장을
+// 처리하는 같은 핸들러를 등록해야 합니다.
+//  m.AddHandler("command/create/domain/launch", handleCreateDomainLaunch)
+//
+// <epp> 바로 아래의 <extension> 에 있는 프로토콜 확장 명령어는 확장의
+// 네임스페이스(또는 별칭)로 라우트됩니다.
+//  m.AddHandler("extension/keyrelay", handleKeyRelay)
 type Mux struct {
 	handlers         map[string]HandlerFunc
 	namespaceAliases map[string]string
@@ -29,9 +39,14 @@ type Mux struct {
 func NewMux() *Mux {
 	m := &Mux{
 		namespaceAliases: map[string]string{
-			types.NameSpaceDomain:  "domain",
-			types.NameSpaceHost:    "host",
-			types.NameSpaceContact: "contact",
+			types.NameSpaceDomain:        "domain",
+			types.NameSpaceHost:          "host",
+			types.NameSpaceContact:       "contact",
+			types.NameSpaceLaunch10:      "launch",
+			types.NameSpaceFee10:         "fee",
+			types.NameSpaceOrg10:         "org",
+			types.NameSpaceMaintenance10: "maint",
+			types.NameSpaceKeyRelay10:    "keyrelay",
 		},
 		handlers: make(map[string]HandlerFunc),
 	}
@@ -66,6 +81,12 @@ func (m *Mux) Handle(s *Session, d []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	for _, ext := range m.extensions(root) {
+		if h, ok := m.handlers[path+"/"+ext]; ok {
+			return h(s, d)
+		}
+	}
+
 	h, ok := m.handlers[path]
 	if !ok {
 		// TODO
@@ -75,6 +96,33 @@ func (m *Mux) Handle(s *Session, d []byte) ([]byte, error) {
 	return h(s, d)
 }
 
+// 명령어의 <extension> 태그에 있는 확장들의 네임스페이스를 별칭으로 바꿔 반환합니다.
+func (m *Mux) extensions(root *xmltree.Element) []string {
+	if len(root.Children) != 1 || root.Children[0].Name.Local != "command" {
+		return nil
+	}
+
+	var extensions []string
+
+	for _, child := range root.Children[0].Children {
+		if child.Name.Local != "extension" {
+			continue
+		}
+
+		for _, ext := range child.Children {
+			ns := ext.Name.Space
+
+			if alias, ok := m.namespaceAliases[ns]; ok {
+				ns = alias
+			}
+
+			extensions = append(extensions, ns)
+		}
+	}
+
+	return extensions
+}
+
 func (m *Mux) buildPath(root *xmltree.Element) (string, error) {
 	// 첫 번째 요소가 <epp>로 시작하는지 확인합니다.
 	if root.Name.Space != nsEPP || root.Name.Local != "epp" {
@@ -87,6 +135,17 @@ func (m *Mux) buildPath(root *xmltree.Element) (string, error) {
 	}
 
 	el := root.Children[0]
+	if el.Name.Local == "extension" && len(el.Children) > 0 {
+		// 프로토콜 확장으로 정의된 명령어는 확장의 네임스페이스로 라우트합니다.
+		ns := el.Children[0].Name.Space
+
+		if alias, ok := m.namespaceAliases[ns]; ok {
+			ns = alias
+		}
+
+		return "extension/" + ns, nil
+	}
+
 	if el.Name.Local != "command" {
 		// 명령어가 아니라면, 이 태그를 라우트 용도로 사용되도록 합니다.
 		return el.Name.Local, nil
@@ -27,6 +27,10 @@ func Test_buildPath(t *testing.T) {
 			input: "check-domain.xml",
 			want:  "command/check/domain",
 		},
+		{
+			input: "keyrelay.xml",
+			want:  "extension/keyrelay",
+		},
 		{
 			input: "login.xml",
 			want:  "command/login",
@@ -66,3 +70,29 @@ func Test_buildPath(t *testing.T) {
 		})
 	}
 }
+
+func TestMux_HandleExtension(t *testing.T) {
+	fileData, err := ioutil.ReadFile(filepath.Join("xml", "commands", "create-domain-launch.xml"))
+	require.Nil(t, err)
+
+	m := NewMux()
+
+	handler := func(route string) HandlerFunc {
+		return func(s *Session, d []byte) ([]byte, error) {
+			return []byte(route), nil
+		}
+	}
+
+	// Without an extension route the command route is used.
+	m.AddHandler("command/create/domain", handler("domain"))
+
+	route, err := m.Handle(nil, fileData)
+	require.Nil(t, err)
+	assert.Equal(t, "domain", string(route))
+
+	m.AddHandler("command/create/domain/launch", handler("launch"))
+
+	route, err = m.Handle(nil, fileData)
+	require.Nil(t, err)
+	assert.Equal(t, "launch", string(route))
+}
@@ -0,0 +1,134 @@
+package epp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+// 검증된 OCSP 응답의 인증서 상태입니다.
+type ocspStatus struct {
+	SerialNumber *big.Int
+	Status       int
+	RevokedAt    time.Time
+	ThisUpdate   time.Time
+	NextUpdate   time.Time
+}
+
+// DER 로 인코딩 된 OCSP 응답을 읽고 발급자 또는 발급자가 위임한 응답자의 서명을 검증합니다.
+// 위임된 응답자는 now 에 유효하고 id-kp-OCSPSigning 확장 키 용도가 있어야 하며
+// (RFC6960 4.2.2.2), 응답의 ResponderID 는 서명한 인증서를, CertID 는 주어진 발급자를
+// 가리켜야 합니다. cert 가 주어지면 응답에서 인증서의 상태를 찾고, nil 이면 응답에 하나의
+// 상태만 있어야 합니다.
+func parseOCSPResponse(der []byte, cert, issuer *x509.Certificate, now time.Time) (*ocspStatus, error) {
+	resp, err := ocsp.ParseResponseForCert(der, cert, issuer)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse OCSP response")
+	}
+
+	signer := issuer
+
+	// ParseResponseForCert 는 포함된 응답자의 인증서가 발급자에게 서명되었는지만
+	// 확인합니다.
+	if resp.Certificate != nil {
+		if !hasExtKeyUsage(resp.Certificate, x509.ExtKeyUsageOCSPSigning) {
+			return nil, errors.New("OCSP responder is not authorized for OCSP signing")
+		}
+
+		if now.Before(resp.Certificate.NotBefore) || now.After(resp.Certificate.NotAfter) {
+			return nil, errors.New("OCSP responder certificate is not valid at this time")
+		}
+
+		signer = resp.Certificate
+	}
+
+	if err := matchResponderID(resp, signer); err != nil {
+		return nil, err
+	}
+
+	if err := matchCertID(resp, issuer); err != nil {
+		return nil, err
+	}
+
+	if resp.ThisUpdate.After(now) {
+		return nil, errors.New("OCSP response is not valid yet")
+	}
+
+	return &ocspStatus{
+		SerialNumber: resp.SerialNumber,
+		Status:       resp.Status,
+		RevokedAt:    resp.RevokedAt,
+		ThisUpdate:   resp.ThisUpdate,
+		NextUpdate:   resp.NextUpdate,
+	}, nil
+}
+
+// 응답의 ResponderID 가 서명한 인증서의 이름 또는 공개 키의 SHA-1 해시인지 확인합니다.
+// (RFC6960 4.2.1)
+func matchResponderID(resp *ocsp.Response, signer *x509.Certificate) error {
+	if resp.RawResponderName != nil {
+		if !bytes.Equal(resp.RawResponderName, signer.RawSubject) {
+			return errors.New("OCSP responder name does not match the signer")
+		}
+
+		return nil
+	}
+
+	_, keyHash, err := issuerHashes(signer, crypto.SHA1)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(resp.ResponderKeyHash, keyHash) {
+		return errors.New("OCSP responder key hash does not match the signer")
+	}
+
+	return nil
+}
+
+// CertID 가 발급자의 이름과 공개 키를 가리키는지 확인합니다. (RFC6960 4.1.1) 파싱된
+// 응답은 CertID 의 해시를 제공하지 않으므로 발급자로 계산한 해시가 서명된 응답 데이터에
+// 있는지 확인합니다.
+func matchCertID(resp *ocsp.Response, issuer *x509.Certificate) error {
+	nameHash, keyHash, err := issuerHashes(issuer, resp.IssuerHash)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Contains(resp.TBSResponseData, nameHash) || !bytes.Contains(resp.TBSResponseData, keyHash) {
+		return errors.Errorf("OCSP response for serial %s is not for this issuer", resp.SerialNumber)
+	}
+
+	return nil
+}
+
+// 발급자의 이름과 공개 키를 CertID 와 같은 방법으로 해시합니다. 해시는 같은 발급자로
+// 만든 OCSP 요청에서 가져옵니다.
+func issuerHashes(issuer *x509.Certificate, hash crypto.Hash) ([]byte, []byte, error) {
+	der, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: big.NewInt(0)}, issuer, &ocsp.RequestOptions{Hash: hash})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not hash OCSP issuer")
+	}
+
+	req, err := ocsp.ParseRequest(der)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not hash OCSP issuer")
+	}
+
+	return req.IssuerNameHash, req.IssuerKeyHash, nil
+}
+
+func hasExtKeyUsage(cert *x509.Certificate, usage x509.ExtKeyUsage) bool {
+	for _, u := range cert.ExtKeyUsage {
+		if u == usage {
+			return true
+		}
+	}
+
+	return false
+}
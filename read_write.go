@@ -8,6 +8,9 @@ import (
 	"io"
 	"math"
 	"net"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"aqwari.net/xml/xmltree"
@@ -21,10 +24,45 @@ const (
 var (
 	connectionError   = errors.New("connection error")
 	contentIsTooLarge = errors.New("content is too large")
+	contentIsTooSmall = errors.New("content is too small")
+	invalidDecodeType = errors.New("decode requires a non-nil pointer")
+)
+
+var (
+	// Encode 와 Decode 에서 사용하는 네임스페이스별 별칭 목록입니다.
+	namespaceAliases = map[string]string{
+		types.NameSpaceDomain:            "domain",
+		types.NameSpaceHost:              "host",
+		types.NameSpaceContact:           "contact",
+		types.NameSpaceDNSSEC10:          "sed",
+		types.NameSpaceDNSSEC11:          "sec",
+		types.NameSpaceIIS12:             "iis",
+		types.NameSpaceRGP10:             "rgp",
+		types.NameSpaceLaunch10:          "launch",
+		types.NameSpaceMark10:            "mark",
+		types.NameSpaceSignedMark10:      "smd",
+		types.NameSpaceFee10:             "fee",
+		types.NameSpaceLoginSec10:        "loginSec",
+		types.NameSpaceChangePoll10:      "changePoll",
+		types.NameSpaceAllocationToken10: "allocationToken",
+		types.NameSpaceOrg10:             "org",
+		types.NameSpaceOrgExt10:          "orgext",
+		types.NameSpaceMaintenance10:     "maint",
+		types.NameSpaceKeyRelay10:        "keyrelay",
+	}
+
+	// 별칭 목록의 읽기, 쓰기 작업에 Thread Safe 접근을 보장하기 위한 Mutex 입니다.
+	namespaceAliasesMu sync.RWMutex
 )
 
 // 하나의 전체 메시지를 읽습니다.
 func ReadMessage(conn net.Conn) ([]byte, error) {
+	return readMessage(conn, 0)
+}
+
+// 헤더를 포함한 크기가 maxSize 보다 큰 메시지는 읽지 않고 오류를 반환합니다.
+// maxSize 가 0 이면 제한하지 않습니다. 헤더보다 크지 않은 메시지도 오류를 반환합니다.
+func readMessage(conn net.Conn, maxSize int) ([]byte, error) {
 	if conn == nil {
 		return nil, connectionError
 	}
@@ -39,6 +77,15 @@ func ReadMessage(conn net.Conn) ([]byte, error) {
 	headerSize := binary.Size(totalSize)
 	contentSize := int(totalSize) - headerSize
 
+	if maxSize > 0 && int(totalSize) > maxSize {
+		return nil, contentIsTooLarge
+	}
+
+	// 전체 크기는 헤더를 포함하므로 헤더보다 작거나 같으면 메시지가 없습니다.
+	if int(totalSize) <= headerSize {
+		return nil, contentIsTooSmall
+	}
+
 	// 메시지를 읽을 때 충분한 시간이 반드시 보장되도록 합니다.
 	if err := conn.SetReadDeadline(time.Now().Add(10 * time.Second)); err != nil {
 		return nil, err
@@ -123,6 +170,11 @@ func ClientXMLAttributes() []xml.Attr {
 // XML을 Marshal 할 수 있는 type을 가지고
 // 등록된 모든 네임스페이스 중에서 매치되는 EPP 태그를 붙여 Byte 조각으로 XML을 반환합니다.
 func Encode(data interface{}, xmlAttributes []xml.Attr) ([]byte, error) {
+	return encode(data, xmlAttributes, nil)
+}
+
+// Encode 와 같지만 네임스페이스 별칭을 추가하기 전에 문서를 변경할 수 있습니다.
+func encode(data interface{}, xmlAttributes []xml.Attr, modify func(*xmltree.Element)) ([]byte, error) {
 	// Input 데이터를 Marshal 하여 XML로 뽑아내고, 요구되는 태그 및 기능으로 type을 유추합니다.
 	b, err := xml.Marshal(data)
 	if err != nil {
@@ -134,7 +186,11 @@ func Encode(data interface{}, xmlAttributes []xml.Attr) ([]byte, error) {
 		return nil, err
 	}
 
-	addNameSpaceAlias(document, false)
+	if modify != nil {
+		modify(document)
+	}
+
+	addNameSpaceAlias(document, "")
 
 	// document root 요소를 적절한 EPP 태그로 변경합니다.
 	document.StartElement = xml.StartElement{
@@ -154,39 +210,300 @@ func Encode(data interface{}, xmlAttributes []xml.Attr) ([]byte, error) {
 	return xmlBytes, nil
 }
 
-// XML 구조 안에 있는 각 노드/요소를 체크하여 만약 xml.Name.Space를 가지고 있을 경우
-// 별칭이 생성되고 모든 자식 노드들에 덧붙입니다.
-// 별칭은 root 요소에 대해서만 설정됩니다.
-func addNameSpaceAlias(document *xmltree.Element, nsAdded bool) *xmltree.Element {
-	namespaceAliases := map[string]string{
-		types.NameSpaceDomain:   "domain",
-		types.NameSpaceHost:     "host",
-		types.NameSpaceContact:  "contact",
-		types.NameSpaceDNSSEC10: "sed",
-		types.NameSpaceDNSSEC11: "sec",
-		types.NameSpaceIIS12:    "iis",
+// Encode 와 Decode 에서 사용할 네임스페이스 별칭을 등록합니다.
+// 새로운 확장을 사용하려면 해당 확장의 네임스페이스를 먼저 등록해야 합니다.
+//
+//	RegisterNamespaceAlias("urn:ietf:params:xml:ns:rgp-1.0", "rgp")
+func RegisterNamespaceAlias(ns, alias string) {
+	namespaceAliasesMu.Lock()
+	defer namespaceAliasesMu.Unlock()
+
+	namespaceAliases[ns] = alias
+}
+
+// 네임스페이스에 등록된 별칭을 반환합니다.
+func aliasForNameSpace(ns string) (string, bool) {
+	namespaceAliasesMu.RLock()
+	defer namespaceAliasesMu.RUnlock()
+
+	alias, ok := namespaceAliases[ns]
+
+	return alias, ok
+}
+
+// 별칭에 등록된 네임스페이스를 반환합니다.
+func nameSpaceForAlias(alias string) (string, bool) {
+	namespaceAliasesMu.RLock()
+	defer namespaceAliasesMu.RUnlock()
+
+	for ns, a := range namespaceAliases {
+		if a == alias {
+			return ns, true
+		}
 	}
 
+	return "", false
+}
+
+// XML 구조 안에 있는 각 노드/요소를 체크하여 만약 xml.Name.Space를 가지고 있을 경우
+// 별칭이 생성되고 모든 자식 노드들에 덧붙입니다.
+// 별칭은 부모와 네임스페이스가 다른 요소(예: launch 확장 안의 mark)에 대해서만 설정됩니다.
+func addNameSpaceAlias(document *xmltree.Element, parentNS string) *xmltree.Element {
 	if document.Name.Space != "" {
-		alias, ok := namespaceAliases[document.Name.Space]
+		alias, ok := aliasForNameSpace(document.Name.Space)
 		if !ok {
-			return nil
+			// 별칭이 없는 네임스페이스(예: panData 안의 EPP paTRID)는 별칭 없이
+			// 기본 네임스페이스로 선언됩니다.
+			for i, child := range document.Children {
+				document.Children[i] = *addNameSpaceAlias(&child, "")
+			}
+
+			return document
 		}
 
-		if !nsAdded {
+		if document.Name.Space != parentNS {
 			xmlns := fmt.Sprintf("xmlns:%s", alias)
 			document.SetAttr("", xmlns, document.Name.Space)
 
-			// 네임스페이스 별칭이 추가되었으므로 자식 요소들을 건너뛰기 위해 true로 변경합니다.
-			nsAdded = true
+			// 네임스페이스 별칭이 추가되었으므로 같은 네임스페이스의 자식 요소들은 건너뜁니다.
+			parentNS = document.Name.Space
 		}
 
 		document.Name.Local = fmt.Sprintf("%s:%s", alias, document.Name.Local)
 	}
 
 	for i, child := range document.Children {
-		document.Children[i] = *addNameSpaceAlias(&child, nsAdded)
+		document.Children[i] = *addNameSpaceAlias(&child, parentNS)
 	}
 
 	return document
 }
+
+// 네임스페이스나 별칭에 상관없이 XML을 types 패키지의 type 으로 Unmarshal 합니다.
+// encoding/xml 은 `xml:"urn:ietf:params:xml:ns:domain-1.0 command>info>info"` 와 같이
+// 경로에 네임스페이스가 붙은 태그를 경로에 있는 모든 요소의 네임스페이스와 비교하므로
+// 이런 필드는 직접 찾아서 Unmarshal 합니다.
+//
+//	di := types.DomainInfoType{}
+//
+//	if err := Decode(data, &di); err != nil {
+//	    return nil, err
+//	}
+func Decode(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return invalidDecodeType
+	}
+
+	document, err := xmltree.Parse(data)
+	if err != nil {
+		return err
+	}
+
+	// 클라이언트마다 다른 접두사를 사용하므로 Encode 와 같은 별칭으로 모든 요소를 다시 작성합니다.
+	normalizeNameSpace(document, "", map[string]struct{}{})
+
+	normalized := xmltree.Marshal(document)
+
+	if err := xml.Unmarshal(normalized, v); err != nil {
+		return err
+	}
+
+	document, err = xmltree.Parse(normalized)
+	if err != nil {
+		return err
+	}
+
+	return decodeNameSpacedFields(document, rv.Elem())
+}
+
+// 요소의 이름에 등록된 별칭을 접두사로 붙이고 필요한 xmlns 속성을 선언합니다.
+// 선언되지 않은 접두사가 등록된 별칭이라면 해당 별칭의 네임스페이스로 처리합니다.
+func normalizeNameSpace(el *xmltree.Element, defaultNS string, declared map[string]struct{}) {
+	ns := el.Name.Space
+	if uri, ok := nameSpaceForAlias(ns); ok {
+		ns = uri
+	}
+
+	// xsi:schemaLocation 과 같이 네임스페이스가 있는 속성은 Unmarshal 에 사용되지 않으므로 제거합니다.
+	attributes := []xml.Attr{}
+
+	for _, attr := range el.StartElement.Attr {
+		if attr.Name.Space == "" {
+			attributes = append(attributes, attr)
+		}
+	}
+
+	if alias, ok := aliasForNameSpace(ns); ok {
+		if _, ok := declared[alias]; !ok {
+			attributes = append(attributes, xml.Attr{
+				Name:  xml.Name{Local: fmt.Sprintf("xmlns:%s", alias)},
+				Value: ns,
+			})
+
+			inner := map[string]struct{}{alias: {}}
+			for a := range declared {
+				inner[a] = struct{}{}
+			}
+
+			declared = inner
+		}
+
+		el.Name = xml.Name{Local: fmt.Sprintf("%s:%s", alias, el.Name.Local)}
+	} else {
+		if ns != defaultNS {
+			attributes = append(attributes, xml.Attr{
+				Name:  xml.Name{Local: "xmlns"},
+				Value: ns,
+			})
+
+			defaultNS = ns
+		}
+
+		el.Name = xml.Name{Local: el.Name.Local}
+	}
+
+	el.StartElement.Attr = attributes
+	el.Scope = xmltree.Scope{}
+
+	for i := range el.Children {
+		normalizeNameSpace(&el.Children[i], defaultNS, declared)
+	}
+}
+
+// 경로에 네임스페이스가 붙은 필드를 찾아서 해당하는 요소를 Unmarshal 합니다.
+// 익명으로 포함된 구조체도 확인하므로 여러 type 을 하나의 구조체로 묶어서 사용할 수 있습니다.
+func decodeNameSpacedFields(root *xmltree.Element, v reflect.Value) error {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		tag := field.Tag.Get("xml")
+
+		if field.Anonymous && tag == "" {
+			if err := decodeNameSpacedFields(root, v.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		// 포인터를 가진 인터페이스 필드(예: Response 의 ResultData)
+		if field.Type.Kind() == reflect.Interface {
+			if err := decodeInterfaceField(root, tag, v.Field(i)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		nameParts := strings.SplitN(strings.Split(tag, ",")[0], " ", 2)
+		if len(nameParts) != 2 {
+			continue
+		}
+
+		// 경로가 없는 필드는 encoding/xml 이 네임스페이스를 올바르게 비교합니다.
+		path := strings.Split(nameParts[1], ">")
+		if len(path) < 2 {
+			continue
+		}
+
+		for _, el := range findElements(root, nameParts[0], path) {
+			if err := unmarshalElement(el, v.Field(i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// 인터페이스 필드가 nil 이 아닌 포인터를 가지고 있으면 encoding/xml 이 해당
+// 포인터에 디코딩하므로, 태그의 경로에서 찾은 요소를 기준으로 경로가 있는
+// 네임스페이스 필드만 추가로 디코딩합니다.
+func decodeInterfaceField(root *xmltree.Element, tag string, field reflect.Value) error {
+	if field.IsNil() || field.Elem().Kind() != reflect.Ptr || field.Elem().IsNil() {
+		return nil
+	}
+
+	name := strings.Split(tag, ",")[0]
+	if name == "" || strings.Contains(name, " ") {
+		return nil
+	}
+
+	for _, el := range findElements(root, "", strings.Split(name, ">")) {
+		if err := decodeNameSpacedFields(el, field.Elem()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 경로를 따라 요소를 찾습니다. 마지막 요소만 네임스페이스를 비교합니다.
+func findElements(root *xmltree.Element, ns string, path []string) []*xmltree.Element {
+	current := []*xmltree.Element{root}
+
+	for i, local := range path {
+		next := []*xmltree.Element{}
+
+		for _, el := range current {
+			for j := range el.Children {
+				child := &el.Children[j]
+
+				if child.Name.Local != local {
+					continue
+				}
+
+				if i == len(path)-1 && ns != "" && child.Name.Space != ns {
+					continue
+				}
+
+				next = append(next, child)
+			}
+		}
+
+		current = next
+	}
+
+	return current
+}
+
+// 요소를 필드에 Unmarshal 합니다. 슬라이스인 경우 요소를 추가합니다.
+func unmarshalElement(el *xmltree.Element, field reflect.Value) error {
+	if field.Kind() == reflect.Slice {
+		item := reflect.New(field.Type().Elem())
+
+		if err := xmltree.Unmarshal(el, item.Interface()); err != nil {
+			return err
+		}
+
+		field.Set(reflect.Append(field, item.Elem()))
+
+		return nil
+	}
+
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			field.Set(reflect.New(field.Type().Elem()))
+		}
+
+		return xmltree.Unmarshal(el, field.Interface())
+	}
+
+	return xmltree.Unmarshal(el, field.Addr().Interface())
+}
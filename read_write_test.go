@@ -1,9 +1,13 @@
 package epp
 
 import (
+	"encoding/binary"
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/bombsimon/epp-go/types"
@@ -39,11 +43,11 @@ func TestEncode(t *testing.T) {
 	dc := types.DomainCreateType{
 		Create: types.DomainCreate{
 			Name: "example.net",
-			Period: types.Period{
+			Period: &types.Period{
 				Value: 12,
 				Unit:  "m",
 			},
-			NameServer: types.NameServer{
+			NameServer: &types.NameServer{
 				HostObject: []string{
 					"ns1.example.net",
 					"ns2.example.net",
@@ -140,7 +144,122 @@ func TestDecode(t *testing.T) {
 	assert.Equal(t, "some-password", dc.AuthInfo.Password, "auth info found")
 }
 
-func ExampleAddNamespace() {
+func TestDecode_canonicalTypes(t *testing.T) {
+	cases := []struct {
+		description string
+		xml         []byte
+	}{
+		{
+			description: "declared prefix",
+			xml: []byte(`<epp xmlns="urn:ietf:params:xml:ns:epp-1.0">
+  <command>
+    <info>
+      <domain:info xmlns:domain="urn:ietf:params:xml:ns:domain-1.0">
+        <domain:name hosts="all">example.se</domain:name>
+      </domain:info>
+    </info>
+  </command>
+</epp>`),
+		},
+		{
+			description: "custom prefix",
+			xml: []byte(`<epp xmlns="urn:ietf:params:xml:ns:epp-1.0">
+  <command>
+    <info>
+      <d:info xmlns:d="urn:ietf:params:xml:ns:domain-1.0">
+        <d:name hosts="all">example.se</d:name>
+      </d:info>
+    </info>
+  </command>
+</epp>`),
+		},
+		{
+			description: "default namespace",
+			xml: []byte(`<epp xmlns="urn:ietf:params:xml:ns:epp-1.0">
+  <command>
+    <info>
+      <info xmlns="urn:ietf:params:xml:ns:domain-1.0">
+        <name hosts="all">example.se</name>
+      </info>
+    </info>
+  </command>
+</epp>`),
+		},
+		{
+			description: "undeclared registered alias",
+			xml: []byte(`<epp xmlns="urn:ietf:params:xml:ns:epp-1.0">
+  <command>
+    <info>
+      <domain:info>
+        <domain:name hosts="all">example.se</domain:name>
+      </domain:info>
+    </info>
+  </command>
+</epp>`),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.description, func(t *testing.T) {
+			di := types.DomainInfoType{}
+
+			require.Nil(t, Decode(tc.xml, &di))
+			assert.Equal(t, "example.se", di.Info.Name.Name)
+			assert.Equal(t, types.DomainHostsAll, di.Info.Name.Hosts)
+		})
+	}
+
+	t.Run("wrong namespace", func(t *testing.T) {
+		data := []byte(`<epp xmlns="urn:ietf:params:xml:ns:epp-1.0">
+  <command>
+    <info>
+      <host:info xmlns:host="urn:ietf:params:xml:ns:host-1.0">
+        <host:name>example.se</host:name>
+      </host:info>
+    </info>
+  </command>
+</epp>`)
+
+		di := types.DomainInfoType{}
+
+		require.Nil(t, Decode(data, &di))
+		assert.Equal(t, "", di.Info.Name.Name)
+	})
+
+	t.Run("not a pointer", func(t *testing.T) {
+		assert.NotNil(t, Decode([]byte(`<epp/>`), types.DomainInfoType{}))
+	})
+}
+
+func TestDecode_roundTrip(t *testing.T) {
+	tests := commandTests
+
+	files, err := filepath.Glob(filepath.Join("xml", "commands", "*.xml"))
+	require.Nil(t, err)
+	require.Len(t, tests, len(files), "all commands should be tested")
+
+	for _, tt := range tests {
+		fileData, err := ioutil.ReadFile(filepath.Join("xml", "commands", tt.input))
+		require.Nil(t, err)
+
+		t.Run(tt.input, func(t *testing.T) {
+			decoded := tt.value()
+
+			require.Nil(t, Decode(fileData, decoded))
+			assert.NotEqual(t, tt.value(), decoded, "nothing was decoded")
+
+			encoded, err := Encode(reflect.ValueOf(decoded).Elem().Interface(), ClientXMLAttributes())
+			require.Nil(t, err)
+
+			reDecoded := tt.value()
+
+			require.Nil(t, Decode(encoded, reDecoded))
+			assert.Equal(t, decoded, reDecoded)
+		})
+	}
+}
+
+func Example_addNamespace() {
 	// Construct the response with basic data.
 	diResponse := types.DomainInfoDataType{
 		InfoData: types.DomainInfoData{
@@ -242,3 +361,27 @@ func ExampleAddNamespace() {
 	// </epp>
 
 }
+
+func TestReadMessage_maxSize(t *testing.T) {
+	conn1, conn2 := net.Pipe()
+
+	go func() {
+		_ = WriteMessage(conn1, []byte("this message is too large"))
+	}()
+
+	_, err := readMessage(conn2, 16)
+	assert.Equal(t, contentIsTooLarge, err)
+}
+
+func TestReadMessage_minSize(t *testing.T) {
+	for _, size := range []uint32{0, 3} {
+		conn1, conn2 := net.Pipe()
+
+		go func(size uint32) {
+			_ = binary.Write(conn1, binary.BigEndian, size)
+		}(size)
+
+		_, err := readMessage(conn2, 0)
+		assert.Equal(t, contentIsTooSmall, err)
+	}
+}
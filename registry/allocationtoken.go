@@ -0,0 +1,191 @@
+package registry
+
+import (
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 이미 사용된 할당 토큰을 다시 사용하면 반환되는 오류입니다.
+var ErrAllocationTokenUsed = errors.New("allocation token is already used")
+
+// 도메인을 등록하거나 이전할 수 있는 권한을 주는 할당 토큰입니다. (RFC 8495) 예약된
+// 이름이나 프리미엄 이름을 정해진 클라이언트에게만 할당할 때 사용하며, 토큰은 한 번만
+// 사용할 수 있습니다.
+type AllocationToken struct {
+	Token string
+
+	// 토큰으로 등록하거나 이전할 수 있는 도메인입니다. 비어있으면 모든 도메인에 사용할
+	// 수 있습니다. 목록에 있는 도메인은 토큰이 사용될 때까지 토큰 없이 등록할 수 없습니다.
+	Names []string
+
+	// 토큰을 사용할 수 있는 클라이언트입니다. 비어있으면 모든 클라이언트가 사용할 수
+	// 있습니다.
+	ClientIDs []string
+
+	// 토큰을 사용한 도메인과 날짜입니다. 사용되지 않은 토큰은 빈 문자열과 nil 입니다.
+	UsedBy   string
+	UsedDate *time.Time
+}
+
+// 할당 토큰을 저장하는 저장소입니다. Registry.AllocationTokens 가 nil 이면
+// allocationToken-1.0 확장을 지원하지 않고 모든 도메인을 토큰 없이 등록할 수 있습니다.
+type AllocationTokenStore interface {
+	// 토큰을 반환합니다. 토큰이 없으면 ErrObjectNotFound 를 반환합니다.
+	AllocationToken(token string) (*AllocationToken, error)
+
+	// 도메인을 등록하는데 토큰이 필요한지 확인합니다.
+	TokenRequired(name string) (bool, error)
+
+	// 토큰을 도메인에 사용합니다. 이미 사용된 토큰이면 ErrAllocationTokenUsed 를
+	// 반환합니다. 같은 토큰이 동시에 사용되지 않도록 확인과 사용은 원자적이어야 합니다.
+	UseAllocationToken(token, name string, date time.Time) error
+
+	// 도메인에 마지막으로 사용된 토큰을 반환합니다. 사용된 토큰이 없으면 nil 을
+	// 반환합니다.
+	DomainAllocationToken(name string) (*AllocationToken, error)
+}
+
+// 명령어의 allocationToken-1.0 확장에서 토큰을 반환합니다. 확장이 없으면 빈 문자열을
+// 반환합니다.
+func (r *Registry) allocationToken(data []byte) (string, error) {
+	ext := types.AllocationTokenExtensionType{}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return "", err
+	}
+
+	if ext.AllocationToken != "" && r.AllocationTokens == nil {
+		return "", errorf(epp.EppUnimplementedExtension, "the allocationToken extension is not supported")
+	}
+
+	return ext.AllocationToken, nil
+}
+
+// 클라이언트가 토큰으로 도메인을 할당받을 수 있는지 확인합니다. 토큰이 없으면 토큰이
+// 필요한 도메인인지 확인합니다. 토큰이 맞지 않으면 2201 로 응답합니다.
+func (r *Registry) verifyAllocationToken(token, name, clientID string) error {
+	if r.AllocationTokens == nil {
+		return nil
+	}
+
+	if token == "" {
+		required, err := r.AllocationTokens.TokenRequired(name)
+		if err != nil {
+			return err
+		}
+
+		if required {
+			return errorf(epp.EppAuthorisationError, "domain %s requires an allocation token", name)
+		}
+
+		return nil
+	}
+
+	t, err := r.AllocationTokens.AllocationToken(token)
+	if err != nil {
+		if errors.Cause(err) == ErrObjectNotFound {
+			return errorf(epp.EppAuthorisationError, "invalid allocation token")
+		}
+
+		return err
+	}
+
+	switch {
+	case t.UsedDate != nil:
+		return errorf(epp.EppAuthorisationError, "allocation token has already been used")
+	case len(t.Names) > 0 && indexOf(normalizeAll(t.Names), name) < 0:
+		return errorf(epp.EppAuthorisationError, "allocation token does not apply to domain %s", name)
+	case len(t.ClientIDs) > 0 && indexOf(t.ClientIDs, clientID) < 0:
+		return errorf(epp.EppAuthorisationError, "allocation token does not apply to client %s", clientID)
+	}
+
+	return nil
+}
+
+// 토큰을 도메인에 사용합니다. 토큰이 없으면 아무것도 하지 않습니다. 작업이 완료되기
+// 전에 호출해야 다른 명령어에서 사용된 토큰으로 작업이 처리되지 않습니다.
+func (r *Registry) useAllocationToken(token, name string) error {
+	if token == "" {
+		return nil
+	}
+
+	err := r.AllocationTokens.UseAllocationToken(token, name, r.now())
+	if errors.Cause(err) == ErrAllocationTokenUsed {
+		return errorf(epp.EppAuthorisationError, "allocation token has already been used")
+	}
+
+	return err
+}
+
+// check 응답의 도메인이 토큰으로 할당될 수 없으면 사용할 수 없는 도메인으로 바꿉니다.
+func (r *Registry) checkAllocationToken(cd *types.CheckType, token, clientID string) error {
+	if r.AllocationTokens == nil || !cd.Name.Available {
+		return nil
+	}
+
+	err := r.verifyAllocationToken(token, normalize(cd.Name.Value), clientID)
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := errors.Cause(err).(*Error); !ok {
+		return err
+	}
+
+	// 사유는 32자로 제한되므로 자세한 오류 대신 짧은 사유를 사용합니다.
+	cd.Name.Available = false
+	cd.Reason = "Allocation Token mismatch"
+
+	if token == "" {
+		cd.Reason = "Allocation Token required"
+	}
+
+	return nil
+}
+
+// 명령어에 allocationToken-1.0 의 info 요소가 있으면 도메인에 사용된 토큰을
+// 반환합니다. 토큰은 관리 클라이언트에게만 반환합니다.
+func (r *Registry) infoAllocationToken(s *epp.Session, data []byte, d *Domain) (*types.AllocationTokenExtensionInfoDataType, error) {
+	ext := types.AllocationTokenExtensionInfoType{}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	if ext.Info == nil {
+		return nil, nil
+	}
+
+	if r.AllocationTokens == nil {
+		return nil, errorf(epp.EppUnimplementedExtension, "the allocationToken extension is not supported")
+	}
+
+	if d.ClientID != s.ClientID {
+		return nil, errorf(epp.EppAuthorisationError, "domain %s is not sponsored by %s", d.Name, s.ClientID)
+	}
+
+	t, err := r.AllocationTokens.DomainAllocationToken(d.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 같은 이름으로 이전에 등록되었던 도메인에 사용된 토큰은 반환하지 않습니다.
+	if t == nil || t.UsedDate.Before(d.CreateDate) {
+		return nil, nil
+	}
+
+	return &types.AllocationTokenExtensionInfoDataType{AllocationToken: t.Token}, nil
+}
+
+func normalizeAll(names []string) []string {
+	normalized := make([]string, len(names))
+
+	for i, name := range names {
+		normalized[i] = normalize(name)
+	}
+
+	return normalized
+}
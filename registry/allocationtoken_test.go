@@ -0,0 +1,132 @@
+package registry
+
+import (
+	"testing"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func allocationToken(token string) types.AllocationTokenExtensionType {
+	return types.AllocationTokenExtensionType{AllocationToken: token}
+}
+
+func testRegistryAllocationToken(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+
+	// Without a token store the extension is not supported.
+	tr.send(s, epp.EppUnimplementedExtension, domainCreate("reserved.se"), allocationToken("abc123"))
+
+	tokens := NewMemoryAllocationTokenStore(
+		AllocationToken{Token: "abc123", Names: []string{"Reserved.se"}, ClientIDs: []string{"ClientX"}},
+		AllocationToken{Token: "any"},
+	)
+	tr.registry.AllocationTokens = tokens
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceAllocationToken10)
+
+	// Check reports reserved names as available only with a valid token.
+	check := types.DomainCheckType{Check: types.DomainCheck{Names: []string{"reserved.se", "example.se"}}}
+	available := func(session *epp.Session, extensions ...interface{}) []bool {
+		result := types.DomainChekDataType{}
+		decodeResData(t, tr.send(session, epp.EppOk, check, extensions...), &result)
+
+		avail := []bool{}
+		for _, cd := range result.CheckData.CheckDomain {
+			avail = append(avail, cd.Name.Available)
+		}
+
+		return avail
+	}
+
+	assert.Equal(t, []bool{false, true}, available(s))
+	assert.Equal(t, []bool{true, false}, available(s, allocationToken("abc123")))
+	assert.Equal(t, []bool{false, false}, available(other, allocationToken("abc123")))
+
+	// Reserved names can only be created with a matching token.
+	tr.send(s, epp.EppAuthorisationError, domainCreate("reserved.se"))
+	tr.send(s, epp.EppAuthorisationError, domainCreate("reserved.se"), allocationToken("unknown"))
+	tr.send(other, epp.EppAuthorisationError, domainCreate("reserved.se"), allocationToken("abc123"))
+	tr.send(s, epp.EppAuthorisationError, domainCreate("example.se"), allocationToken("abc123"))
+	tr.send(s, epp.EppOk, domainCreate("reserved.se"), allocationToken("abc123"))
+
+	token, err := tokens.AllocationToken("abc123")
+	require.Nil(t, err)
+	assert.Equal(t, "reserved.se", token.UsedBy)
+	assert.True(t, tr.now.Equal(*token.UsedDate))
+
+	// Tokens can only be used once.
+	tr.send(other, epp.EppOk, domainCreate("example.se"), allocationToken("any"))
+	tr.send(other, epp.EppAuthorisationError, domainCreate("example.nu"), allocationToken("any"))
+
+	// The token used for a domain is returned to the sponsoring client.
+	info := types.DomainInfoType{Info: types.DomainInfo{Name: types.DomainInfoName{Name: "reserved.se"}}}
+	infoToken := types.AllocationTokenExtensionInfoType{Info: types.Empty()}
+
+	ext := types.AllocationTokenExtensionInfoDataType{}
+	require.Nil(t, epp.Decode(tr.send(s, epp.EppOk, info, infoToken), &types.Response{Extension: &ext}))
+	assert.Equal(t, "abc123", ext.AllocationToken)
+
+	tr.send(other, epp.EppAuthorisationError, info, infoToken)
+
+	// Transfers are verified and use the token when one is given.
+	tokens.Add(AllocationToken{Token: "move", Names: []string{"reserved.se"}, ClientIDs: []string{"ClientY"}})
+
+	transfer := domainTransfer("reserved.se", types.TransferOperationRequest, "2fooBAR")
+
+	tr.send(other, epp.EppAuthorisationError, transfer, allocationToken("abc123"))
+	tr.send(other, epp.EppOkPending, transfer, allocationToken("move"))
+
+	token, err = tokens.AllocationToken("move")
+	require.Nil(t, err)
+	assert.NotNil(t, token.UsedDate)
+
+	// Applications keep the token and use it when they are allocated, so a
+	// token can only back one allocated application.
+	tokens.Add(AllocationToken{Token: "sunrise"})
+	tr.registry.Launch = &Launch{
+		Phases: []LaunchPhase{{Phase: launchPhase(types.LaunchPhaseSunrise), Start: tr.now, Applications: true}},
+	}
+
+	application := launchCreate(types.LaunchPhaseSunrise, "c21k")
+	application.Create.Type = types.LaunchObjectApplication
+
+	applicationID := func(name string) string {
+		created := types.LaunchExtensionCreateDataType{}
+		response := tr.send(s, epp.EppOkPending, domainCreate(name), application, allocationToken("sunrise"))
+		require.Nil(t, epp.Decode(response, &types.Response{Extension: &created}))
+
+		return created.CreateData.ApplicationID
+	}
+
+	first, second := applicationID("first.se"), applicationID("second.se")
+
+	token, err = tokens.AllocationToken("sunrise")
+	require.Nil(t, err)
+	assert.Nil(t, token.UsedDate)
+
+	require.Nil(t, tr.registry.SetApplicationStatus(first, types.LaunchStatusAllocated))
+
+	token, err = tokens.AllocationToken("sunrise")
+	require.Nil(t, err)
+	assert.Equal(t, "first.se", token.UsedBy)
+
+	info.Info.Name.Name = "first.se"
+	ext = types.AllocationTokenExtensionInfoDataType{}
+	require.Nil(t, epp.Decode(tr.send(s, epp.EppOk, info, infoToken), &types.Response{Extension: &ext}))
+	assert.Equal(t, "sunrise", ext.AllocationToken)
+
+	err = tr.registry.SetApplicationStatus(second, types.LaunchStatusAllocated)
+	require.NotNil(t, err)
+	assert.Equal(t, epp.EppAuthorisationError, err.(*Error).Code)
+
+	_, err = tr.registry.Repository.Domain("second.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+}
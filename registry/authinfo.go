@@ -0,0 +1,130 @@
+package registry
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/bombsimon/epp-go/types"
+)
+
+// 해시된 인증 정보의 접두사입니다.
+const authInfoHashPrefix = "sha256$"
+
+// 안전한 인증 정보 처리 정책입니다. (RFC 9154) 도메인의 인증 정보는 솔트를 추가한
+// 해시로 저장되고 info 응답에는 설정 여부만 반환됩니다. 인증 정보는 이전이 필요할 때
+// 클라이언트가 생성해 설정하며, 이전이 완료되면 해제됩니다.
+type SecureAuthInfo struct {
+	// 인증 정보가 설정된 후 유효한 기간입니다. 기간이 지난 인증 정보는 해제된 것으로
+	// 처리합니다. 0 이면 만료되지 않습니다.
+	TTL time.Duration
+}
+
+// 도메인의 인증 정보를 설정합니다. 빈 인증 정보는 인증 정보를 해제합니다.
+// SecureAuthInfo 가 설정되어 있으면 인증 정보를 해시로 저장합니다.
+func (r *Registry) setDomainAuthInfo(d *Domain, authInfo *types.AuthInfo) error {
+	password := ""
+	if authInfo != nil && authInfo.Null == nil {
+		password = authInfo.Password
+	}
+
+	if r.SecureAuthInfo == nil {
+		d.AuthInfo = password
+		return nil
+	}
+
+	if password == "" {
+		d.AuthInfo, d.AuthInfoDate = "", nil
+		return nil
+	}
+
+	hash, err := hashAuthInfo(password)
+	if err != nil {
+		return err
+	}
+
+	now := r.now()
+	d.AuthInfo, d.AuthInfoDate = hash, &now
+
+	return nil
+}
+
+// 주어진 인증 정보가 도메인의 인증 정보와 같은지 확인합니다. 해제되었거나 만료된
+// 인증 정보는 어떤 인증 정보와도 일치하지 않습니다.
+func (r *Registry) validDomainAuthInfo(authInfo *types.AuthInfo, d *Domain) bool {
+	if r.SecureAuthInfo == nil || !strings.HasPrefix(d.AuthInfo, authInfoHashPrefix) {
+		return validAuthInfo(authInfo, d.AuthInfo)
+	}
+
+	if authInfo == nil || authInfo.Password == "" {
+		return false
+	}
+
+	if ttl := r.SecureAuthInfo.TTL; ttl > 0 && d.AuthInfoDate != nil && !r.now().Before(d.AuthInfoDate.Add(ttl)) {
+		return false
+	}
+
+	return verifyAuthInfo(d.AuthInfo, authInfo.Password)
+}
+
+// 관리 클라이언트의 info 응답에 반환할 인증 정보입니다. SecureAuthInfo 가 설정되어
+// 있으면 인증 정보를 반환하지 않고, 설정되어 있고 만료되지 않았으면 빈 pw 를,
+// 해제되었으면 nil 을 반환합니다. (RFC 9154 4.1)
+func (r *Registry) domainAuthInfo(d *Domain) *types.AuthInfo {
+	if r.SecureAuthInfo == nil {
+		return &types.AuthInfo{Password: d.AuthInfo}
+	}
+
+	if d.AuthInfo == "" {
+		return nil
+	}
+
+	if ttl := r.SecureAuthInfo.TTL; ttl > 0 && d.AuthInfoDate != nil && !r.now().Before(d.AuthInfoDate.Add(ttl)) {
+		return nil
+	}
+
+	return &types.AuthInfo{}
+}
+
+// 이전이 완료된 도메인의 인증 정보를 해제합니다. (RFC 9154 4.4)
+func (r *Registry) clearDomainAuthInfo(d *Domain) {
+	if r.SecureAuthInfo != nil {
+		d.AuthInfo, d.AuthInfoDate = "", nil
+	}
+}
+
+// 128 비트의 임의의 솔트를 추가한 SHA-256 해시를 반환합니다.
+func hashAuthInfo(password string) (string, error) {
+	salt := make([]byte, 16)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	return authInfoHash(salt, password), nil
+}
+
+func authInfoHash(salt []byte, password string) string {
+	sum := sha256.Sum256(append(append([]byte{}, salt...), password...))
+
+	return authInfoHashPrefix +
+		base64.RawStdEncoding.EncodeToString(salt) + "$" +
+		base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+func verifyAuthInfo(hash, password string) bool {
+	parts := strings.Split(strings.TrimPrefix(hash, authInfoHashPrefix), "$")
+	if len(parts) != 2 {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(authInfoHash(salt, password)), []byte(hash)) == 1
+}
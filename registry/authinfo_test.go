@@ -0,0 +1,121 @@
+package registry
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func domainChangeAuthInfo(name string, authInfo *types.AuthInfo) types.DomainUpdateType {
+	return types.DomainUpdateType{
+		Update: types.DomainUpdate{
+			Name:   name,
+			Change: &types.DomainChange{AuthInfo: authInfo},
+		},
+	}
+}
+
+func testRegistrySecureAuthInfo(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+	day := 24 * time.Hour
+
+	tr.registry.SecureAuthInfo = &SecureAuthInfo{TTL: 14 * day}
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceSecureAuthInfoTransfer10)
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+
+	// The authorization information is only stored as a salted hash.
+	stored := tr.domain("example.se")
+	assert.True(t, strings.HasPrefix(stored.AuthInfo, authInfoHashPrefix))
+	assert.NotContains(t, stored.AuthInfo, "2fooBAR")
+	assert.True(t, tr.now.Equal(*stored.AuthInfoDate))
+
+	// Info never returns the authorization information, not even to the
+	// sponsoring client, but can be used to verify it. The sponsor gets an
+	// empty password while it's set.
+	info := types.DomainInfoType{Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}}}
+	sponsorAuthInfo := func(session *epp.Session) *types.AuthInfo {
+		sponsorInfo := types.DomainInfoType{Info: types.DomainInfo{Name: info.Info.Name}}
+		response := tr.send(session, epp.EppOk, sponsorInfo)
+
+		infoData := types.DomainInfoDataType{}
+		decodeResData(t, response, &infoData)
+
+		if infoData.InfoData.AuthInfo != nil {
+			assert.Contains(t, string(response), "<domain:pw />")
+		}
+
+		return infoData.InfoData.AuthInfo
+	}
+
+	authInfo := sponsorAuthInfo(s)
+	require.NotNil(t, authInfo)
+	assert.Empty(t, authInfo.Password)
+
+	info.Info.AuthInfo = &types.AuthInfo{Password: "wrong"}
+	tr.send(other, epp.EppInvalidAuthInfo, info)
+
+	info.Info.AuthInfo = &types.AuthInfo{Password: "2fooBAR"}
+	infoData := types.DomainInfoDataType{}
+	decodeResData(t, tr.send(other, epp.EppOk, info), &infoData)
+	assert.Nil(t, infoData.InfoData.AuthInfo)
+	assert.Equal(t, "jd1234", infoData.InfoData.Registrant)
+
+	// The authorization information is unset when the transfer completes.
+	tr.transfer(other, epp.EppInvalidAuthInfo, types.TransferOperationRequest, "wrong")
+	tr.transfer(other, epp.EppOkPending, types.TransferOperationRequest, "2fooBAR")
+	tr.transfer(s, epp.EppOk, types.TransferOperationApprove, "")
+
+	stored = tr.domain("example.se")
+	assert.Empty(t, stored.AuthInfo)
+	assert.Nil(t, stored.AuthInfoDate)
+	assert.Nil(t, sponsorAuthInfo(other))
+
+	tr.transfer(s, epp.EppInvalidAuthInfo, types.TransferOperationRequest, "2fooBAR")
+
+	// The new sponsor sets the authorization information when it's needed,
+	// it's only valid until the TTL has passed.
+	tr.send(other, epp.EppOk, domainChangeAuthInfo("example.se", &types.AuthInfo{Password: "n3wPassw0rd"}))
+	assert.NotNil(t, sponsorAuthInfo(other))
+
+	tr.now = tr.now.Add(14 * day)
+	tr.transfer(s, epp.EppInvalidAuthInfo, types.TransferOperationRequest, "n3wPassw0rd")
+	assert.Nil(t, sponsorAuthInfo(other))
+
+	tr.send(other, epp.EppOk, domainChangeAuthInfo("example.se", &types.AuthInfo{Password: "n3wPassw0rd"}))
+	assert.True(t, tr.now.Equal(*tr.domain("example.se").AuthInfoDate))
+
+	// An empty authorization information with null unsets it.
+	tr.send(other, epp.EppOk, domainChangeAuthInfo("example.se", &types.AuthInfo{Null: types.Empty()}))
+	assert.Empty(t, tr.domain("example.se").AuthInfo)
+	tr.transfer(s, epp.EppInvalidAuthInfo, types.TransferOperationRequest, "n3wPassw0rd")
+
+	// Domains can be created with an empty authorization information.
+	create := domainCreate("example.nu")
+	create.Create.AuthInfo.Password = "unset"
+
+	data, err := epp.NewCommand(create).WithClientTransactionID("ABC-12345").Encode()
+	require.Nil(t, err)
+
+	data = bytes.Replace(data, []byte(">unset<"), []byte("><"), 1)
+	require.Nil(t, tr.validator.Validate(data), string(data))
+
+	response, err := tr.mux.Handle(s, data)
+	require.Nil(t, err)
+
+	result := types.Response{}
+	require.Nil(t, epp.Decode(response, &result))
+	assert.Equal(t, epp.EppOk.Code(), result.Result[0].Code, string(response))
+	assert.Empty(t, tr.domain("example.nu").AuthInfo)
+}
@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"fmt"
+
+	"github.com/bombsimon/epp-go/types"
+	"github.com/google/uuid"
+)
+
+// 서버에서 개체를 바꾼 작업입니다. (RFC 8590) Registry.ChangePoll 이 설정되면 작업은
+// 바뀌기 전과 후의 개체 정보와 함께 changePoll-1.0 확장으로 관리 클라이언트에게
+// 전달됩니다.
+type Change struct {
+	Operation types.ChangePollOperationType
+
+	// custom 작업의 이름이나 작업의 세부 내용입니다.
+	Op string
+
+	// 개체를 바꾼 사용자나 프로세스입니다. 빈 문자열이면 ServerID 를 사용합니다.
+	Who string
+
+	// 개체를 바꾼 이유입니다.
+	Reason string
+
+	// 작업의 원인이 된 분쟁 사건입니다. (예: UDRP)
+	CaseID *types.ChangePollCaseID
+}
+
+// 서버에서 도메인을 바꾸고 관리 클라이언트에게 서비스 메시지를 보냅니다. fn 이 반환한
+// 오류는 그대로 반환되며 도메인은 바뀌지 않습니다.
+//
+// ChangePoll 이 설정되면 바뀌기 전과 후의 도메인 정보를 각각 하나의 메시지로 보내고,
+// 아니면 도메인이 바뀌었다는 메시지만 보냅니다.
+func (r *Registry) ChangeDomain(name string, change Change, fn func(d *Domain) error) error {
+	var notices []notice
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		d, err := tx.Domain(normalize(name))
+		if err != nil {
+			return notFound(err, "domain %s does not exist", name)
+		}
+
+		message := fmt.Sprintf("Domain %s was updated by the registry.", d.Name)
+		changeData := r.changeData(change)
+
+		before, err := r.domainChangeNotice(tx, d.copy(), message, changeData, types.ChangePollStateBefore)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(d); err != nil {
+			return err
+		}
+
+		now := r.now()
+		d.UpdateDate = &now
+
+		if err := tx.UpdateDomain(d); err != nil {
+			return err
+		}
+
+		if !r.ChangePoll {
+			notices = append(notices, before)
+			return nil
+		}
+
+		after, err := r.domainChangeNotice(tx, d, message, changeData, types.ChangePollStateAfter)
+		if err != nil {
+			return err
+		}
+
+		notices = append(notices, before, after)
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	r.sendNotices(notices)
+
+	return nil
+}
+
+// 작업의 changeData 를 생성합니다. 같은 작업의 메시지들은 같은 서버 트랜잭션 ID 와
+// 날짜를 사용합니다.
+func (r *Registry) changeData(change Change) *types.ChangePollChangeData {
+	who := change.Who
+	if who == "" {
+		who = r.ServerID
+	}
+
+	cd := &types.ChangePollChangeData{
+		Operation: types.ChangePollOperation{
+			Operation: change.Operation,
+			Op:        change.Op,
+		},
+		Date:                r.now(),
+		ServerTransactionID: uuid.New().String(),
+		Who:                 who,
+		CaseID:              change.CaseID,
+	}
+
+	if change.Reason != "" {
+		cd.Reason = &types.MessageQueueMessage{Value: change.Reason}
+	}
+
+	return cd
+}
+
+// 서버에서 바꾼 도메인의 서비스 메시지를 생성합니다. ChangePoll 이 설정되면 주어진
+// 상태의 도메인 정보와 changeData 를 포함합니다.
+func (r *Registry) domainChangeNotice(tx Repository, d *Domain, message string, change *types.ChangePollChangeData, state types.ChangePollStateType) (notice, error) {
+	n := notice{
+		clientID: d.ClientID,
+		message:  message,
+	}
+
+	if !r.ChangePoll {
+		return n, nil
+	}
+
+	info, err := r.domainInfoData(tx, d, types.DomainHostsAll)
+	if err != nil {
+		return n, err
+	}
+
+	cd := *change
+	cd.State = state
+
+	n.data = &types.PollResultData{DomainInfoData: info}
+	n.change = &cd
+
+	return n, nil
+}
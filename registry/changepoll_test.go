@@ -0,0 +1,91 @@
+package registry
+
+import (
+	"testing"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 가장 오래된 서비스 메시지를 확인하고 도메인 정보와 changeData 를 반환합니다.
+func pollChange(t *testing.T, tr *testRegistry, s *epp.Session) (*types.DomainInfoData, types.ChangePollChangeData) {
+	response := tr.send(s, epp.EppOkMessages, pollCommand(types.PollOperationRequest, ""))
+
+	data := types.PollResultData{}
+	ext := types.ChangePollExtensionChangeDataType{}
+	result := types.Response{ResultData: &data, Extension: &ext}
+	require.Nil(t, epp.Decode(response, &result))
+
+	tr.send(s, epp.EppOk, pollCommand(types.PollOperationAcknowledge, result.MessageQ.ID))
+
+	return data.DomainInfoData, ext.ChangeData
+}
+
+func testRegistryChangePoll(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+
+	hold := func(d *Domain) error {
+		d.Status = append(d.Status, types.DomainStatusServerHold)
+		return nil
+	}
+
+	// Without the extension only a message is sent.
+	require.Nil(t, tr.registry.ChangeDomain("example.se", Change{Operation: types.ChangePollOperationUpdate}, hold))
+	assert.Equal(t, 1, tr.messageCount("ClientX"))
+
+	info, _ := pollChange(t, tr, s)
+	assert.Nil(t, info)
+
+	tr.registry.ChangePoll = true
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceChangePoll10)
+
+	assert.NotNil(t, tr.registry.ChangeDomain("unknown.se", Change{Operation: types.ChangePollOperationUpdate}, hold))
+
+	// Server changes are sent with the domain before and after the change.
+	require.Nil(t, tr.registry.ChangeDomain("example.se", Change{
+		Operation: types.ChangePollOperationUpdate,
+		Who:       "URS Admin",
+		Reason:    "URS Lock",
+		CaseID:    &types.ChangePollCaseID{Type: types.ChangePollCaseURS, Value: "urs123"},
+	}, func(d *Domain) error {
+		d.Status = []types.DomainStatusType{types.DomainStatusServerUpdateProhibited}
+		return nil
+	}))
+	assert.Equal(t, 2, tr.messageCount("ClientX"))
+
+	before, beforeChange := pollChange(t, tr, s)
+	require.NotNil(t, before)
+	assert.Equal(t, "example.se", before.Name)
+	assert.Equal(t, types.DomainStatusServerHold, before.Status[0].DomainStatusType)
+	assert.Equal(t, types.ChangePollStateBefore, beforeChange.State)
+	assert.Equal(t, types.ChangePollOperationUpdate, beforeChange.Operation.Operation)
+	assert.Equal(t, "URS Admin", beforeChange.Who)
+	assert.Equal(t, "URS Lock", beforeChange.Reason.Value)
+	assert.Equal(t, "urs123", beforeChange.CaseID.Value)
+
+	after, afterChange := pollChange(t, tr, s)
+	require.NotNil(t, after)
+	assert.Equal(t, types.DomainStatusServerUpdateProhibited, after.Status[0].DomainStatusType)
+	assert.Equal(t, types.ChangePollStateAfter, afterChange.State)
+	assert.Equal(t, beforeChange.ServerTransactionID, afterChange.ServerTransactionID)
+	assert.True(t, tr.now.Equal(afterChange.Date))
+
+	// Changes made by the lifecycle engine are sent with the extension.
+	tr.now = tr.now.AddDate(2, 0, 1)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	info, change := pollChange(t, tr, s)
+	require.NotNil(t, info)
+	assert.True(t, tr.now.AddDate(1, 0, -1).Equal(*info.ExpireDate))
+	assert.Equal(t, types.ChangePollOperationAutoRenew, change.Operation.Operation)
+	assert.Equal(t, tr.registry.ServerID, change.Who)
+	assert.Nil(t, change.Reason)
+}
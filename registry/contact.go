@@ -0,0 +1,487 @@
+package registry
+
+import (
+	"strings"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 클라이언트가 추가하거나 제거할 수 있는 연락처 상태입니다.
+var clientContactStatuses = map[types.ContactStatusType]struct{}{
+	types.ContactStatusClientDeleteProhibited:   {},
+	types.ContactStatusClientTransferProhibited: {},
+	types.ContactStatusClientUpdateProhibited:   {},
+}
+
+func (r *Registry) checkContact(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.ContactCheckType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	result := types.ContactCheckDataType{}
+
+	for _, id := range cmd.Check.Names {
+		cd := types.CheckContact{
+			Name: types.CheckName{
+				Value:     id,
+				Available: true,
+			},
+		}
+
+		if _, err := r.Repository.Contact(id); err == nil {
+			cd.Name.Available = false
+			cd.Reason = "In use"
+		} else if errors.Cause(err) != ErrObjectNotFound {
+			return nil, err
+		}
+
+		result.CheckData.Name = append(result.CheckData.Name, cd)
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(result), nil
+}
+
+func (r *Registry) infoContact(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.ContactInfoType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	c, err := r.Repository.Contact(cmd.Info.Name)
+	if err != nil {
+		return nil, notFound(err, "contact %s does not exist", cmd.Info.Name)
+	}
+
+	isSponsor := c.ClientID == s.ClientID
+
+	if !isSponsor && !validAuthInfo(cmd.Info.AuthInfo, c.AuthInfo) {
+		if cmd.Info.AuthInfo != nil {
+			return nil, errorf(epp.EppInvalidAuthInfo, "invalid authorization information")
+		}
+
+		return nil, errorf(epp.EppAuthorisationError, "contact %s is not sponsored by %s", c.ID, s.ClientID)
+	}
+
+	status, err := r.contactStatus(r.Repository, c)
+	if err != nil {
+		return nil, err
+	}
+
+	info := types.ContactInfoData{
+		Name:         c.ID,
+		ROID:         c.ROID,
+		Status:       status,
+		PostalInfo:   c.PostalInfo,
+		Voice:        c.Voice,
+		Fax:          c.Fax,
+		Email:        c.Email,
+		ClientID:     c.ClientID,
+		CreateID:     c.CreateID,
+		CreateDate:   c.CreateDate,
+		UpdateID:     c.UpdateID,
+		UpdateDate:   c.UpdateDate,
+		TransferDate: c.TransferDate,
+		Disclose:     c.Disclose,
+	}
+
+	// 인증 정보는 관리 클라이언트에게만 반환합니다.
+	if isSponsor {
+		info.AuthInfo = &types.AuthInfo{Password: c.AuthInfo}
+	}
+
+	response := epp.NewResponse(epp.EppOk).WithResData(types.ContactInfoDataType{InfoData: info})
+
+	return withOrganizations(response, c.Organizations), nil
+}
+
+func (r *Registry) createContact(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.ContactCreateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if len(cmd.Create.PostalInfo) == 0 {
+		return nil, errorf(epp.EppMissingParam, "postal info is required")
+	}
+
+	if cmd.Create.AuthInfo.Password == "" {
+		return nil, errorf(epp.EppMissingParam, "authorization information is required")
+	}
+
+	orgs, err := r.orgExtensionCreate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	c := &Contact{
+		ID:            cmd.Create.ID,
+		PostalInfo:    cmd.Create.PostalInfo,
+		Voice:         cmd.Create.Voice,
+		Fax:           cmd.Create.Fax,
+		Email:         cmd.Create.Email,
+		ClientID:      s.ClientID,
+		CreateID:      s.ClientID,
+		CreateDate:    now,
+		AuthInfo:      cmd.Create.AuthInfo.Password,
+		Disclose:      cmd.Create.Disclose,
+		Organizations: orgs,
+	}
+
+	err = r.Repository.Transaction(func(tx Repository) error {
+		if _, err := tx.Contact(c.ID); err == nil {
+			return errorf(epp.EppObjectExists, "contact %s already exists", c.ID)
+		} else if errors.Cause(err) != ErrObjectNotFound {
+			return err
+		}
+
+		if err := checkOrgLinks(tx, c.ClientID, c.Organizations); err != nil {
+			return err
+		}
+
+		roid, err := r.roid(tx, "C")
+		if err != nil {
+			return err
+		}
+
+		c.ROID = roid
+
+		return tx.CreateContact(c)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.ContactCreateDataType{
+		CreateData: types.ContactCreateData{
+			Name:       c.ID,
+			CreateDate: c.CreateDate,
+		},
+	}), nil
+}
+
+func (r *Registry) updateContact(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.ContactUpdateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	id := cmd.Update.Name
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		c, err := r.sponsoredContact(tx, s, id)
+		if err != nil {
+			return err
+		}
+
+		removesUpdateProhibited := false
+
+		if cmd.Update.Remove != nil {
+			for _, status := range cmd.Update.Remove.Status {
+				if status.ContactStatusType == types.ContactStatusClientUpdateProhibited {
+					removesUpdateProhibited = true
+				}
+			}
+		}
+
+		if c.hasStatus(types.ContactStatusServerUpdateProhibited) ||
+			(c.hasStatus(types.ContactStatusClientUpdateProhibited) && !removesUpdateProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "contact %s has status update prohibited", id)
+		}
+
+		if c.hasStatus(types.ContactStatusPendingTransfer, types.ContactStatusPendingDelete) {
+			return errorf(epp.EppStatusProhibitsOp, "contact %s has a pending status", id)
+		}
+
+		if rem := cmd.Update.Remove; rem != nil {
+			for _, status := range rem.Status {
+				if _, ok := clientContactStatuses[status.ContactStatusType]; !ok {
+					return errorf(epp.EppParamPolicyError, "status %s can not be removed by the client", status.ContactStatusType)
+				}
+
+				found := false
+
+				for i, existing := range c.Status {
+					if existing == status.ContactStatusType {
+						c.Status = append(c.Status[:i], c.Status[i+1:]...)
+						found = true
+
+						break
+					}
+				}
+
+				if !found {
+					return errorf(epp.EppParamPolicyError, "contact %s does not have status %s", id, status.ContactStatusType)
+				}
+			}
+		}
+
+		if add := cmd.Update.Add; add != nil {
+			for _, status := range add.Status {
+				if _, ok := clientContactStatuses[status.ContactStatusType]; !ok {
+					return errorf(epp.EppParamPolicyError, "status %s can not be added by the client", status.ContactStatusType)
+				}
+
+				if c.hasStatus(status.ContactStatusType) {
+					return errorf(epp.EppParamPolicyError, "contact %s already has status %s", id, status.ContactStatusType)
+				}
+
+				c.Status = append(c.Status, status.ContactStatusType)
+			}
+		}
+
+		if chg := cmd.Update.Change; chg != nil {
+			changeContact(c, chg)
+		}
+
+		orgs, err := r.orgExtensionUpdate(data, c.Organizations)
+		if err != nil {
+			return err
+		}
+
+		if err := checkOrgLinks(tx, c.ClientID, newOrgLinks(c.Organizations, orgs)); err != nil {
+			return err
+		}
+
+		c.Organizations = orgs
+
+		now := r.now()
+		c.UpdateID = s.ClientID
+		c.UpdateDate = &now
+
+		return tx.UpdateContact(c)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func (r *Registry) deleteContact(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.ContactDeleteType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	id := cmd.Delete.Name
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		c, err := r.sponsoredContact(tx, s, id)
+		if err != nil {
+			return err
+		}
+
+		if c.hasStatus(types.ContactStatusClientDeleteProhibited, types.ContactStatusServerDeleteProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "contact %s has status delete prohibited", id)
+		}
+
+		if c.hasStatus(types.ContactStatusPendingTransfer, types.ContactStatusPendingDelete) {
+			return errorf(epp.EppStatusProhibitsOp, "contact %s has a pending status", id)
+		}
+
+		domains, err := tx.DomainsByContact(id)
+		if err != nil {
+			return err
+		}
+
+		if len(domains) > 0 {
+			return errorf(epp.EppAssocProhibitsOp, "contact %s is linked to domain %s", id, strings.Join(domains, ", "))
+		}
+
+		return tx.DeleteContact(id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func (r *Registry) transferContact(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.ContactTransferType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	id := cmd.Transfer.Contact.Name
+	authInfo := cmd.Transfer.Contact.AuthInfo
+
+	var c *Contact
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		var err error
+
+		c, err = tx.Contact(id)
+		if err != nil {
+			return notFound(err, "contact %s does not exist", id)
+		}
+
+		switch cmd.Transfer.Operation {
+		case types.TransferOperationQuery:
+			if c.Transfer == nil {
+				return errorf(epp.EppObjectNotPendingTransfer, "contact %s has no transfer", id)
+			}
+
+			if c.ClientID != s.ClientID && c.Transfer.RequestingID != s.ClientID && c.Transfer.ActingID != s.ClientID && !validAuthInfo(authInfo, c.AuthInfo) {
+				return errorf(epp.EppAuthorisationError, "not authorized to query transfer for contact %s", id)
+			}
+
+			return nil
+		case types.TransferOperationRequest:
+			return r.requestContactTransfer(tx, s, c, authInfo)
+		default:
+			// 이전 요청은 즉시 승인되므로 처리할 이전 요청이 없습니다.
+			return errorf(epp.EppObjectNotPendingTransfer, "contact %s is not pending transfer", id)
+		}
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.ContactTransferDataType{
+		TransferData: types.ContactTransferData{
+			Name:           c.ID,
+			TransferStatus: types.ContactTransferStatusType(c.Transfer.Status),
+			RequestingID:   c.Transfer.RequestingID,
+			RequestingDate: c.Transfer.RequestingDate,
+			ActingID:       c.Transfer.ActingID,
+			ActingDate:     c.Transfer.ActingDate,
+		},
+	}), nil
+}
+
+// 연락처 이전을 요청합니다. 이전 요청은 서버에서 즉시 승인됩니다.
+func (r *Registry) requestContactTransfer(tx Repository, s *epp.Session, c *Contact, authInfo *types.AuthInfo) error {
+	if c.ClientID == s.ClientID {
+		return errorf(epp.EppNotTransferrable, "contact %s is already sponsored by %s", c.ID, s.ClientID)
+	}
+
+	if !validAuthInfo(authInfo, c.AuthInfo) {
+		return errorf(epp.EppInvalidAuthInfo, "invalid authorization information")
+	}
+
+	if c.hasStatus(types.ContactStatusClientTransferProhibited, types.ContactStatusServerTransferProhibited) {
+		return errorf(epp.EppStatusProhibitsOp, "contact %s has status transfer prohibited", c.ID)
+	}
+
+	if c.hasStatus(types.ContactStatusPendingTransfer) {
+		return errorf(epp.EppObjectPendingTransfer, "contact %s is already pending transfer", c.ID)
+	}
+
+	if c.hasStatus(types.ContactStatusPendingDelete) {
+		return errorf(epp.EppStatusProhibitsOp, "contact %s has a pending status", c.ID)
+	}
+
+	now := r.now()
+
+	c.Transfer = &Transfer{
+		Status:         types.DomainTransferServerApproved,
+		RequestingID:   s.ClientID,
+		RequestingDate: now,
+		ActingID:       c.ClientID,
+		ActingDate:     now,
+	}
+
+	c.ClientID = s.ClientID
+	c.TransferDate = &now
+
+	return tx.UpdateContact(c)
+}
+
+// 연락처를 관리하는 클라이언트로 로그인 되어 있는 경우에만 연락처를 반환합니다.
+func (r *Registry) sponsoredContact(tx Repository, s *epp.Session, id string) (*Contact, error) {
+	c, err := tx.Contact(id)
+	if err != nil {
+		return nil, notFound(err, "contact %s does not exist", id)
+	}
+
+	if c.ClientID != s.ClientID {
+		return nil, errorf(epp.EppAuthorisationError, "contact %s is not sponsored by %s", id, s.ClientID)
+	}
+
+	return c, nil
+}
+
+// 연락처의 상태를 반환합니다. 도메인에서 사용되는 연락처는 linked 상태를 가지며,
+// 다른 상태가 없는 경우 ok 상태를 가집니다.
+func (r *Registry) contactStatus(tx Repository, c *Contact) ([]types.ContactStatus, error) {
+	status := []types.ContactStatus{}
+
+	for _, s := range c.Status {
+		status = append(status, types.ContactStatus{ContactStatusType: s})
+	}
+
+	domains, err := tx.DomainsByContact(c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(domains) > 0 {
+		status = append(status, types.ContactStatus{ContactStatusType: types.ContactStatusLinked})
+	}
+
+	if len(status) == 0 || (len(status) == 1 && len(domains) > 0) {
+		status = append([]types.ContactStatus{{ContactStatusType: types.ContactStatusOk}}, status...)
+	}
+
+	return status, nil
+}
+
+// 연락처 갱신 명령어의 변경 사항을 적용합니다. 우편 정보는 같은 유형의 정보를
+// 대체하며, 이름이 없는 경우 기존 이름을 유지합니다.
+func changeContact(c *Contact, chg *types.ContactChange) {
+	for _, pi := range chg.PostalInfo {
+		replaced := false
+
+		for i, existing := range c.PostalInfo {
+			if existing.Type != pi.Type {
+				continue
+			}
+
+			if pi.Name == "" {
+				pi.Name = existing.Name
+			}
+
+			c.PostalInfo[i] = pi
+			replaced = true
+		}
+
+		if !replaced {
+			c.PostalInfo = append(c.PostalInfo, pi)
+		}
+	}
+
+	if chg.Voice != nil {
+		c.Voice = chg.Voice
+	}
+
+	if chg.Fax != nil {
+		c.Fax = chg.Fax
+	}
+
+	if chg.Email != "" {
+		c.Email = chg.Email
+	}
+
+	if chg.AuthInfo != nil {
+		c.AuthInfo = chg.AuthInfo.Password
+	}
+
+	if chg.Disclose != nil {
+		c.Disclose = chg.Disclose
+	}
+}
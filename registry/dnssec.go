@@ -0,0 +1,171 @@
+package registry
+
+import (
+	"strings"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/dnssec"
+	"github.com/bombsimon/epp-go/types"
+)
+
+// 도메인 명령어의 secDNS-1.1 확장입니다. 명령어에 없는 확장은 nil 입니다.
+type dnssecExtensions struct {
+	Create *types.DNSSECOrKeyData       `xml:"urn:ietf:params:xml:ns:secDNS-1.1 command>extension>create"`
+	Update *types.DNSSECExtensionUpdate `xml:"urn:ietf:params:xml:ns:secDNS-1.1 command>extension>update"`
+}
+
+func (r *Registry) dnssecExtensions(data []byte) (*dnssecExtensions, error) {
+	ext := &dnssecExtensions{}
+
+	if err := epp.Decode(data, ext); err != nil {
+		return nil, err
+	}
+
+	if (ext.Create != nil || ext.Update != nil) && r.DNSSEC == nil {
+		return nil, errorf(epp.EppUnimplementedExtension, "the secDNS extension is not supported")
+	}
+
+	return ext, nil
+}
+
+// 명령어의 secDNS-1.1 create 확장을 검증하고 도메인에 등록할 위임 정보를 반환합니다.
+// 확장이 없으면 nil 을 반환합니다.
+func (r *Registry) dnssecExtensionCreate(data []byte, name string) (*types.DNSSECOrKeyData, error) {
+	ext, err := r.dnssecExtensions(data)
+	if err != nil || ext.Create == nil {
+		return nil, err
+	}
+
+	if err := r.DNSSEC.ValidateCreate(name, *ext.Create); err != nil {
+		return nil, dnssecError(err)
+	}
+
+	return ext.Create, nil
+}
+
+// 명령어의 secDNS-1.1 update 확장을 도메인의 위임 정보에 적용합니다. 확장이 없으면
+// 아무것도 바꾸지 않습니다. (RFC 5910 3.2.5)
+func (r *Registry) dnssecExtensionUpdate(data []byte, d *Domain) error {
+	ext, err := r.dnssecExtensions(data)
+	if err != nil || ext.Update == nil {
+		return err
+	}
+
+	update := *ext.Update
+
+	// 긴급 처리는 지원하지 않습니다.
+	if update.Urgent {
+		return errorf(epp.EppUnimplementedOption, "urgent secDNS updates are not supported")
+	}
+
+	if err := r.DNSSEC.ValidateUpdate(d.Name, update); err != nil {
+		return dnssecError(err)
+	}
+
+	sec := types.DNSSECOrKeyData{}
+	if d.DNSSEC != nil {
+		sec = *d.copy().DNSSEC
+	}
+
+	if rem := update.Remove; rem != nil {
+		if rem.All {
+			sec.DNSSECData = nil
+			sec.KeyData = nil
+		}
+
+		for _, ds := range rem.DNSSECdata {
+			i := indexOfDS(sec.DNSSECData, ds)
+			if i < 0 {
+				return errorf(epp.EppParamPolicyError, "DS %d is not associated with domain %s", ds.KeyTag, d.Name)
+			}
+
+			sec.DNSSECData = append(sec.DNSSECData[:i], sec.DNSSECData[i+1:]...)
+		}
+
+		for _, key := range rem.KeyData {
+			i := indexOfKeyData(sec.KeyData, key)
+			if i < 0 {
+				return errorf(epp.EppParamPolicyError, "DNSKEY is not associated with domain %s", d.Name)
+			}
+
+			sec.KeyData = append(sec.KeyData[:i], sec.KeyData[i+1:]...)
+		}
+	}
+
+	if add := update.Add; add != nil {
+		for _, ds := range add.DNSSECData {
+			if indexOfDS(sec.DNSSECData, ds) < 0 {
+				sec.DNSSECData = append(sec.DNSSECData, ds)
+			}
+		}
+
+		for _, key := range add.KeyData {
+			if indexOfKeyData(sec.KeyData, key) < 0 {
+				sec.KeyData = append(sec.KeyData, key)
+			}
+		}
+
+		if add.MaxSignatureLife > 0 {
+			sec.MaxSignatureLife = add.MaxSignatureLife
+		}
+	}
+
+	if update.ChangeMaxSignatureLife > 0 {
+		sec.MaxSignatureLife = update.ChangeMaxSignatureLife
+	}
+
+	// 위임 정보가 모두 삭제되면 서명의 유효 기간도 함께 삭제합니다.
+	if len(sec.DNSSECData) == 0 && len(sec.KeyData) == 0 {
+		d.DNSSEC = nil
+		return nil
+	}
+
+	d.DNSSEC = &sec
+
+	return nil
+}
+
+// 도메인에 위임 정보가 있으면 secDNS-1.1 확장의 infData 를 응답에 추가합니다.
+func withDNSSEC(response *epp.ResponseBuilder, d *Domain) *epp.ResponseBuilder {
+	if d.DNSSEC == nil {
+		return response
+	}
+
+	return response.WithExtension(types.DNSSECExtensionInfoDataType{InfoData: *d.DNSSEC})
+}
+
+// dnssec 패키지의 검증 오류를 같은 결과 코드의 오류로 바꿉니다.
+func dnssecError(err error) error {
+	if e, ok := err.(*dnssec.Error); ok {
+		return errorf(e.Code, "%s", e.Reason)
+	}
+
+	return err
+}
+
+// 키 태그, 알고리즘, 다이제스트 타입과 다이제스트가 같은 DS 의 위치를 반환합니다.
+func indexOfDS(values []types.DNSSEC, ds types.DNSSEC) int {
+	for i, v := range values {
+		if v.KeyTag == ds.KeyTag && v.Algorithm == ds.Algorithm &&
+			v.DigestType == ds.DigestType && strings.EqualFold(v.Digest, ds.Digest) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// 플래그, 프로토콜, 알고리즘과 공개 키가 같은 키 데이터의 위치를 반환합니다. 공개 키의
+// 공백은 비교하지 않습니다.
+func indexOfKeyData(values []types.DNSSECKeyData, key types.DNSSECKeyData) int {
+	publicKey := strings.Join(strings.Fields(key.PublicKey), "")
+
+	for i, v := range values {
+		if v.Flags == key.Flags && v.Protocol == key.Protocol && v.Algorithm == key.Algorithm &&
+			strings.Join(strings.Fields(v.PublicKey), "") == publicKey {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"testing"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/dnssec"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	dnssecKSK = types.DNSSECKeyData{
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dnssec.AlgorithmECDSAP256SHA256,
+		PublicKey: "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA==",
+	}
+
+	dnssecZSK = types.DNSSECKeyData{
+		Flags:     256,
+		Protocol:  3,
+		Algorithm: dnssec.AlgorithmECDSAP384SHA384,
+		PublicKey: "xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40",
+	}
+)
+
+func testRegistryDNSSEC(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+
+	ksk, err := dnssec.ComputeDS("example.se", dnssecKSK, dnssec.DigestSHA256)
+	require.Nil(t, err)
+
+	zsk, err := dnssec.ComputeDS("example.se", dnssecZSK, dnssec.DigestSHA384)
+	require.Nil(t, err)
+
+	create := types.DNSSECExtensionCreateType{
+		Create: types.DNSSECOrKeyData{MaxSignatureLife: 604800, DNSSECData: []types.DNSSEC{*ksk}},
+	}
+
+	update := types.DomainUpdateType{Update: types.DomainUpdate{Name: "example.se"}}
+
+	info := func(s *epp.Session) *types.DNSSECOrKeyData {
+		ext := types.DNSSECExtensionInfoDataType{}
+
+		response := tr.send(s, epp.EppOk, types.DomainInfoType{
+			Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}},
+		})
+		require.Nil(t, epp.Decode(response, &types.Response{Extension: &ext}))
+
+		if len(ext.InfoData.DNSSECData) == 0 && len(ext.InfoData.KeyData) == 0 {
+			return nil
+		}
+
+		return &ext.InfoData
+	}
+
+	// Without an interface the extension is not supported.
+	tr.send(s, epp.EppUnimplementedExtension, domainCreate("example.se"), create)
+
+	dsData := dnssec.DSDataInterface
+	tr.registry.DNSSEC = &dsData
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceDNSSEC11)
+
+	// The data must use the supported interface and match the domain.
+	tr.send(s, epp.EppParamPolicyError, domainCreate("example.se"), types.DNSSECExtensionCreateType{
+		Create: types.DNSSECOrKeyData{KeyData: []types.DNSSECKeyData{dnssecKSK}},
+	})
+
+	invalid := *ksk
+	invalid.Digest = "ABCDEF"
+	tr.send(s, epp.EppParamSyntaxError, domainCreate("example.se"), types.DNSSECExtensionCreateType{
+		Create: types.DNSSECOrKeyData{DNSSECData: []types.DNSSEC{invalid}},
+	})
+
+	withKey := *ksk
+	withKey.KeyData = &dnssecKSK
+	tr.send(s, epp.EppParamPolicyError, domainCreate("other.se"), types.DNSSECExtensionCreateType{
+		Create: types.DNSSECOrKeyData{DNSSECData: []types.DNSSEC{withKey}},
+	})
+
+	tr.send(s, epp.EppOk, domainCreate("example.se"), create)
+
+	sec := info(s)
+	require.NotNil(t, sec)
+	assert.Equal(t, 604800, sec.MaxSignatureLife)
+	assert.Equal(t, []types.DNSSEC{*ksk}, sec.DNSSECData)
+
+	// Other clients without authorization only get the limited info.
+	assert.Nil(t, info(other))
+
+	// Removals are applied before additions and the maximum signature life can
+	// be changed without changing the delegation.
+	tr.send(s, epp.EppOk, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{
+			Remove: &types.DNSSECRemove{DNSSECdata: []types.DNSSEC{*ksk}},
+			Add:    &types.DNSSECOrKeyData{DNSSECData: []types.DNSSEC{*zsk, *ksk}},
+		},
+	})
+	tr.send(s, epp.EppOk, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{ChangeMaxSignatureLife: 86400},
+	})
+
+	sec = info(s)
+	require.NotNil(t, sec)
+	assert.Equal(t, 86400, sec.MaxSignatureLife)
+	assert.Equal(t, []types.DNSSEC{*zsk, *ksk}, sec.DNSSECData)
+
+	tr.send(s, epp.EppParamPolicyError, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{
+			Remove: &types.DNSSECRemove{DNSSECdata: []types.DNSSEC{invalid}},
+		},
+	})
+	tr.send(s, epp.EppParamPolicyError, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{
+			Add: &types.DNSSECOrKeyData{KeyData: []types.DNSSECKeyData{dnssecZSK}},
+		},
+	})
+	tr.send(s, epp.EppUnimplementedOption, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{
+			Remove: &types.DNSSECRemove{All: true},
+			Urgent: true,
+		},
+	})
+	tr.send(other, epp.EppAuthorisationError, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{Remove: &types.DNSSECRemove{All: true}},
+	})
+
+	// Removing all data removes the delegation.
+	tr.send(s, epp.EppOk, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{Remove: &types.DNSSECRemove{All: true}},
+	})
+
+	assert.Nil(t, info(s))
+	assert.Nil(t, tr.domain("example.se").DNSSEC)
+
+	// The key data interface stores the keys instead.
+	*tr.registry.DNSSEC = dnssec.KeyDataInterface
+
+	tr.send(s, epp.EppParamPolicyError, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{Add: &types.DNSSECOrKeyData{DNSSECData: []types.DNSSEC{*ksk}}},
+	})
+	tr.send(s, epp.EppOk, update, types.DNSSECExtensionUpdateType{
+		Update: types.DNSSECExtensionUpdate{Add: &types.DNSSECOrKeyData{KeyData: []types.DNSSECKeyData{dnssecKSK}}},
+	})
+
+	sec = info(s)
+	require.NotNil(t, sec)
+	assert.Equal(t, []types.DNSSECKeyData{dnssecKSK}, sec.KeyData)
+	assert.Empty(t, sec.DNSSECData)
+}
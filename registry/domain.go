@@ -0,0 +1,811 @@
+package registry
+
+import (
+	"regexp"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 도메인의 최대 등록 기간(월)입니다. RFC 5731 에 따라 10년을 넘을 수 없습니다.
+const maxPeriodMonths = 120
+
+var hostnameRegexp = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// 클라이언트가 추가하거나 제거할 수 있는 도메인 상태입니다.
+var clientDomainStatuses = map[types.DomainStatusType]struct{}{
+	types.DomainStatusClientDeleteProhibited:   {},
+	types.DomainStatusClientHold:               {},
+	types.DomainStatusClientRenewProhibited:    {},
+	types.DomainStatusClientTransferProhibited: {},
+	types.DomainStatusClientUpdateProhibited:   {},
+}
+
+// 다른 명령어를 처리할 수 없게 하는 진행 중 상태입니다.
+var pendingDomainStatuses = []types.DomainStatusType{
+	types.DomainStatusPendingCreate,
+	types.DomainStatusPendingDelete,
+	types.DomainStatusPendingRenew,
+	types.DomainStatusPendingTransfer,
+	types.DomainStatusPendingUpdate,
+}
+
+func (r *Registry) checkDomain(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainCheckType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	token, err := r.allocationToken(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := types.DomainChekDataType{}
+
+	for _, name := range cmd.Check.Names {
+		cd := types.CheckType{
+			Name: types.CheckName{
+				Value:     name,
+				Available: true,
+			},
+		}
+
+		if _, err := r.Repository.Domain(normalize(name)); err == nil {
+			cd.Name.Available = false
+			cd.Reason = "In use"
+		} else if errors.Cause(err) != ErrObjectNotFound {
+			return nil, err
+		}
+
+		// 토큰이 필요하거나 토큰이 맞지 않는 도메인은 사용할 수 없습니다.
+		if err := r.checkAllocationToken(&cd, token, s.ClientID); err != nil {
+			return nil, err
+		}
+
+		result.CheckData.CheckDomain = append(result.CheckData.CheckDomain, cd)
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(result), nil
+}
+
+// check 명령어에 함께 사용할 수 있는 확장입니다. 명령어에 없는 확장은 nil 입니다.
+type domainCheckExtensions struct {
+	Launch *types.LaunchCheck `xml:"urn:ietf:params:xml:ns:launch-1.0 command>extension>check"`
+	Fee    *types.FeeCheck    `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 command>extension>check"`
+}
+
+// launch-1.0 또는 fee-1.0 확장이 있는 check 명령어를 처리합니다. Mux 는 명령어의
+// 확장 중 처음 찾은 라우트 하나만 사용하므로, 두 확장의 라우트 모두 이 핸들러를
+// 사용하여 함께 전송된 확장을 모두 처리합니다.
+func (r *Registry) checkDomainExtension(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	ext := domainCheckExtensions{}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	if ext.Launch != nil {
+		return r.checkDomainLaunch(s, data, ext.Fee != nil)
+	}
+
+	return r.checkDomainFee(s, data)
+}
+
+func (r *Registry) infoDomain(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainInfoType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Info.Name.Name)
+
+	d, err := r.Repository.Domain(name)
+	if err != nil {
+		return nil, notFound(err, "domain %s does not exist", name)
+	}
+
+	isSponsor := d.ClientID == s.ClientID
+
+	if !isSponsor && cmd.Info.AuthInfo != nil && !r.validDomainAuthInfo(cmd.Info.AuthInfo, d) {
+		return nil, errorf(epp.EppInvalidAuthInfo, "invalid authorization information")
+	}
+
+	token, err := r.infoAllocationToken(s, data, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// 관리 클라이언트가 아니고 인증 정보도 없는 경우 제한된 정보만 반환합니다.
+	if !isSponsor && cmd.Info.AuthInfo == nil {
+		return epp.NewResponse(epp.EppOk).WithResData(types.DomainInfoDataType{
+			InfoData: types.DomainInfoData{
+				Name:     d.Name,
+				ROID:     d.ROID,
+				Status:   r.domainStatus(d),
+				ClientID: d.ClientID,
+			},
+		}), nil
+	}
+
+	info, err := r.domainInfoData(r.Repository, d, cmd.Info.Name.Hosts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 인증 정보는 관리 클라이언트에게만 반환합니다. 해시로 저장된 인증 정보는 설정
+	// 여부만 반환합니다.
+	if isSponsor {
+		info.AuthInfo = r.domainAuthInfo(d)
+	}
+
+	response := epp.NewResponse(epp.EppOk).WithResData(types.DomainInfoDataType{InfoData: *info})
+
+	// 삭제가 예정된 도메인은 iis-1.2 확장으로 삭제와 해제 날짜를 함께 반환합니다.
+	if iis := r.IISInfoData(d); iis.DeleteDate != nil {
+		response = response.WithExtension(types.IISExtensionInfoDataType{InfoData: iis})
+	}
+
+	// 유예 기간에 있는 도메인은 rgp-1.0 확장으로 rgpStatus 를 반환합니다.
+	if status := r.rgpStatus(d); len(status) > 0 {
+		response = response.WithExtension(types.RGPExtensionInfoDataType{
+			InfoData: types.RGPResponseData{Status: status},
+		})
+	}
+
+	if token != nil {
+		response = response.WithExtension(*token)
+	}
+
+	response = withDNSSEC(response, d)
+
+	return withOrganizations(response, d.Organizations), nil
+}
+
+// 도메인의 인증 정보를 제외한 모든 정보를 반환합니다. hosts 에 따라 네임서버와 종속된
+// 호스트를 반환하며, 비어있으면 모두 반환합니다.
+func (r *Registry) domainInfoData(tx Repository, d *Domain, hosts types.DomainHostsType) (*types.DomainInfoData, error) {
+	info := &types.DomainInfoData{
+		Name:     d.Name,
+		ROID:     d.ROID,
+		Status:   r.domainStatus(d),
+		ClientID: d.ClientID,
+	}
+
+	if hosts == "" {
+		hosts = types.DomainHostsAll
+	}
+
+	if (hosts == types.DomainHostsAll || hosts == types.DomainHostsDel) && len(d.Hosts) > 0 {
+		info.NameServer = &types.NameServer{
+			HostObject: d.Hosts,
+		}
+	}
+
+	if hosts == types.DomainHostsAll || hosts == types.DomainHostsSub {
+		subordinates, err := tx.HostsBySuperordinate(d.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		info.Host = subordinates
+	}
+
+	createDate, expireDate := d.CreateDate, d.ExpireDate
+
+	info.Registrant = d.Registrant
+	info.Contact = d.Contacts
+	info.CreateID = d.CreateID
+	info.CreateDate = &createDate
+	info.UpdateID = d.UpdateID
+	info.UpdateDate = d.UpdateDate
+	info.ExpireDate = &expireDate
+	info.TransferDate = d.TransferDate
+
+	return info, nil
+}
+
+func (r *Registry) createDomain(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainCreateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Create.Name)
+
+	// 런치 단계에서는 launch-1.0 확장 없이 도메인을 등록할 수 없습니다.
+	if err := r.checkOpenRegistration(name); err != nil {
+		return nil, err
+	}
+
+	token, err := r.allocationToken(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.verifyAllocationToken(token, name, s.ClientID); err != nil {
+		return nil, err
+	}
+
+	orgs, err := r.orgExtensionCreate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := r.dnssecExtensionCreate(data, name)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := r.chargeFee(data, name, types.FeeCommandCreate, cmd.Create.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.registerDomain(s, cmd.Create, token, orgs, sec)
+	if err != nil {
+		return nil, err
+	}
+
+	return withFee(response, types.FeeCommandCreate, fee), nil
+}
+
+// 도메인을 등록하고 creData 응답을 반환합니다. 할당 토큰이 주어지면 도메인을 등록하는
+// 트랜잭션 안에서 토큰을 사용하며, orgs 의 조직과 sec 의 DNSSEC 위임 정보를 도메인에
+// 등록합니다.
+func (r *Registry) registerDomain(s *epp.Session, create types.DomainCreate, token string, orgs []types.OrgExtensionID, sec *types.DNSSECOrKeyData) (*epp.ResponseBuilder, error) {
+	d, months, err := r.newDomain(s, create)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Organizations = orgs
+	d.DNSSEC = sec
+
+	r.addGracePeriod(d, GraceAdd, d.CreateDate, r.Lifecycle.AddGracePeriod, months)
+
+	err = r.Repository.Transaction(func(tx Repository) error {
+		if err := r.prepareDomain(tx, d); err != nil {
+			return err
+		}
+
+		if err := checkOrgLinks(tx, d.ClientID, d.Organizations); err != nil {
+			return err
+		}
+
+		if err := tx.CreateDomain(d); err != nil {
+			return err
+		}
+
+		return r.useAllocationToken(token, d.Name)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	expireDate := d.ExpireDate
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.DomainCreateDataType{
+		CreateData: types.DomainCreateData{
+			Name:       d.Name,
+			CreateDate: d.CreateDate,
+			ExpireDate: &expireDate,
+		},
+	}), nil
+}
+
+// create 명령어를 검사하고 저장되지 않은 새로운 도메인과 등록 기간(월)을 반환합니다.
+func (r *Registry) newDomain(s *epp.Session, create types.DomainCreate) (*Domain, int, error) {
+	name := normalize(create.Name)
+
+	if !hostnameRegexp.MatchString(name) {
+		return nil, 0, errorf(epp.EppParamSyntaxError, "invalid domain name %s", create.Name)
+	}
+
+	months, err := periodMonths(create.Period)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 안전한 인증 정보 처리에서는 빈 인증 정보로 도메인을 등록할 수 있습니다.
+	if create.AuthInfo == nil || (create.AuthInfo.Password == "" && r.SecureAuthInfo == nil) {
+		return nil, 0, errorf(epp.EppMissingParam, "authorization information is required")
+	}
+
+	now := r.now()
+	d := &Domain{
+		Name:       name,
+		Registrant: create.Registrant,
+		Contacts:   create.Contacts,
+		ClientID:   s.ClientID,
+		CreateID:   s.ClientID,
+		CreateDate: now,
+		ExpireDate: now.AddDate(0, months, 0),
+	}
+
+	if err := r.setDomainAuthInfo(d, create.AuthInfo); err != nil {
+		return nil, 0, err
+	}
+
+	if ns := create.NameServer; ns != nil {
+		if len(ns.HostAttribute) > 0 {
+			return nil, 0, errorf(epp.EppUnimplementedOption, "host attributes are not supported, use host objects")
+		}
+
+		for _, h := range ns.HostObject {
+			d.Hosts = append(d.Hosts, normalize(h))
+		}
+	}
+
+	return d, months, nil
+}
+
+// 도메인이 이미 존재하지 않고 참조하는 개체가 모두 존재하는지 확인한 후 ROID 를
+// 할당합니다.
+func (r *Registry) prepareDomain(tx Repository, d *Domain) error {
+	if _, err := tx.Domain(d.Name); err == nil {
+		return errorf(epp.EppObjectExists, "domain %s already exists", d.Name)
+	} else if errors.Cause(err) != ErrObjectNotFound {
+		return err
+	}
+
+	if err := checkDomainReferences(tx, d); err != nil {
+		return err
+	}
+
+	roid, err := r.roid(tx, "D")
+	if err != nil {
+		return err
+	}
+
+	d.ROID = roid
+
+	return nil
+}
+
+func (r *Registry) updateDomain(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainUpdateType{}
+	rgp := types.RGPExtensionUpdateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if err := epp.Decode(data, &rgp); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Update.Name)
+
+	// 복구 명령어는 빈 chg 를 가진 update 명령어로 전송됩니다. (RFC 3915 4.2.5)
+	// 복구 요청에는 restore 요금이 부과됩니다.
+	if rgp.Update.Restore.Operation == types.RGPRestoreRequest {
+		fee, err := r.chargeFee(data, name, types.FeeCommandRestore, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := r.restoreDomain(s, name, rgp.Update.Restore)
+		if err != nil {
+			return nil, err
+		}
+
+		return withFee(response, types.FeeCommandRestore, fee), nil
+	}
+
+	if rgp.Update.Restore.Operation != "" {
+		return r.restoreDomain(s, name, rgp.Update.Restore)
+	}
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		d, err := r.sponsoredDomain(tx, s, name)
+		if err != nil {
+			return err
+		}
+
+		removesUpdateProhibited := false
+
+		if cmd.Update.Remove != nil {
+			for _, status := range cmd.Update.Remove.Status {
+				if status.DomainStatusType == types.DomainStatusClientUpdateProhibited {
+					removesUpdateProhibited = true
+				}
+			}
+		}
+
+		if d.hasStatus(types.DomainStatusServerUpdateProhibited) ||
+			(d.hasStatus(types.DomainStatusClientUpdateProhibited) && !removesUpdateProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "domain %s has status update prohibited", name)
+		}
+
+		if d.hasStatus(pendingDomainStatuses...) {
+			return errorf(epp.EppStatusProhibitsOp, "domain %s has a pending status", name)
+		}
+
+		if err := r.changeDomain(tx, d, cmd.Update); err != nil {
+			return err
+		}
+
+		orgs, err := r.orgExtensionUpdate(data, d.Organizations)
+		if err != nil {
+			return err
+		}
+
+		if err := checkOrgLinks(tx, d.ClientID, newOrgLinks(d.Organizations, orgs)); err != nil {
+			return err
+		}
+
+		d.Organizations = orgs
+
+		if err := r.dnssecExtensionUpdate(data, d); err != nil {
+			return err
+		}
+
+		now := r.now()
+		d.UpdateID = s.ClientID
+		d.UpdateDate = &now
+
+		return tx.UpdateDomain(d)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func (r *Registry) deleteDomain(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainDeleteType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Delete.Name)
+
+	pending := false
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		d, err := r.sponsoredDomain(tx, s, name)
+		if err != nil {
+			return err
+		}
+
+		if d.hasStatus(types.DomainStatusClientDeleteProhibited, types.DomainStatusServerDeleteProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "domain %s has status delete prohibited", name)
+		}
+
+		if d.hasStatus(pendingDomainStatuses...) {
+			return errorf(epp.EppStatusProhibitsOp, "domain %s has a pending status", name)
+		}
+
+		// 종속된 호스트가 있는 도메인은 호스트를 먼저 삭제해야 합니다.
+		subordinates, err := tx.HostsBySuperordinate(name)
+		if err != nil {
+			return err
+		}
+
+		if len(subordinates) > 0 {
+			return errorf(epp.EppAssocProhibitsOp, "domain %s has subordinate hosts", name)
+		}
+
+		// 유예 기간이 지난 도메인은 바로 삭제되지 않고 복구 기간에 들어갑니다.
+		pending, err = r.removeDomain(tx, d)
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if pending {
+		return epp.NewResponse(epp.EppOkPending), nil
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func (r *Registry) renewDomain(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainRenewType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Renew.Name)
+
+	months, err := periodMonths(cmd.Renew.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := r.chargeFee(data, name, types.FeeCommandRenew, cmd.Renew.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	var d *Domain
+
+	err = r.Repository.Transaction(func(tx Repository) error {
+		d, err = r.sponsoredDomain(tx, s, name)
+		if err != nil {
+			return err
+		}
+
+		if d.hasStatus(types.DomainStatusClientRenewProhibited, types.DomainStatusServerRenewProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "domain %s has status renew prohibited", name)
+		}
+
+		if d.hasStatus(pendingDomainStatuses...) {
+			return errorf(epp.EppStatusProhibitsOp, "domain %s has a pending status", name)
+		}
+
+		// 같은 갱신 명령어가 여러 번 처리되지 않도록 현재 만료일을 확인합니다.
+		if cmd.Renew.ExpireDate.Format("2006-01-02") != d.ExpireDate.Format("2006-01-02") {
+			return errorf(epp.EppParamPolicyError, "current expiry date does not match %s", d.ExpireDate.Format("2006-01-02"))
+		}
+
+		expireDate := d.ExpireDate.AddDate(0, months, 0)
+
+		if expireDate.After(r.now().AddDate(0, maxPeriodMonths, 0)) {
+			return errorf(epp.EppParamPolicyError, "domain can not be registered for more than %d years", maxPeriodMonths/12)
+		}
+
+		d.ExpireDate = expireDate
+		r.addGracePeriod(d, GraceRenew, r.now(), r.Lifecycle.RenewGracePeriod, months)
+
+		return tx.UpdateDomain(d)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := epp.NewResponse(epp.EppOk).WithResData(types.DomainRenewDataType{
+		RenewData: types.DomainRenewData{
+			Name:       d.Name,
+			ExpireDate: d.ExpireDate,
+		},
+	})
+
+	return withFee(response, types.FeeCommandRenew, fee), nil
+}
+
+// 도메인을 관리하는 클라이언트로 로그인 되어 있는 경우에만 도메인을 반환합니다.
+func (r *Registry) sponsoredDomain(tx Repository, s *epp.Session, name string) (*Domain, error) {
+	d, err := tx.Domain(name)
+	if err != nil {
+		return nil, notFound(err, "domain %s does not exist", name)
+	}
+
+	if d.ClientID != s.ClientID {
+		return nil, errorf(epp.EppAuthorisationError, "domain %s is not sponsored by %s", name, s.ClientID)
+	}
+
+	return d, nil
+}
+
+// 도메인의 상태를 반환합니다. 네임서버가 없는 도메인은 inactive 상태를 가지며,
+// 다른 상태가 없는 경우 ok 상태를 가집니다.
+func (r *Registry) domainStatus(d *Domain) []types.DomainStatus {
+	status := []types.DomainStatus{}
+
+	for _, s := range d.Status {
+		status = append(status, types.DomainStatus{DomainStatusType: s})
+	}
+
+	if len(d.Hosts) == 0 {
+		status = append(status, types.DomainStatus{DomainStatusType: types.DomainStatusInactive})
+	}
+
+	if len(status) == 0 {
+		status = append(status, types.DomainStatus{DomainStatusType: types.DomainStatusOk})
+	}
+
+	return status
+}
+
+// 도메인이 참조하는 호스트와 연락처가 모두 존재하는지 확인합니다.
+func checkDomainReferences(tx Repository, d *Domain) error {
+	for _, h := range d.Hosts {
+		if _, err := tx.Host(h); err != nil {
+			return notFound(err, "host %s does not exist", h)
+		}
+	}
+
+	contacts := []string{}
+
+	if d.Registrant != "" {
+		contacts = append(contacts, d.Registrant)
+	}
+
+	for _, c := range d.Contacts {
+		contacts = append(contacts, c.Name)
+	}
+
+	for _, id := range contacts {
+		if _, err := tx.Contact(id); err != nil {
+			return notFound(err, "contact %s does not exist", id)
+		}
+	}
+
+	return nil
+}
+
+// update 명령어의 변경 내용을 도메인에 적용하고 참조하는 개체가 모두 존재하는지
+// 확인합니다.
+func (r *Registry) changeDomain(tx Repository, d *Domain, update types.DomainUpdate) error {
+	// RFC 5731 에 따라 제거를 먼저 처리하고 추가를 처리합니다.
+	if rem := update.Remove; rem != nil {
+		if err := removeFromDomain(d, rem); err != nil {
+			return err
+		}
+	}
+
+	if add := update.Add; add != nil {
+		if err := addToDomain(d, add); err != nil {
+			return err
+		}
+	}
+
+	if chg := update.Change; chg != nil {
+		if chg.Registrant != "" {
+			d.Registrant = chg.Registrant
+		}
+
+		if chg.AuthInfo != nil {
+			if err := r.setDomainAuthInfo(d, chg.AuthInfo); err != nil {
+				return err
+			}
+		}
+	}
+
+	return checkDomainReferences(tx, d)
+}
+
+func removeFromDomain(d *Domain, rem *types.DomainAddRemove) error {
+	if rem.NameServer != nil {
+		if len(rem.NameServer.HostAttribute) > 0 {
+			return errorf(epp.EppUnimplementedOption, "host attributes are not supported, use host objects")
+		}
+
+		for _, h := range rem.NameServer.HostObject {
+			h = normalize(h)
+			i := indexOf(d.Hosts, h)
+
+			if i < 0 {
+				return errorf(epp.EppParamPolicyError, "host %s is not associated with domain %s", h, d.Name)
+			}
+
+			d.Hosts = append(d.Hosts[:i], d.Hosts[i+1:]...)
+		}
+	}
+
+	for _, c := range rem.Contact {
+		i := indexOfContact(d.Contacts, c)
+
+		if i < 0 {
+			return errorf(epp.EppParamPolicyError, "%s contact %s is not associated with domain %s", c.Type, c.Name, d.Name)
+		}
+
+		d.Contacts = append(d.Contacts[:i], d.Contacts[i+1:]...)
+	}
+
+	for _, status := range rem.Status {
+		if _, ok := clientDomainStatuses[status.DomainStatusType]; !ok {
+			return errorf(epp.EppParamPolicyError, "status %s can not be removed by the client", status.DomainStatusType)
+		}
+
+		found := false
+
+		for i, s := range d.Status {
+			if s == status.DomainStatusType {
+				d.Status = append(d.Status[:i], d.Status[i+1:]...)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return errorf(epp.EppParamPolicyError, "domain %s does not have status %s", d.Name, status.DomainStatusType)
+		}
+	}
+
+	return nil
+}
+
+func addToDomain(d *Domain, add *types.DomainAddRemove) error {
+	if add.NameServer != nil {
+		if len(add.NameServer.HostAttribute) > 0 {
+			return errorf(epp.EppUnimplementedOption, "host attributes are not supported, use host objects")
+		}
+
+		for _, h := range add.NameServer.HostObject {
+			h = normalize(h)
+
+			if indexOf(d.Hosts, h) >= 0 {
+				return errorf(epp.EppParamPolicyError, "host %s is already associated with domain %s", h, d.Name)
+			}
+
+			d.Hosts = append(d.Hosts, h)
+		}
+	}
+
+	for _, c := range add.Contact {
+		if indexOfContact(d.Contacts, c) >= 0 {
+			return errorf(epp.EppParamPolicyError, "%s contact %s is already associated with domain %s", c.Type, c.Name, d.Name)
+		}
+
+		d.Contacts = append(d.Contacts, c)
+	}
+
+	for _, status := range add.Status {
+		if _, ok := clientDomainStatuses[status.DomainStatusType]; !ok {
+			return errorf(epp.EppParamPolicyError, "status %s can not be added by the client", status.DomainStatusType)
+		}
+
+		if d.hasStatus(status.DomainStatusType) {
+			return errorf(epp.EppParamPolicyError, "domain %s already has status %s", d.Name, status.DomainStatusType)
+		}
+
+		d.Status = append(d.Status, status.DomainStatusType)
+	}
+
+	return nil
+}
+
+// 등록 기간을 월 단위로 반환합니다. 기간이 없으면 기본값으로 1년을 사용합니다.
+func periodMonths(period *types.Period) (int, error) {
+	if period == nil {
+		return 12, nil
+	}
+
+	months := period.Value
+
+	switch period.Unit {
+	case "y":
+		months *= 12
+	case "m":
+	default:
+		return 0, errorf(epp.EppParamSyntaxError, "invalid period unit %s", period.Unit)
+	}
+
+	if months < 1 || months > maxPeriodMonths {
+		return 0, errorf(epp.EppParamRangeError, "period must be between 1 month and %d years", maxPeriodMonths/12)
+	}
+
+	return months, nil
+}
+
+// 인증 정보가 주어졌고 저장된 인증 정보와 같은지 확인합니다.
+func validAuthInfo(authInfo *types.AuthInfo, password string) bool {
+	return authInfo != nil && authInfo.Password != "" && authInfo.Password == password
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func indexOfContact(contacts []types.Contact, contact types.Contact) int {
+	for i, c := range contacts {
+		if c.Name == contact.Name && c.Type == contact.Type {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,335 @@
+package registry
+
+import (
+	"fmt"
+	"math/big"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 요금 등급입니다. standard 가 아닌 등급의 요금은 클라이언트가 확인해야 명령어를
+// 처리할 수 있습니다.
+const (
+	FeeClassStandard = "standard"
+	FeeClassPremium  = "premium"
+)
+
+// 도메인 명령어의 요금을 정하는 엔진입니다. (RFC 8748) Registry.Pricing 이 nil 이면
+// fee-1.0 확장을 지원하지 않고 요금을 확인하지 않습니다.
+type PricingEngine interface {
+	// 요금에 사용되는 ISO 4217 통화 코드입니다.
+	Currency() string
+
+	// 도메인에 명령어를 처리하는 요금을 반환합니다. 기간(월)은 create, renew, transfer
+	// 명령어에만 주어지고 다른 명령어는 0 입니다. 요금을 정할 수 없는 도메인은 오류를
+	// 반환하며, 오류는 클라이언트에게 사유로 전달됩니다.
+	Price(name string, command types.FeeCommandName, months int) (*Price, error)
+}
+
+// 하나의 명령어에 대한 요금입니다.
+type Price struct {
+	// 요금 등급입니다. 빈 문자열은 standard 로 처리합니다.
+	Class string
+	Fees  []types.FeeFee
+}
+
+// 요금이 standard 등급인지 확인합니다.
+func (p *Price) standard() bool {
+	return p.Class == "" || p.Class == FeeClassStandard
+}
+
+// 모든 요금의 합을 반환합니다.
+func (p *Price) total() (*big.Rat, error) {
+	return feeTotal(p.Fees)
+}
+
+// 등급과 명령어별 1년 요금으로 요금을 정하는 PricingEngine 입니다. 요금은 기간에
+// 비례하며, 기간이 없는 명령어는 정해진 요금을 그대로 사용합니다.
+//
+//	r.Pricing = &registry.TieredPricing{
+//	    CurrencyCode: "SEK",
+//	    Prices: map[string]map[types.FeeCommandName]string{
+//	        registry.FeeClassStandard: {types.FeeCommandCreate: "100.00"},
+//	        registry.FeeClassPremium:  {types.FeeCommandCreate: "1000.00"},
+//	    },
+//	}
+type TieredPricing struct {
+	CurrencyCode string
+
+	// 등급과 명령어별 요금입니다. 요금이 없는 명령어는 무료입니다.
+	Prices map[string]map[types.FeeCommandName]string
+
+	// 도메인의 등급을 반환합니다. nil 이면 모든 도메인은 standard 등급입니다.
+	Class func(name string) string
+}
+
+// 요금에 사용되는 통화 코드를 반환합니다.
+func (t *TieredPricing) Currency() string {
+	return t.CurrencyCode
+}
+
+// 도메인의 등급에 따라 명령어의 요금을 반환합니다.
+func (t *TieredPricing) Price(name string, command types.FeeCommandName, months int) (*Price, error) {
+	class := FeeClassStandard
+	if t.Class != nil {
+		class = t.Class(name)
+	}
+
+	prices, ok := t.Prices[class]
+	if !ok {
+		return nil, errors.Errorf("no prices for class %s", class)
+	}
+
+	price := &Price{Class: class}
+
+	yearly, ok := prices[command]
+	if !ok {
+		return price, nil
+	}
+
+	value, ok := new(big.Rat).SetString(yearly)
+	if !ok {
+		return nil, errors.Errorf("invalid %s price %s", command, yearly)
+	}
+
+	if months > 0 {
+		value.Mul(value, big.NewRat(int64(months), 12))
+	}
+
+	price.Fees = []types.FeeFee{{
+		Value:       value.FloatString(2),
+		Description: fmt.Sprintf("%s fee", command),
+	}}
+
+	return price, nil
+}
+
+// fee-1.0 확장으로 받은 check 명령어를 처리합니다. 도메인 check 응답에 요청된
+// 명령어별 요금을 chkData 확장으로 추가합니다.
+func (r *Registry) checkDomainFee(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainCheckType{}
+	ext := types.FeeExtensionCheckType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	if r.Pricing == nil {
+		return nil, errorf(epp.EppUnimplementedExtension, "the fee extension is not supported")
+	}
+
+	currency := r.Pricing.Currency()
+
+	if ext.Check.Currency != "" && ext.Check.Currency != currency {
+		return nil, errorf(epp.EppParamRangeError, "currency %s is not supported", ext.Check.Currency)
+	}
+
+	response, err := r.checkDomain(s, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := types.FeeCheckData{Currency: currency}
+
+	for _, name := range cmd.Check.Names {
+		cd, err := r.feeCheckData(normalize(name), ext.Check.Command)
+		if err != nil {
+			return nil, err
+		}
+
+		result.CheckData = append(result.CheckData, cd)
+	}
+
+	return response.WithExtension(types.FeeExtensionCheckDataType{CheckData: result}), nil
+}
+
+// 하나의 도메인에 대한 명령어별 요금을 반환합니다. 요금을 정할 수 없는 도메인은
+// 사유와 함께 avail 이 0 으로 반환됩니다.
+func (r *Registry) feeCheckData(name string, commands []types.FeeCommand) (types.FeeObjectCheckData, error) {
+	cd := types.FeeObjectCheckData{
+		Available: true,
+		ObjectID:  name,
+	}
+
+	for _, c := range commands {
+		if c.Name == types.FeeCommandCustom {
+			return cd, errorf(epp.EppUnimplementedOption, "custom commands are not supported")
+		}
+
+		period, months, err := feePeriod(c.Name, c.Period)
+		if err != nil {
+			return cd, err
+		}
+
+		price, err := r.Pricing.Price(name, c.Name, months)
+		if err != nil {
+			return types.FeeObjectCheckData{
+				ObjectID: name,
+				Reason:   &types.FeeReason{Value: err.Error()},
+			}, nil
+		}
+
+		if cd.Class == "" {
+			cd.Class = price.Class
+		}
+
+		cd.Command = append(cd.Command, types.FeeCommandData{
+			Name:     c.Name,
+			Phase:    c.Phase,
+			Subphase: c.Subphase,
+			Standard: price.standard(),
+			Period:   period,
+			Fee:      price.Fees,
+		})
+	}
+
+	return cd, nil
+}
+
+// 클라이언트가 fee-1.0 확장으로 확인한 요금을 검사하고 응답에 포함할 요금을
+// 반환합니다. 요금 엔진이 없거나 클라이언트가 확장 없이 standard 요금의 명령어를
+// 보낸 경우 nil 을 반환합니다.
+//
+// standard 가 아닌 요금을 확인하지 않으면 2104 로, 확인한 요금이 부족하거나 통화가
+// 다르면 2004 로 응답합니다.
+func (r *Registry) chargeFee(data []byte, name string, command types.FeeCommandName, period *types.Period) (*types.FeeTransformResult, error) {
+	ext := struct {
+		types.FeeExtensionCreateType
+		types.FeeExtensionRenewType
+		types.FeeExtensionTransferType
+		types.FeeExtensionUpdateType
+	}{}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	// 복구 요청은 update 명령어로 전송되므로 update 요소로 요금을 확인합니다.
+	acknowledged := ext.Update
+
+	switch command {
+	case types.FeeCommandCreate:
+		acknowledged = ext.Create
+	case types.FeeCommandRenew:
+		acknowledged = ext.Renew
+	case types.FeeCommandTransfer:
+		acknowledged = ext.Transfer
+	}
+
+	if r.Pricing == nil {
+		if len(acknowledged.Fee) > 0 {
+			return nil, errorf(epp.EppUnimplementedExtension, "the fee extension is not supported")
+		}
+
+		return nil, nil
+	}
+
+	resultPeriod, months, err := feePeriod(command, period)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := r.Pricing.Price(name, command, months)
+	if err != nil {
+		if _, ok := errors.Cause(err).(*Error); !ok {
+			err = errorf(epp.EppParamPolicyError, "no %s fee for domain %s: %s", command, name, err.Error())
+		}
+
+		return nil, err
+	}
+
+	if len(acknowledged.Fee) == 0 {
+		if !price.standard() {
+			return nil, errorf(epp.EppBillingFailure, "the %s fee for domain %s must be acknowledged", price.Class, name)
+		}
+
+		return nil, nil
+	}
+
+	currency := r.Pricing.Currency()
+
+	if acknowledged.Currency != "" && acknowledged.Currency != currency {
+		return nil, errorf(epp.EppParamRangeError, "currency %s is not supported", acknowledged.Currency)
+	}
+
+	want, err := price.total()
+	if err != nil {
+		return nil, err
+	}
+
+	got, err := feeTotal(acknowledged.Fee)
+	if err != nil {
+		return nil, errorf(epp.EppParamSyntaxError, "invalid fee: %s", err.Error())
+	}
+
+	if got.Cmp(want) < 0 {
+		return nil, errorf(epp.EppParamRangeError, "the %s fee for domain %s is %s %s", command, name, want.FloatString(2), currency)
+	}
+
+	return &types.FeeTransformResult{
+		Currency: currency,
+		Period:   resultPeriod,
+		Fee:      price.Fees,
+	}, nil
+}
+
+// 요금이 있으면 명령어에 맞는 fee-1.0 확장을 응답에 추가합니다.
+func withFee(response *epp.ResponseBuilder, command types.FeeCommandName, fee *types.FeeTransformResult) *epp.ResponseBuilder {
+	if fee == nil {
+		return response
+	}
+
+	switch command {
+	case types.FeeCommandCreate:
+		return response.WithExtension(types.FeeExtensionCreateDataType{CreateData: *fee})
+	case types.FeeCommandRenew:
+		return response.WithExtension(types.FeeExtensionRenewDataType{RenewData: *fee})
+	case types.FeeCommandTransfer:
+		return response.WithExtension(types.FeeExtensionTransferDataType{TransferData: *fee})
+	}
+
+	return response.WithExtension(types.FeeExtensionUpdateDataType{UpdateData: *fee})
+}
+
+// 명령어의 기간을 반환합니다. 기간은 create, renew, transfer 명령어에만 사용되며
+// 주어지지 않으면 1년입니다.
+func feePeriod(command types.FeeCommandName, period *types.Period) (*types.Period, int, error) {
+	switch command {
+	case types.FeeCommandCreate, types.FeeCommandRenew, types.FeeCommandTransfer:
+	default:
+		return nil, 0, nil
+	}
+
+	months, err := periodMonths(period)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if period == nil {
+		period = &types.Period{Value: 1, Unit: "y"}
+	}
+
+	return period, months, nil
+}
+
+// 요금의 합을 반환합니다.
+func feeTotal(fees []types.FeeFee) (*big.Rat, error) {
+	total := new(big.Rat)
+
+	for _, f := range fees {
+		value, ok := new(big.Rat).SetString(f.Value)
+		if !ok {
+			return nil, errors.Errorf("invalid amount %s", f.Value)
+		}
+
+		total.Add(total, value)
+	}
+
+	return total, nil
+}
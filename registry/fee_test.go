@@ -0,0 +1,170 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func feeCommand(currency, value string) types.FeeTransformCommand {
+	return types.FeeTransformCommand{
+		Currency: currency,
+		Fee:      []types.FeeFee{{Value: value}},
+	}
+}
+
+// 응답의 extension 에서 요금을 반환합니다.
+func feeResult(t *testing.T, response []byte) types.FeeTransformResult {
+	ext := struct {
+		types.FeeExtensionCreateDataType
+		types.FeeExtensionRenewDataType
+		types.FeeExtensionTransferDataType
+		types.FeeExtensionUpdateDataType
+	}{}
+
+	require.Nil(t, epp.Decode(response, &types.Response{Extension: &ext}))
+
+	for _, result := range []types.FeeTransformResult{ext.CreateData, ext.RenewData, ext.TransferData, ext.UpdateData} {
+		if len(result.Fee) > 0 {
+			return result
+		}
+	}
+
+	return types.FeeTransformResult{}
+}
+
+func testRegistryFee(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	check := types.DomainCheckType{Check: types.DomainCheck{Names: []string{"example.se", "premium.se"}}}
+	checkFee := types.FeeExtensionCheckType{
+		Check: types.FeeCheck{
+			Command: []types.FeeCommand{
+				{Name: types.FeeCommandCreate, Period: &types.Period{Value: 2, Unit: "y"}},
+				{Name: types.FeeCommandRestore},
+			},
+		},
+	}
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+
+	// Without a pricing engine the extension is not supported.
+	tr.send(s, epp.EppUnimplementedExtension, check, checkFee)
+	tr.send(s, epp.EppUnimplementedExtension, domainCreate("premium.se"), types.FeeExtensionCreateType{
+		Create: feeCommand("", "2000.00"),
+	})
+
+	tr.registry.Pricing = &TieredPricing{
+		CurrencyCode: "SEK",
+		Prices: map[string]map[types.FeeCommandName]string{
+			FeeClassStandard: {
+				types.FeeCommandCreate:  "100.00",
+				types.FeeCommandRenew:   "100.00",
+				types.FeeCommandRestore: "400.00",
+			},
+			FeeClassPremium: {
+				types.FeeCommandCreate:   "1000.00",
+				types.FeeCommandRenew:    "1000.00",
+				types.FeeCommandTransfer: "1000.00",
+			},
+		},
+		Class: func(name string) string {
+			if name == "premium.se" {
+				return FeeClassPremium
+			}
+
+			return FeeClassStandard
+		},
+	}
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceFee10)
+
+	// Check returns the fees for each command with per period pricing.
+	ext := types.FeeExtensionCheckDataType{}
+	require.Nil(t, epp.Decode(tr.send(s, epp.EppOk, check, checkFee), &types.Response{Extension: &ext}))
+
+	fees := ext.CheckData
+	assert.Equal(t, "SEK", fees.Currency)
+	require.Len(t, fees.CheckData, 2)
+
+	standard, premium := fees.CheckData[0], fees.CheckData[1]
+	assert.Equal(t, FeeClassStandard, standard.Class)
+	require.Len(t, standard.Command, 2)
+	assert.True(t, standard.Command[0].Standard)
+	assert.Equal(t, "200.00", standard.Command[0].Fee[0].Value)
+	assert.Equal(t, "400.00", standard.Command[1].Fee[0].Value)
+	assert.Nil(t, standard.Command[1].Period)
+
+	assert.Equal(t, FeeClassPremium, premium.Class)
+	assert.False(t, premium.Command[0].Standard)
+	assert.Equal(t, "2000.00", premium.Command[0].Fee[0].Value)
+
+	checkFee.Check.Currency = "EUR"
+	tr.send(s, epp.EppParamRangeError, check, checkFee)
+
+	// Premium names require the client to acknowledge the fee.
+	tr.send(s, epp.EppBillingFailure, domainCreate("premium.se"))
+	tr.send(s, epp.EppParamRangeError, domainCreate("premium.se"), types.FeeExtensionCreateType{
+		Create: feeCommand("SEK", "1000.00"),
+	})
+	tr.send(s, epp.EppParamRangeError, domainCreate("premium.se"), types.FeeExtensionCreateType{
+		Create: feeCommand("EUR", "2000.00"),
+	})
+
+	response := tr.send(s, epp.EppOk, domainCreate("premium.se"), types.FeeExtensionCreateType{
+		Create: feeCommand("SEK", "2000.00"),
+	})
+
+	created := feeResult(t, response)
+	assert.Equal(t, "SEK", created.Currency)
+	assert.Equal(t, "2000.00", created.Fee[0].Value)
+	assert.Equal(t, 2, created.Period.Value)
+
+	// Standard fees do not need to be acknowledged.
+	response = tr.send(s, epp.EppOk, domainCreate("example.se"))
+	assert.Empty(t, feeResult(t, response).Fee)
+
+	renew := types.DomainRenewType{
+		Renew: types.DomainRenew{
+			Name:       "premium.se",
+			ExpireDate: types.Date{Time: tr.now.AddDate(2, 0, 0)},
+		},
+	}
+
+	tr.send(s, epp.EppBillingFailure, renew)
+
+	response = tr.send(s, epp.EppOk, renew, types.FeeExtensionRenewType{Renew: feeCommand("", "1000.00")})
+	assert.Equal(t, "1000.00", feeResult(t, response).Fee[0].Value)
+
+	transfer := domainTransfer("premium.se", types.TransferOperationRequest, "2fooBAR")
+	tr.send(other, epp.EppBillingFailure, transfer)
+
+	response = tr.send(other, epp.EppOkPending, transfer, types.FeeExtensionTransferType{
+		Transfer: feeCommand("SEK", "1000.00"),
+	})
+	assert.Equal(t, "1000.00", feeResult(t, response).Fee[0].Value)
+
+	// Restore requests are charged the restore fee.
+	tr.now = tr.now.Add(6 * 24 * time.Hour)
+	tr.send(s, epp.EppOkPending, domainDelete("example.se"))
+
+	update := types.DomainUpdateType{
+		Update: types.DomainUpdate{Name: "example.se", Change: &types.DomainChange{}},
+	}
+
+	tr.send(s, epp.EppParamRangeError, update, restore(types.RGPRestoreRequest, nil), types.FeeExtensionUpdateType{
+		Update: feeCommand("SEK", "300.00"),
+	})
+
+	response = tr.send(s, epp.EppOk, update, restore(types.RGPRestoreRequest, nil), types.FeeExtensionUpdateType{
+		Update: feeCommand("SEK", "400.00"),
+	})
+	assert.Equal(t, "400.00", feeResult(t, response).Fee[0].Value)
+}
@@ -0,0 +1,495 @@
+package registry
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 클라이언트가 추가하거나 제거할 수 있는 호스트 상태입니다.
+var clientHostStatuses = map[types.HostStatusType]struct{}{
+	types.HostStatusClientDeleteProhibited: {},
+	types.HostStatusClientUpdateProhibited: {},
+}
+
+func (r *Registry) checkHost(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.HostCheckType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	result := types.HostCheckDataType{}
+
+	for _, name := range cmd.Check.Names {
+		cd := types.CheckType{
+			Name: types.CheckName{
+				Value:     name,
+				Available: true,
+			},
+		}
+
+		if _, err := r.Repository.Host(normalize(name)); err == nil {
+			cd.Name.Available = false
+			cd.Reason = "In use"
+		} else if errors.Cause(err) != ErrObjectNotFound {
+			return nil, err
+		}
+
+		result.CheckData.Name = append(result.CheckData.Name, cd)
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(result), nil
+}
+
+func (r *Registry) infoHost(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.HostInfoType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Info.Name)
+
+	h, err := r.Repository.Host(name)
+	if err != nil {
+		return nil, notFound(err, "host %s does not exist", name)
+	}
+
+	status, err := r.hostStatus(r.Repository, h)
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.HostInfoDataType{
+		InfoData: types.HostInfoData{
+			Name:         h.Name,
+			ROID:         h.ROID,
+			Status:       status,
+			Address:      h.Addresses,
+			ClientID:     h.ClientID,
+			CreateID:     h.CreateID,
+			CreateDate:   h.CreateDate,
+			UpdateID:     h.UpdateID,
+			UpdateDate:   h.UpdateDate,
+			TransferDate: h.TransferDate,
+		},
+	}), nil
+}
+
+func (r *Registry) createHost(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.HostCreateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Create.Name)
+
+	if !hostnameRegexp.MatchString(name) {
+		return nil, errorf(epp.EppParamSyntaxError, "invalid host name %s", cmd.Create.Name)
+	}
+
+	addresses, err := normalizeAddresses(cmd.Create.Address)
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	h := &Host{
+		Name:       name,
+		Addresses:  addresses,
+		ClientID:   s.ClientID,
+		CreateID:   s.ClientID,
+		CreateDate: now,
+	}
+
+	err = r.Repository.Transaction(func(tx Repository) error {
+		if _, err := tx.Host(name); err == nil {
+			return errorf(epp.EppObjectExists, "host %s already exists", name)
+		} else if errors.Cause(err) != ErrObjectNotFound {
+			return err
+		}
+
+		if err := r.setSuperordinate(tx, s, h); err != nil {
+			return err
+		}
+
+		roid, err := r.roid(tx, "H")
+		if err != nil {
+			return err
+		}
+
+		h.ROID = roid
+
+		return tx.CreateHost(h)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.HostCreateDataType{
+		CreateData: types.HostCreateData{
+			Name:       h.Name,
+			CreateDate: h.CreateDate,
+		},
+	}), nil
+}
+
+func (r *Registry) updateHost(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.HostUpdateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Update.Name)
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		h, err := r.sponsoredHost(tx, s, name)
+		if err != nil {
+			return err
+		}
+
+		removesUpdateProhibited := false
+
+		if cmd.Update.Remove != nil {
+			for _, status := range cmd.Update.Remove.Status {
+				if status.HostStatusType == types.HostStatusClientUpdateProhibited {
+					removesUpdateProhibited = true
+				}
+			}
+		}
+
+		if h.hasStatus(types.HostStatusServerUpdateProhibited) ||
+			(h.hasStatus(types.HostStatusClientUpdateProhibited) && !removesUpdateProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "host %s has status update prohibited", name)
+		}
+
+		if rem := cmd.Update.Remove; rem != nil {
+			if err := removeFromHost(h, rem); err != nil {
+				return err
+			}
+		}
+
+		if add := cmd.Update.Add; add != nil {
+			if err := addToHost(h, add); err != nil {
+				return err
+			}
+		}
+
+		if chg := cmd.Update.Change; chg != nil && normalize(chg.Name) != name {
+			if err := r.renameHost(tx, s, h, normalize(chg.Name)); err != nil {
+				return err
+			}
+		} else if h.Superordinate == "" && len(h.Addresses) > 0 {
+			return errorf(epp.EppParamPolicyError, "external host %s can not have addresses", name)
+		} else if h.Superordinate != "" && len(h.Addresses) == 0 {
+			return errorf(epp.EppParamPolicyError, "host %s requires at least one address", name)
+		}
+
+		now := r.now()
+		h.UpdateID = s.ClientID
+		h.UpdateDate = &now
+
+		return tx.UpdateHost(name, h)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func (r *Registry) deleteHost(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.HostDeleteType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Delete.Name)
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		h, err := r.sponsoredHost(tx, s, name)
+		if err != nil {
+			return err
+		}
+
+		if h.hasStatus(types.HostStatusClientDeleteProhibited, types.HostStatusServerDeleteProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "host %s has status delete prohibited", name)
+		}
+
+		domains, err := tx.DomainsByHost(name)
+		if err != nil {
+			return err
+		}
+
+		if len(domains) > 0 {
+			return errorf(epp.EppAssocProhibitsOp, "host %s is linked to domain %s", name, strings.Join(domains, ", "))
+		}
+
+		return tx.DeleteHost(name)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+// 호스트의 이름을 변경합니다. 호스트를 사용하는 도메인의 네임서버도 같이 변경됩니다.
+func (r *Registry) renameHost(tx Repository, s *epp.Session, h *Host, newName string) error {
+	if !hostnameRegexp.MatchString(newName) {
+		return errorf(epp.EppParamSyntaxError, "invalid host name %s", newName)
+	}
+
+	if _, err := tx.Host(newName); err == nil {
+		return errorf(epp.EppObjectExists, "host %s already exists", newName)
+	} else if errors.Cause(err) != ErrObjectNotFound {
+		return err
+	}
+
+	oldName := h.Name
+	h.Name = newName
+
+	if err := r.setSuperordinate(tx, s, h); err != nil {
+		return err
+	}
+
+	domains, err := tx.DomainsByHost(oldName)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range domains {
+		d, err := tx.Domain(name)
+		if err != nil {
+			return err
+		}
+
+		d.Hosts[indexOf(d.Hosts, oldName)] = newName
+
+		if err := tx.UpdateDomain(d); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 호스트 이름에 맞는 상위 도메인을 찾아서 설정합니다. 상위 도메인이 레지스트리에
+// 있는 경우 내부 호스트가 되며, 상위 도메인을 관리하는 클라이언트만 생성할 수
+// 있고 주소가 반드시 있어야 합니다. 외부 호스트는 주소를 가질 수 없습니다.
+func (r *Registry) setSuperordinate(tx Repository, s *epp.Session, h *Host) error {
+	h.Superordinate = ""
+
+	labels := strings.Split(h.Name, ".")
+
+	for i := 1; i < len(labels)-1; i++ {
+		parent := strings.Join(labels[i:], ".")
+
+		d, err := tx.Domain(parent)
+		if errors.Cause(err) == ErrObjectNotFound {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if d.ClientID != s.ClientID {
+			return errorf(epp.EppAuthorisationError, "superordinate domain %s is not sponsored by %s", parent, s.ClientID)
+		}
+
+		h.Superordinate = parent
+
+		break
+	}
+
+	if h.Superordinate == "" && len(h.Addresses) > 0 {
+		return errorf(epp.EppParamPolicyError, "external host %s can not have addresses", h.Name)
+	}
+
+	if h.Superordinate != "" && len(h.Addresses) == 0 {
+		return errorf(epp.EppParamPolicyError, "host %s requires at least one address", h.Name)
+	}
+
+	return nil
+}
+
+// 호스트를 관리하는 클라이언트로 로그인 되어 있는 경우에만 호스트를 반환합니다.
+func (r *Registry) sponsoredHost(tx Repository, s *epp.Session, name string) (*Host, error) {
+	h, err := tx.Host(name)
+	if err != nil {
+		return nil, notFound(err, "host %s does not exist", name)
+	}
+
+	if h.ClientID != s.ClientID {
+		return nil, errorf(epp.EppAuthorisationError, "host %s is not sponsored by %s", name, s.ClientID)
+	}
+
+	return h, nil
+}
+
+// 호스트의 상태를 반환합니다. 도메인에서 사용되는 호스트는 linked 상태를 가지며,
+// 다른 상태가 없는 경우 ok 상태를 가집니다.
+func (r *Registry) hostStatus(tx Repository, h *Host) ([]types.HostStatus, error) {
+	status := []types.HostStatus{}
+
+	for _, s := range h.Status {
+		status = append(status, types.HostStatus{HostStatusType: s})
+	}
+
+	domains, err := tx.DomainsByHost(h.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(domains) > 0 {
+		status = append(status, types.HostStatus{HostStatusType: types.HostStatusLinked})
+	}
+
+	if len(status) == 0 || (len(status) == 1 && len(domains) > 0) {
+		status = append([]types.HostStatus{{HostStatusType: types.HostStatusOk}}, status...)
+	}
+
+	return status, nil
+}
+
+// 도메인과 함께 이전되는 종속 호스트의 관리 클라이언트를 변경합니다.
+func transferSubordinateHosts(tx Repository, domain, clientID string, date time.Time) error {
+	names, err := tx.HostsBySuperordinate(domain)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		h, err := tx.Host(name)
+		if err != nil {
+			return err
+		}
+
+		h.ClientID = clientID
+		h.TransferDate = &date
+
+		if err := tx.UpdateHost(name, h); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func removeFromHost(h *Host, rem *types.HostAddRemove) error {
+	addresses, err := normalizeAddresses(rem.Address)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range addresses {
+		found := false
+
+		for i, existing := range h.Addresses {
+			if existing == a {
+				h.Addresses = append(h.Addresses[:i], h.Addresses[i+1:]...)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return errorf(epp.EppParamPolicyError, "address %s is not associated with host %s", a.Address, h.Name)
+		}
+	}
+
+	for _, status := range rem.Status {
+		if _, ok := clientHostStatuses[status.HostStatusType]; !ok {
+			return errorf(epp.EppParamPolicyError, "status %s can not be removed by the client", status.HostStatusType)
+		}
+
+		found := false
+
+		for i, s := range h.Status {
+			if s == status.HostStatusType {
+				h.Status = append(h.Status[:i], h.Status[i+1:]...)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return errorf(epp.EppParamPolicyError, "host %s does not have status %s", h.Name, status.HostStatusType)
+		}
+	}
+
+	return nil
+}
+
+func addToHost(h *Host, add *types.HostAddRemove) error {
+	addresses, err := normalizeAddresses(add.Address)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range addresses {
+		for _, existing := range h.Addresses {
+			if existing == a {
+				return errorf(epp.EppParamPolicyError, "address %s is already associated with host %s", a.Address, h.Name)
+			}
+		}
+
+		h.Addresses = append(h.Addresses, a)
+	}
+
+	for _, status := range add.Status {
+		if _, ok := clientHostStatuses[status.HostStatusType]; !ok {
+			return errorf(epp.EppParamPolicyError, "status %s can not be added by the client", status.HostStatusType)
+		}
+
+		if h.hasStatus(status.HostStatusType) {
+			return errorf(epp.EppParamPolicyError, "host %s already has status %s", h.Name, status.HostStatusType)
+		}
+
+		h.Status = append(h.Status, status.HostStatusType)
+	}
+
+	return nil
+}
+
+// 주소가 올바른 IP 주소인지 확인하고 IP 버전을 설정합니다.
+func normalizeAddresses(addresses []types.HostAddress) ([]types.HostAddress, error) {
+	result := []types.HostAddress{}
+
+	for _, a := range addresses {
+		ip := net.ParseIP(strings.TrimSpace(a.Address))
+		if ip == nil {
+			return nil, errorf(epp.EppParamSyntaxError, "invalid address %s", a.Address)
+		}
+
+		version := types.HostIPv4
+		if ip.To4() == nil {
+			version = types.HostIPv6
+		}
+
+		if a.IP != "" && a.IP != version {
+			return nil, errorf(epp.EppParamSyntaxError, "address %s is not an ip%s address", a.Address, a.IP)
+		}
+
+		result = append(result, types.HostAddress{
+			Address: ip.String(),
+			IP:      version,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,107 @@
+package registry
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/dnssec"
+	"github.com/bombsimon/epp-go/types"
+)
+
+// xsd:duration 형식의 상대적인 만료 기간입니다. 음수인 기간은 허용하지 않습니다.
+var keyRelayDuration = regexp.MustCompile(`^P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?$`)
+
+// keyrelay 명령어로 받은 키를 도메인의 관리 클라이언트에게 서비스 메시지로 전달합니다.
+// (RFC 8063) DNS 운영자가 바뀌는 이전에서 새로운 운영자의 키를 현재 운영자가 영역에
+// 추가할 수 있도록 사용하며, 요청하는 클라이언트는 도메인의 인증 정보를 알아야 합니다.
+func (r *Registry) keyRelay(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.KeyRelayType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if !r.KeyRelay {
+		return nil, errorf(epp.EppUnimplementedCommand, "the keyrelay command is not supported")
+	}
+
+	relay := cmd.Command.KeyRelay
+	name := normalize(relay.Name)
+
+	d, err := r.Repository.Domain(name)
+	if err != nil {
+		return nil, notFound(err, "domain %s does not exist", name)
+	}
+
+	if d.ClientID == s.ClientID {
+		return nil, errorf(epp.EppParamPolicyError, "domain %s is already sponsored by %s", name, s.ClientID)
+	}
+
+	if !r.validDomainAuthInfo(&types.AuthInfo{Password: relay.AuthInfo.Password}, d) {
+		return nil, errorf(epp.EppInvalidAuthInfo, "invalid authorization information")
+	}
+
+	if len(relay.KeyRelayData) == 0 {
+		return nil, errorf(epp.EppMissingParam, "keyRelayData is required")
+	}
+
+	for _, kr := range relay.KeyRelayData {
+		if err := r.validKeyRelayData(kr); err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = r.Notify(d.ClientID, fmt.Sprintf("Key relay for domain %s.", d.Name), &types.PollResultData{
+		KeyRelayInfoData: &types.KeyRelayInfoData{
+			Name:         d.Name,
+			KeyRelayData: relay.KeyRelayData,
+			CreateDate:   r.now(),
+			RequestingID: s.ClientID,
+			ActingID:     d.ClientID,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+// 전달할 키가 영역 서명 키(ZSK 또는 KSK)인지 확인하고 만료 기간을 확인합니다.
+func (r *Registry) validKeyRelayData(kr types.KeyRelayData) error {
+	if err := validDNSKEY(types.DNSSECKeyData(kr.KeyData)); err != nil {
+		return err
+	}
+
+	if kr.Expiry == nil {
+		return nil
+	}
+
+	switch {
+	case kr.Expiry.Absolute != nil && kr.Expiry.Relative != "":
+		return errorf(epp.EppParamSyntaxError, "expiry must be either absolute or relative")
+	case kr.Expiry.Absolute != nil && !kr.Expiry.Absolute.After(r.now()):
+		return errorf(epp.EppParamRangeError, "expiry %s has already passed", kr.Expiry.Absolute.Format(time.RFC3339))
+	case kr.Expiry.Absolute == nil && !validDuration(kr.Expiry.Relative):
+		return errorf(epp.EppParamSyntaxError, "invalid relative expiry %s", kr.Expiry.Relative)
+	}
+
+	return nil
+}
+
+// DNSKEY 의 값이 RFC 4034 에 맞는지 확인하고 검증 오류를 응답할 수 있는 오류로
+// 바꿉니다.
+func validDNSKEY(key types.DNSSECKeyData) error {
+	err := dnssec.ValidateKeyData(key)
+	if e, ok := err.(*dnssec.Error); ok {
+		return errorf(e.Code, "%s", e.Reason)
+	}
+
+	return err
+}
+
+func validDuration(duration string) bool {
+	return keyRelayDuration.MatchString(duration) && duration != "P" && duration[len(duration)-1] != 'T'
+}
@@ -0,0 +1,110 @@
+package registry
+
+import (
+	"testing"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keyrelay 명령어는 확장 안에 트랜잭션 ID 를 가지므로 CommandBuilder 없이 전송합니다.
+func sendKeyRelay(t *testing.T, tr *testRegistry, s *epp.Session, want epp.ResultCode, relay types.KeyRelay) {
+	t.Helper()
+
+	data, err := epp.Encode(types.KeyRelayType{
+		Command: types.KeyRelayCommand{KeyRelay: relay, ClientTransactionID: "ABC-12345"},
+	}, epp.ClientXMLAttributes())
+	require.Nil(t, err)
+	require.Nil(t, tr.validator.Validate(data), string(data))
+
+	response, err := tr.mux.Handle(s, data)
+	require.Nil(t, err)
+	require.Nil(t, tr.validator.Validate(response), string(response))
+
+	result := types.Response{}
+	require.Nil(t, epp.Decode(response, &result))
+	assert.Equal(t, want.Code(), result.Result[0].Code, string(response))
+	assert.Equal(t, "ABC-12345", result.TransactionID.ClientTransactionID)
+}
+
+func testRegistryKeyRelay(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+
+	key := types.KeyRelayKeyData{Flags: 257, Protocol: 3, Algorithm: 13, PublicKey: "cmlraXN0aGViZXN0"}
+	expiry := tr.now.AddDate(0, 1, 0)
+	relay := types.KeyRelay{
+		Name:     "example.se",
+		AuthInfo: types.KeyRelayAuthInfo{Password: "2fooBAR"},
+		KeyRelayData: []types.KeyRelayData{
+			{KeyData: key, Expiry: &types.KeyRelayExpiry{Absolute: &expiry}},
+			{KeyData: key, Expiry: &types.KeyRelayExpiry{Relative: "P1M13D"}},
+		},
+	}
+
+	sendKeyRelay(t, tr, other, epp.EppUnimplementedCommand, relay)
+
+	tr.registry.KeyRelay = true
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceKeyRelay10)
+
+	// The key can only be relayed with the authorization information of an
+	// existing domain sponsored by another client.
+	unknown := relay
+	unknown.Name = "example.nu"
+	sendKeyRelay(t, tr, other, epp.EppObjectDoesNotExist, unknown)
+
+	wrong := relay
+	wrong.AuthInfo.Password = "wrong"
+	sendKeyRelay(t, tr, other, epp.EppInvalidAuthInfo, wrong)
+	sendKeyRelay(t, tr, s, epp.EppParamPolicyError, relay)
+
+	// Keys must be valid zone keys using a supported algorithm with an expiry
+	// in the future.
+	for invalid, code := range map[*types.KeyRelayData]epp.ResultCode{
+		{KeyData: types.KeyRelayKeyData{Flags: 0, Protocol: 3, Algorithm: 13, PublicKey: key.PublicKey}}:   epp.EppParamSyntaxError,
+		{KeyData: types.KeyRelayKeyData{Flags: 256, Protocol: 2, Algorithm: 13, PublicKey: key.PublicKey}}: epp.EppParamSyntaxError,
+		{KeyData: types.KeyRelayKeyData{Flags: 256, Protocol: 3, Algorithm: 1, PublicKey: key.PublicKey}}:  epp.EppParamPolicyError,
+		{KeyData: key, Expiry: &types.KeyRelayExpiry{Absolute: &tr.now}}:                                   epp.EppParamRangeError,
+	} {
+		r := relay
+		r.KeyRelayData = []types.KeyRelayData{*invalid}
+		sendKeyRelay(t, tr, other, code, r)
+	}
+
+	// Invalid public keys and durations are rejected by the XSD as well.
+	assert.NotNil(t, validDNSKEY(types.DNSSECKeyData{Flags: 256, Protocol: 3, Algorithm: 13, PublicKey: "not base64!"}))
+
+	for _, duration := range []string{"P", "P1DT", "-P1D", "1D"} {
+		assert.False(t, validDuration(duration), duration)
+	}
+
+	// Only relayed keys are delivered to the sponsoring client.
+	tr.send(s, epp.EppOkNoMessages, pollCommand(types.PollOperationRequest, ""))
+
+	// The key is delivered to the sponsoring client.
+	sendKeyRelay(t, tr, other, epp.EppOk, relay)
+
+	response := tr.send(s, epp.EppOkMessages, pollCommand(types.PollOperationRequest, ""))
+
+	data := types.PollResultData{}
+	require.Nil(t, epp.Decode(response, &types.Response{ResultData: &data}))
+	require.NotNil(t, data.KeyRelayInfoData)
+
+	infoData := data.KeyRelayInfoData
+	assert.Equal(t, "example.se", infoData.Name)
+	assert.Equal(t, "ClientY", infoData.RequestingID)
+	assert.Equal(t, "ClientX", infoData.ActingID)
+	assert.True(t, tr.now.Equal(infoData.CreateDate))
+	require.Len(t, infoData.KeyRelayData, 2)
+	assert.Equal(t, key, infoData.KeyRelayData[0].KeyData)
+	assert.True(t, expiry.Equal(*infoData.KeyRelayData[0].Expiry.Absolute))
+	assert.Equal(t, "P1M13D", infoData.KeyRelayData[1].Expiry.Relative)
+}
@@ -0,0 +1,592 @@
+package registry
+
+import (
+	"fmt"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 런치 단계의 정책입니다. (RFC 8334) Registry.Launch 가 nil 이면 launch-1.0 확장을
+// 지원하지 않고 모든 도메인은 바로 등록됩니다.
+type Launch struct {
+	// 런치 단계 목록입니다. 시작 시간이 지났고 종료 시간이 지나지 않은 마지막 단계가
+	// 현재 단계가 되며, 현재 단계가 없으면 누구나 도메인을 등록할 수 있습니다.
+	Phases []LaunchPhase
+
+	// 도메인 이름과 일치하는 상표의 클레임 키를 반환합니다. nil 이면 클레임이 있는
+	// 도메인이 없는 것으로 처리합니다.
+	ClaimKeys func(name string) ([]types.LaunchValidatorValue, error)
+
+	// sunrise 단계에서 전달된 상표 정보를 검증합니다. nil 이면 상표 정보가 있는지만
+	// 확인합니다. *Error 가 아닌 오류는 2306 으로 응답합니다.
+	ValidateMarks func(name string, create *types.LaunchCreate) error
+}
+
+// 하나의 런치 단계입니다.
+type LaunchPhase struct {
+	Phase types.LaunchPhase
+	Start time.Time
+
+	// 단계가 끝나는 시간입니다. 0 이면 단계가 끝나지 않습니다.
+	End time.Time
+
+	// true 이면 도메인을 바로 등록하지 않고 신청을 받습니다. 신청은
+	// SetApplicationStatus 로 할당되면 도메인으로 등록됩니다.
+	Applications bool
+}
+
+// 더 이상 바뀌지 않는 신청 상태입니다.
+var finalApplicationStatuses = []types.LaunchStatusType{
+	types.LaunchStatusAllocated,
+	types.LaunchStatusRejected,
+	types.LaunchStatusInvalid,
+}
+
+// 현재 런치 단계를 반환합니다. 런치 단계가 없으면 nil 을 반환합니다.
+func (r *Registry) currentPhase() *LaunchPhase {
+	if r.Launch == nil {
+		return nil
+	}
+
+	now := r.now()
+
+	var current *LaunchPhase
+
+	for i, p := range r.Launch.Phases {
+		if now.Before(p.Start) || (!p.End.IsZero() && !now.Before(p.End)) {
+			continue
+		}
+
+		current = &r.Launch.Phases[i]
+	}
+
+	return current
+}
+
+// 명령어에 지정된 단계가 현재 런치 단계인지 확인하고 현재 단계를 반환합니다.
+func (r *Registry) launchPhase(phase types.LaunchPhase) (*LaunchPhase, error) {
+	if r.Launch == nil {
+		return nil, errorf(epp.EppUnimplementedExtension, "launch-1.0 is not supported")
+	}
+
+	current := r.currentPhase()
+	if current == nil || current.Phase != phase {
+		return nil, errorf(epp.EppParamPolicyError, "launch phase %s is not active", phase.Phase)
+	}
+
+	return current, nil
+}
+
+// launch-1.0 확장 없이 도메인을 등록할 수 있는지 확인합니다. claims 단계에서는
+// 클레임이 없는 도메인만, open 단계와 런치 단계가 없을 때는 모든 도메인을 등록할
+// 수 있습니다.
+func (r *Registry) checkOpenRegistration(name string) error {
+	current := r.currentPhase()
+	if current == nil {
+		return nil
+	}
+
+	switch current.Phase.Phase {
+	case types.LaunchPhaseOpen:
+		return nil
+	case types.LaunchPhaseClaims:
+		return r.checkClaimsNotice(name, nil)
+	}
+
+	return errorf(epp.EppParamPolicyError, "the %s phase requires the launch extension", current.Phase.Phase)
+}
+
+// 도메인에 클레임이 있으면 유효한 클레임 알림을 받았는지 확인합니다.
+func (r *Registry) checkClaimsNotice(name string, notices []types.LaunchNotice) error {
+	keys, err := r.claimKeys(name)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	if len(notices) == 0 {
+		return errorf(epp.EppMissingParam, "domain %s has trademark claims, a claims notice is required", name)
+	}
+
+	now := r.now()
+
+	for _, n := range notices {
+		if n.NoticeID.Value != "" && now.Before(n.NotAfter) && !n.AcceptedDate.After(now) {
+			return nil
+		}
+	}
+
+	return errorf(epp.EppParamPolicyError, "claims notice for domain %s is not valid", name)
+}
+
+func (r *Registry) claimKeys(name string) ([]types.LaunchValidatorValue, error) {
+	if r.Launch == nil || r.Launch.ClaimKeys == nil {
+		return nil, nil
+	}
+
+	return r.Launch.ClaimKeys(name)
+}
+
+func (r *Registry) checkDomainLaunch(s *epp.Session, data []byte, fee bool) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainCheckType{}
+	ext := types.LaunchExtensionCheckType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	if r.Launch == nil {
+		return nil, errorf(epp.EppUnimplementedExtension, "launch-1.0 is not supported")
+	}
+
+	phase := ext.Check.Phase
+
+	switch ext.Check.Type {
+	case "", types.LaunchCheckClaims:
+		// 클레임 확인에는 사용 가능 여부가 없으므로 요금을 함께 확인할 수 없습니다.
+		if fee {
+			return nil, errorf(epp.EppUnimplementedExtension, "the fee extension can not be used with a claims check")
+		}
+
+		if phase != nil {
+			if _, err := r.launchPhase(*phase); err != nil {
+				return nil, err
+			}
+		}
+
+		result := types.LaunchCheckData{Phase: phase}
+
+		for _, name := range cmd.Check.Names {
+			keys, err := r.claimKeys(normalize(name))
+			if err != nil {
+				return nil, err
+			}
+
+			result.CheckData = append(result.CheckData, types.LaunchCheckDataItem{
+				Name:     types.LaunchCheckName{Name: name, Exists: len(keys) > 0},
+				ClaimKey: keys,
+			})
+		}
+
+		// 클레임 확인은 resData 없이 확장으로만 응답합니다. (RFC 8334 3.1.1)
+		return epp.NewResponse(epp.EppOk).WithExtension(types.LaunchExtensionCheckDataType{
+			CheckData: result,
+		}), nil
+	case types.LaunchCheckAvail:
+		if phase == nil {
+			return nil, errorf(epp.EppMissingParam, "phase is required for an availability check")
+		}
+
+		if _, err := r.launchPhase(*phase); err != nil {
+			return nil, err
+		}
+
+		if fee {
+			return r.checkDomainFee(s, data)
+		}
+
+		return r.checkDomain(s, data)
+	}
+
+	return nil, errorf(epp.EppUnimplementedOption, "launch check type %s is not supported", ext.Check.Type)
+}
+
+func (r *Registry) createDomainLaunch(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainCreateType{}
+	ext := types.LaunchExtensionCreateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	launch := ext.Create
+	name := normalize(cmd.Create.Name)
+
+	current, err := r.launchPhase(launch.Phase)
+	if err != nil {
+		return nil, err
+	}
+
+	if launch.Type != "" && (launch.Type == types.LaunchObjectApplication) != current.Applications {
+		return nil, errorf(epp.EppParamPolicyError, "the %s phase does not accept %s objects", launch.Phase.Phase, launch.Type)
+	}
+
+	switch launch.Phase.Phase {
+	case types.LaunchPhaseSunrise:
+		if len(launch.CodeMark) == 0 && len(launch.EncodedSignedMark) == 0 {
+			return nil, errorf(epp.EppMissingParam, "a mark is required in the sunrise phase")
+		}
+
+		if r.Launch.ValidateMarks != nil {
+			if err := r.Launch.ValidateMarks(name, &launch); err != nil {
+				if _, ok := errors.Cause(err).(*Error); !ok {
+					err = errorf(epp.EppParamPolicyError, "invalid mark: %s", err.Error())
+				}
+
+				return nil, err
+			}
+		}
+	case types.LaunchPhaseClaims:
+		if err := r.checkClaimsNotice(name, launch.Notice); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := r.allocationToken(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.verifyAllocationToken(token, name, s.ClientID); err != nil {
+		return nil, err
+	}
+
+	orgs, err := r.orgExtensionCreate(data)
+	if err != nil {
+		return nil, err
+	}
+
+	sec, err := r.dnssecExtensionCreate(data, name)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := r.chargeFee(data, name, types.FeeCommandCreate, cmd.Create.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	var response *epp.ResponseBuilder
+
+	// 신청은 토큰을 저장해두고 할당될 때 사용합니다.
+	if current.Applications {
+		response, err = r.createApplication(s, cmd.Create, launch, token, orgs, sec)
+	} else {
+		response, err = r.registerDomain(s, cmd.Create, token, orgs, sec)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return withFee(response, types.FeeCommandCreate, fee), nil
+}
+
+// 도메인을 등록하지 않고 신청을 저장합니다. 신청은 할당될 때까지 pendingCreate
+// 상태로 응답하며, 할당된 도메인은 token 을 사용하고 orgs 의 조직과 sec 의 DNSSEC
+// 위임 정보로 등록됩니다.
+func (r *Registry) createApplication(s *epp.Session, create types.DomainCreate, launch types.LaunchCreate, token string, orgs []types.OrgExtensionID, sec *types.DNSSECOrKeyData) (*epp.ResponseBuilder, error) {
+	d, months, err := r.newDomain(s, create)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Organizations = orgs
+	d.DNSSEC = sec
+
+	a := &Application{
+		Phase:           launch.Phase,
+		Status:          types.LaunchStatusPendingValidation,
+		Months:          months,
+		ClientID:        s.ClientID,
+		CreateDate:      d.CreateDate,
+		AllocationToken: token,
+	}
+
+	for _, cm := range launch.CodeMark {
+		if cm.Mark != nil {
+			a.Marks = append(a.Marks, *cm.Mark)
+		}
+	}
+
+	err = r.Repository.Transaction(func(tx Repository) error {
+		if err := r.prepareDomain(tx, d); err != nil {
+			return err
+		}
+
+		if err := checkOrgLinks(tx, d.ClientID, d.Organizations); err != nil {
+			return err
+		}
+
+		id, err := r.roid(tx, "A")
+		if err != nil {
+			return err
+		}
+
+		a.ID = id
+		a.Domain = *d
+
+		return tx.CreateApplication(a)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOkPending).
+		WithResData(types.DomainCreateDataType{
+			CreateData: types.DomainCreateData{
+				Name:       d.Name,
+				CreateDate: d.CreateDate,
+			},
+		}).
+		WithExtension(types.LaunchExtensionCreateDataType{
+			CreateData: types.LaunchApplication{
+				Phase:         a.Phase,
+				ApplicationID: a.ID,
+			},
+		}), nil
+}
+
+func (r *Registry) infoDomainLaunch(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainInfoType{}
+	ext := types.LaunchExtensionInfoType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	// 신청 ID 가 없으면 등록된 도메인을 조회합니다.
+	if ext.Info.ApplicationID == "" {
+		return r.infoDomain(s, data)
+	}
+
+	a, err := r.sponsoredApplication(r.Repository, s, normalize(cmd.Info.Name.Name), ext.Info.Phase, ext.Info.ApplicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := a.Domain
+	createDate := d.CreateDate
+
+	info := types.DomainInfoData{
+		Name: d.Name,
+		ROID: d.ROID,
+		Status: []types.DomainStatus{
+			{DomainStatusType: types.DomainStatusPendingCreate},
+		},
+		Registrant: d.Registrant,
+		Contact:    d.Contacts,
+		ClientID:   a.ClientID,
+		CreateID:   d.CreateID,
+		CreateDate: &createDate,
+		UpdateID:   a.UpdateID,
+		UpdateDate: a.UpdateDate,
+	}
+
+	info.AuthInfo = r.domainAuthInfo(&d)
+
+	if len(d.Hosts) > 0 {
+		info.NameServer = &types.NameServer{HostObject: d.Hosts}
+	}
+
+	launchInfo := types.LaunchInfoData{
+		Phase:         a.Phase,
+		ApplicationID: a.ID,
+		Status:        &types.LaunchStatus{Status: a.Status},
+	}
+
+	if ext.Info.IncludeMark {
+		launchInfo.Mark = a.Marks
+	}
+
+	return epp.NewResponse(epp.EppOk).
+		WithResData(types.DomainInfoDataType{InfoData: info}).
+		WithExtension(types.LaunchExtensionInfoDataType{InfoData: launchInfo}), nil
+}
+
+func (r *Registry) updateDomainLaunch(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainUpdateType{}
+	ext := types.LaunchExtensionUpdateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		a, err := r.sponsoredApplication(tx, s, normalize(cmd.Update.Name), ext.Update.Phase, ext.Update.ApplicationID)
+		if err != nil {
+			return err
+		}
+
+		if a.hasStatus(finalApplicationStatuses...) {
+			return errorf(epp.EppStatusProhibitsOp, "application %s is %s", a.ID, a.Status)
+		}
+
+		if err := r.changeDomain(tx, &a.Domain, cmd.Update); err != nil {
+			return err
+		}
+
+		now := r.now()
+		a.UpdateID = s.ClientID
+		a.UpdateDate = &now
+
+		return tx.UpdateApplication(a)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func (r *Registry) deleteDomainLaunch(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainDeleteType{}
+	ext := types.LaunchExtensionDeleteType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		a, err := r.sponsoredApplication(tx, s, normalize(cmd.Delete.Name), ext.Delete.Phase, ext.Delete.ApplicationID)
+		if err != nil {
+			return err
+		}
+
+		if a.hasStatus(types.LaunchStatusAllocated) {
+			return errorf(epp.EppStatusProhibitsOp, "application %s is allocated", a.ID)
+		}
+
+		return tx.DeleteApplication(a.ID)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+// 신청을 관리하는 클라이언트로 로그인 되어 있고 신청이 주어진 도메인과 단계에 속한
+// 경우에만 신청을 반환합니다.
+func (r *Registry) sponsoredApplication(tx Repository, s *epp.Session, name string, phase types.LaunchPhase, id string) (*Application, error) {
+	if r.Launch == nil {
+		return nil, errorf(epp.EppUnimplementedExtension, "launch-1.0 is not supported")
+	}
+
+	a, err := tx.Application(id)
+	if err != nil {
+		return nil, notFound(err, "application %s does not exist", id)
+	}
+
+	if a.ClientID != s.ClientID {
+		return nil, errorf(epp.EppAuthorisationError, "application %s is not sponsored by %s", id, s.ClientID)
+	}
+
+	if a.Domain.Name != name {
+		return nil, errorf(epp.EppObjectDoesNotExist, "application %s does not exist for domain %s", id, name)
+	}
+
+	if a.Phase != phase {
+		return nil, errorf(epp.EppParamPolicyError, "application %s does not belong to the %s phase", id, phase.Phase)
+	}
+
+	return a, nil
+}
+
+// 신청의 상태를 바꾸고 관리 클라이언트에게 서비스 메시지를 보냅니다. allocated 로
+// 바뀐 신청은 그 시점부터 등록 기간이 시작되는 도메인으로 등록되고, 신청할 때 받은
+// 조직이 연결되며 할당 토큰이 사용됩니다. 토큰이 이미 사용되었거나 조직을 연결할 수
+// 없으면 할당되지 않습니다. 할당, 거절, 무효 처리된 신청의 상태는 더 이상 바꿀 수
+// 없습니다.
+func (r *Registry) SetApplicationStatus(id string, status types.LaunchStatusType) error {
+	var notices []notice
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		a, err := tx.Application(id)
+		if err != nil {
+			return notFound(err, "application %s does not exist", id)
+		}
+
+		if a.hasStatus(finalApplicationStatuses...) {
+			return errorf(epp.EppStatusProhibitsOp, "application %s is %s", id, a.Status)
+		}
+
+		now := r.now()
+		a.Status = status
+		a.UpdateDate = &now
+
+		if status == types.LaunchStatusAllocated {
+			d := a.Domain.copy()
+			d.CreateDate = now
+			d.ExpireDate = now.AddDate(0, a.Months, 0)
+
+			if _, err := tx.Domain(d.Name); err == nil {
+				return errorf(epp.EppObjectExists, "domain %s already exists", d.Name)
+			} else if errors.Cause(err) != ErrObjectNotFound {
+				return err
+			}
+
+			if err := checkDomainReferences(tx, d); err != nil {
+				return err
+			}
+
+			if err := checkOrgLinks(tx, d.ClientID, d.Organizations); err != nil {
+				return err
+			}
+
+			r.addGracePeriod(d, GraceAdd, now, r.Lifecycle.AddGracePeriod, a.Months)
+
+			if err := tx.CreateDomain(d); err != nil {
+				return err
+			}
+
+			if err := r.useAllocationToken(a.AllocationToken, d.Name); err != nil {
+				return err
+			}
+		}
+
+		notices = append(notices, notice{
+			clientID: a.ClientID,
+			message:  fmt.Sprintf("Application %s for domain %s is %s.", a.ID, a.Domain.Name, a.Status),
+		})
+
+		return tx.UpdateApplication(a)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	r.sendNotices(notices)
+
+	return nil
+}
+
+func (a *Application) hasStatus(status ...types.LaunchStatusType) bool {
+	for _, want := range status {
+		if a.Status == want {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,255 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func launchPhase(phase types.LaunchPhaseType) types.LaunchPhase {
+	return types.LaunchPhase{Phase: phase}
+}
+
+func launchCreate(phase types.LaunchPhaseType, marks ...string) types.LaunchExtensionCreateType {
+	ext := types.LaunchExtensionCreateType{
+		Create: types.LaunchCreate{Phase: launchPhase(phase)},
+	}
+
+	for _, m := range marks {
+		ext.Create.EncodedSignedMark = append(ext.Create.EncodedSignedMark, types.EncodedSignedMark{Value: m})
+	}
+
+	return ext
+}
+
+func testRegistryLaunch(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	day := 24 * time.Hour
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+
+	// Without a launch policy the extension is not supported.
+	tr.send(s, epp.EppUnimplementedExtension, domainCreate("example.se"), launchCreate(types.LaunchPhaseSunrise, "c21k"))
+
+	tr.registry.Launch = &Launch{
+		Phases: []LaunchPhase{
+			{Phase: launchPhase(types.LaunchPhaseSunrise), Start: tr.now, End: tr.now.Add(10 * day)},
+			{Phase: launchPhase(types.LaunchPhaseClaims), Start: tr.now.Add(10 * day), End: tr.now.Add(20 * day)},
+			{Phase: launchPhase(types.LaunchPhaseOpen), Start: tr.now.Add(20 * day)},
+		},
+		ClaimKeys: func(name string) ([]types.LaunchValidatorValue, error) {
+			if name == "claimed.se" {
+				return []types.LaunchValidatorValue{{Value: "2013041500/2/6/9/rJ1N", ValidatorID: "tmch"}}, nil
+			}
+
+			return nil, nil
+		},
+		ValidateMarks: func(name string, create *types.LaunchCreate) error {
+			if create.EncodedSignedMark[0].Value != "c21k" {
+				return errors.New("signature is not valid")
+			}
+
+			return nil
+		},
+	}
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceLaunch10)
+
+	// The sunrise phase requires a valid mark.
+	tr.send(s, epp.EppParamPolicyError, domainCreate("example.se"))
+	tr.send(s, epp.EppParamPolicyError, domainCreate("example.se"), launchCreate(types.LaunchPhaseClaims))
+	tr.send(s, epp.EppMissingParam, domainCreate("example.se"), launchCreate(types.LaunchPhaseSunrise))
+	tr.send(s, epp.EppParamPolicyError, domainCreate("example.se"), launchCreate(types.LaunchPhaseSunrise, "aW52YWxpZA=="))
+
+	response := tr.send(s, epp.EppOk, domainCreate("example.se"), launchCreate(types.LaunchPhaseSunrise, "c21k"))
+
+	created := types.DomainCreateDataType{}
+	decodeResData(t, response, &created)
+	assert.Equal(t, "example.se", created.CreateData.Name)
+
+	// A claims check only reports the claim keys.
+	check := types.DomainCheckType{Check: types.DomainCheck{Names: []string{"claimed.se", "example.nu"}}}
+	claims := types.LaunchExtensionCheckDataType{}
+
+	response = tr.send(s, epp.EppOk, check, types.LaunchExtensionCheckType{})
+	require.Nil(t, epp.Decode(response, &types.Response{Extension: &claims}))
+	require.Len(t, claims.CheckData.CheckData, 2)
+	assert.True(t, claims.CheckData.CheckData[0].Name.Exists)
+	assert.Equal(t, "tmch", claims.CheckData.CheckData[0].ClaimKey[0].ValidatorID)
+	assert.False(t, claims.CheckData.CheckData[1].Name.Exists)
+
+	tr.send(s, epp.EppMissingParam, check, types.LaunchExtensionCheckType{
+		Check: types.LaunchCheck{Type: types.LaunchCheckAvail},
+	})
+
+	// An availability check can include the fees, in any extension order, but a
+	// claims check can not.
+	sunrise := launchPhase(types.LaunchPhaseSunrise)
+	avail := types.LaunchExtensionCheckType{Check: types.LaunchCheck{Type: types.LaunchCheckAvail, Phase: &sunrise}}
+	checkFee := types.FeeExtensionCheckType{
+		Check: types.FeeCheck{Command: []types.FeeCommand{{Name: types.FeeCommandCreate}}},
+	}
+
+	tr.send(s, epp.EppUnimplementedExtension, check, avail, checkFee)
+
+	tr.registry.Pricing = &TieredPricing{
+		CurrencyCode: "SEK",
+		Prices: map[string]map[types.FeeCommandName]string{
+			FeeClassStandard: {types.FeeCommandCreate: "100.00"},
+		},
+	}
+
+	for _, extensions := range [][]interface{}{{avail, checkFee}, {checkFee, avail}} {
+		response = tr.send(s, epp.EppOk, check, extensions...)
+
+		checked := types.DomainChekDataType{}
+		decodeResData(t, response, &checked)
+		require.Len(t, checked.CheckData.CheckDomain, 2)
+		assert.True(t, checked.CheckData.CheckDomain[0].Name.Available)
+
+		fees := types.FeeExtensionCheckDataType{}
+		require.Nil(t, epp.Decode(response, &types.Response{Extension: &fees}))
+		require.Len(t, fees.CheckData.CheckData, 2)
+		assert.Equal(t, "100.00", fees.CheckData.CheckData[0].Command[0].Fee[0].Value)
+	}
+
+	tr.send(s, epp.EppUnimplementedExtension, check, types.LaunchExtensionCheckType{}, checkFee)
+	tr.send(s, epp.EppUnimplementedExtension, check, checkFee, types.LaunchExtensionCheckType{})
+	tr.send(s, epp.EppParamPolicyError, check, checkFee, types.LaunchExtensionCheckType{
+		Check: types.LaunchCheck{Type: types.LaunchCheckAvail, Phase: &types.LaunchPhase{Phase: types.LaunchPhaseLandrush}},
+	})
+
+	tr.registry.Pricing = nil
+
+	// The claims phase requires a notice for names with claims.
+	tr.now = tr.now.Add(10 * day)
+
+	notice := launchCreate(types.LaunchPhaseClaims)
+	notice.Create.Notice = []types.LaunchNotice{{
+		NoticeID:     types.LaunchValidatorValue{Value: "370d0b7c9223372036854775807", ValidatorID: "tmch"},
+		NotAfter:     tr.now.Add(day),
+		AcceptedDate: tr.now.Add(-time.Hour),
+	}}
+
+	tr.send(s, epp.EppOk, domainCreate("unclaimed.se"))
+	tr.send(s, epp.EppMissingParam, domainCreate("claimed.se"))
+	tr.send(s, epp.EppMissingParam, domainCreate("claimed.se"), launchCreate(types.LaunchPhaseClaims))
+	tr.send(s, epp.EppOk, domainCreate("claimed.se"), notice)
+
+	tr.now = tr.now.Add(10 * day)
+	tr.send(s, epp.EppOk, domainCreate("open.se"))
+}
+
+func testRegistryLaunchApplications(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+	sunrise := launchPhase(types.LaunchPhaseSunrise)
+
+	tr.registry.Launch = &Launch{
+		Phases: []LaunchPhase{{Phase: sunrise, Start: tr.now, Applications: true}},
+	}
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, contactCreate("sh8013"))
+
+	create := launchCreate(types.LaunchPhaseSunrise)
+	create.Create.Type = types.LaunchObjectApplication
+	create.Create.CodeMark = []types.LaunchCodeMark{{
+		Code: &types.LaunchValidatorValue{Value: "49FD46E6C4B45C55D4AC"},
+		Mark: &types.Mark{Trademark: []types.MarkTrademark{{
+			ID:       "00052013734689731373468973-65535",
+			MarkName: "Example One",
+			Holder: []types.MarkHolder{{
+				Organization: "Example Inc.",
+				Address:      types.MarkAddress{Street: []string{"123 Example Dr."}, City: "Reston", CountryCode: "US"},
+			}},
+			Jurisdiction:       "US",
+			GoodsAndServices:   "Dirigendas et eiusmodi.",
+			RegistrationNumber: "234235",
+			RegistrationDate:   tr.now.AddDate(-10, 0, 0),
+		}}},
+	}}
+
+	registration := launchCreate(types.LaunchPhaseSunrise, "c21k")
+	registration.Create.Type = types.LaunchObjectRegistration
+	tr.send(s, epp.EppParamPolicyError, domainCreate("example.se"), registration)
+
+	applicationID := func(response []byte) string {
+		created := types.LaunchExtensionCreateDataType{}
+		require.Nil(t, epp.Decode(response, &types.Response{Extension: &created}))
+		require.NotEmpty(t, created.CreateData.ApplicationID)
+
+		return created.CreateData.ApplicationID
+	}
+
+	// Two applications for the same name are accepted.
+	otherID := applicationID(tr.send(other, epp.EppOkPending, domainCreate("example.se"), create))
+	id := applicationID(tr.send(s, epp.EppOkPending, domainCreate("example.se"), create))
+
+	_, err := tr.registry.Repository.Domain("example.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	application := types.LaunchApplication{Phase: sunrise, ApplicationID: id}
+	info := types.DomainInfoType{Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}}}
+
+	infoData := func(session *epp.Session, want epp.ResultCode) types.LaunchInfoData {
+		ext := types.LaunchExtensionInfoDataType{}
+		response := tr.send(session, want, info, types.LaunchExtensionInfoType{
+			Info: types.LaunchInfo{IncludeMark: true, Phase: sunrise, ApplicationID: id},
+		})
+
+		require.Nil(t, epp.Decode(response, &types.Response{Extension: &ext}))
+
+		return ext.InfoData
+	}
+
+	launchInfo := infoData(s, epp.EppOk)
+	assert.Equal(t, types.LaunchStatusPendingValidation, launchInfo.Status.Status)
+	require.Len(t, launchInfo.Mark, 1)
+	assert.Equal(t, "Example One", launchInfo.Mark[0].Trademark[0].MarkName)
+
+	infoData(other, epp.EppAuthorisationError)
+
+	// Applications can be updated until they are allocated.
+	update := types.DomainUpdateType{Update: types.DomainUpdate{
+		Name: "example.se",
+		Add:  &types.DomainAddRemove{Contact: []types.Contact{{Name: "sh8013", Type: "tech"}}},
+	}}
+
+	tr.send(s, epp.EppOk, update, types.LaunchExtensionUpdateType{Update: application})
+	tr.send(other, epp.EppAuthorisationError, update, types.LaunchExtensionUpdateType{Update: application})
+
+	require.Nil(t, tr.registry.SetApplicationStatus(id, types.LaunchStatusAllocated))
+	assert.Equal(t, 1, tr.messageCount("ClientX"))
+
+	assert.Equal(t, types.LaunchStatusAllocated, infoData(s, epp.EppOk).Status.Status)
+
+	d := tr.domain("example.se")
+	assert.Equal(t, "ClientX", d.ClientID)
+	assert.Len(t, d.Contacts, 2)
+	assert.True(t, tr.now.AddDate(2, 0, 0).Equal(d.ExpireDate))
+
+	tr.send(s, epp.EppStatusProhibitsOp, update, types.LaunchExtensionUpdateType{Update: application})
+	tr.send(s, epp.EppStatusProhibitsOp, domainDelete("example.se"), types.LaunchExtensionDeleteType{Delete: application})
+
+	assert.NotNil(t, tr.registry.SetApplicationStatus(id, types.LaunchStatusRejected))
+
+	// The name is taken so the other application can not be allocated, but it
+	// can be deleted by its sponsor.
+	assert.NotNil(t, tr.registry.SetApplicationStatus(otherID, types.LaunchStatusAllocated))
+
+	otherApplication := types.LaunchExtensionDeleteType{
+		Delete: types.LaunchApplication{Phase: sunrise, ApplicationID: otherID},
+	}
+
+	tr.send(s, epp.EppAuthorisationError, domainDelete("example.se"), otherApplication)
+	tr.send(other, epp.EppOk, domainDelete("example.se"), otherApplication)
+	tr.send(other, epp.EppObjectDoesNotExist, domainDelete("example.se"), otherApplication)
+}
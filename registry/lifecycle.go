@@ -0,0 +1,427 @@
+package registry
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 도메인의 유예 기간과 삭제 단계입니다. RFC 3915 의 rgpStatus 와 같은 값을 사용합니다.
+type GraceStatus string
+
+// 수명 주기 엔진이 사용하는 유예 기간과 삭제 단계입니다.
+const (
+	GraceAdd            GraceStatus = "addPeriod"
+	GraceAutoRenew      GraceStatus = "autoRenewPeriod"
+	GraceRenew          GraceStatus = "renewPeriod"
+	GraceTransfer       GraceStatus = "transferPeriod"
+	GraceRedemption     GraceStatus = "redemptionPeriod"
+	GracePendingRestore GraceStatus = "pendingRestore"
+	GracePendingDelete  GraceStatus = "pendingDelete"
+)
+
+// 도메인에 적용된 하나의 유예 기간입니다.
+type GracePeriod struct {
+	Status  GraceStatus
+	EndDate time.Time
+
+	// 유예 기간 안에 도메인이 삭제되면 취소되는 등록 기간(월)입니다.
+	Months int
+}
+
+// 도메인 수명 주기 정책입니다. 기간이 0 이면 해당 유예 기간이나 단계를 사용하지 않습니다.
+type LifecyclePolicy struct {
+	// 만료된 도메인을 1년씩 자동으로 갱신합니다. 설정되지 않으면 만료된 도메인은
+	// 삭제된 도메인과 같이 복구 기간에 들어갑니다.
+	AutoRenew bool
+
+	// 생성, 갱신, 자동 갱신, 이전 후에 도메인을 삭제하면 해당 등록 기간이 취소되는
+	// 기간입니다. 생성 유예 기간 안에 삭제된 도메인은 복구 기간 없이 바로 삭제됩니다.
+	AddGracePeriod       time.Duration
+	RenewGracePeriod     time.Duration
+	AutoRenewGracePeriod time.Duration
+	TransferGracePeriod  time.Duration
+
+	// 삭제된 도메인이 pendingDelete 상태로 복구를 기다리는 기간입니다.
+	RedemptionPeriod time.Duration
+
+	// 복구 요청 후 복구 보고서를 기다리는 기간입니다. 0 이면 복구 요청만으로 복구됩니다.
+	PendingRestorePeriod time.Duration
+
+	// 복구 기간이 끝난 후 도메인이 저장소에서 삭제되어 이름이 해제되기까지의 기간입니다.
+	PendingDeletePeriod time.Duration
+}
+
+// 일반적인 gTLD 레지스트리의 수명 주기 정책을 반환합니다.
+func DefaultLifecyclePolicy() LifecyclePolicy {
+	day := 24 * time.Hour
+
+	return LifecyclePolicy{
+		AutoRenew:            true,
+		AddGracePeriod:       5 * day,
+		RenewGracePeriod:     5 * day,
+		AutoRenewGracePeriod: 45 * day,
+		TransferGracePeriod:  5 * day,
+		RedemptionPeriod:     30 * day,
+		PendingRestorePeriod: defaultPendingRestorePeriod,
+		PendingDeletePeriod:  5 * day,
+	}
+}
+
+// 수명 주기 정책에 따라 이전 요청, 만료된 도메인과 삭제중인 도메인을 처리합니다.
+// 시간은 Registry.Now 를 사용하므로 테스트에서 시간을 옮겨서 실행할 수 있습니다.
+func (r *Registry) ProcessLifecycle() error {
+	if err := r.ProcessTransfers(); err != nil {
+		return err
+	}
+
+	names, err := r.Repository.DomainsExpiring(r.now())
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := r.lifecycleTransaction(name, r.expireDomain); err != nil {
+			return errors.Wrapf(err, "could not expire domain %s", name)
+		}
+	}
+
+	names, err = r.Repository.DomainsByStatus(types.DomainStatusPendingDelete)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := r.lifecycleTransaction(name, r.advanceDeletion); err != nil {
+			return errors.Wrapf(err, "could not process deletion of domain %s", name)
+		}
+	}
+
+	return nil
+}
+
+// interval 마다 ProcessLifecycle 을 실행합니다. stop 채널이 닫힐 때까지 실행됩니다.
+func (r *Registry) RunLifecycle(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := r.ProcessLifecycle(); err != nil {
+				log.Printf("could not process domain lifecycle: %s", err.Error())
+			}
+		}
+	}
+}
+
+// 하나의 도메인을 트랜잭션 안에서 처리하고 트랜잭션이 완료되면 메시지를 보냅니다.
+// 처리하는 동안 도메인이 삭제되었으면 아무것도 하지 않습니다.
+func (r *Registry) lifecycleTransaction(name string, fn func(tx Repository, d *Domain) ([]notice, error)) error {
+	var notices []notice
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		d, err := tx.Domain(name)
+		if err != nil {
+			if errors.Cause(err) == ErrObjectNotFound {
+				return nil
+			}
+
+			return err
+		}
+
+		notices, err = fn(tx, d)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	r.sendNotices(notices)
+
+	return nil
+}
+
+// 만료된 도메인을 자동으로 갱신하거나 복구 기간에 들어가게 합니다.
+func (r *Registry) expireDomain(tx Repository, d *Domain) ([]notice, error) {
+	now := r.now()
+
+	if d.ExpireDate.After(now) || d.hasStatus(types.DomainStatusPendingDelete) {
+		return nil, nil
+	}
+
+	if !r.Lifecycle.AutoRenew || d.hasStatus(types.DomainStatusClientRenewProhibited, types.DomainStatusServerRenewProhibited) {
+		if err := r.enterRedemption(tx, d); err != nil {
+			return nil, err
+		}
+
+		n, err := r.domainChangeNotice(
+			tx, d, fmt.Sprintf("Domain %s expired and will be deleted.", d.Name),
+			r.changeData(Change{Operation: types.ChangePollOperationAutoDelete}), types.ChangePollStateAfter,
+		)
+
+		return []notice{n}, err
+	}
+
+	for !d.ExpireDate.After(now) {
+		r.addGracePeriod(d, GraceAutoRenew, d.ExpireDate, r.Lifecycle.AutoRenewGracePeriod, 12)
+		d.ExpireDate = d.ExpireDate.AddDate(1, 0, 0)
+	}
+
+	if err := tx.UpdateDomain(d); err != nil {
+		return nil, err
+	}
+
+	n, err := r.domainChangeNotice(
+		tx, d, fmt.Sprintf("Domain %s was renewed automatically until %s.", d.Name, d.ExpireDate.Format("2006-01-02")),
+		r.changeData(Change{Operation: types.ChangePollOperationAutoRenew}), types.ChangePollStateAfter,
+	)
+
+	return []notice{n}, err
+}
+
+// 복구 기간이 끝난 도메인을 pendingDelete 단계로 옮기고, 단계가 끝난 도메인은
+// 저장소에서 삭제합니다.
+func (r *Registry) advanceDeletion(tx Repository, d *Domain) ([]notice, error) {
+	now := r.now()
+	notices := []notice{}
+
+	// 복구 보고서를 받지 못한 도메인은 복구 기간으로 돌아갑니다.
+	if g := d.gracePeriod(GracePendingRestore); g != nil {
+		if now.Before(g.EndDate) {
+			return nil, nil
+		}
+
+		d.removeGracePeriod(GracePendingRestore)
+		notices = append(notices, notice{
+			clientID: d.ClientID,
+			message:  fmt.Sprintf("Restore report for domain %s was not received.", d.Name),
+		})
+	}
+
+	if g := d.gracePeriod(GraceRedemption); g != nil {
+		if now.Before(g.EndDate) {
+			if len(notices) == 0 {
+				return nil, nil
+			}
+
+			return notices, tx.UpdateDomain(d)
+		}
+
+		d.GracePeriods = []GracePeriod{{
+			Status:  GracePendingDelete,
+			EndDate: g.EndDate.Add(r.Lifecycle.PendingDeletePeriod),
+		}}
+
+		notices = append(notices, notice{
+			clientID: d.ClientID,
+			message:  fmt.Sprintf("Redemption period for domain %s ended.", d.Name),
+		})
+	}
+
+	g := d.gracePeriod(GracePendingDelete)
+	if g == nil {
+		// 다른 단계(예: 복구 요청)에 있는 도메인은 처리하지 않습니다.
+		return notices, nil
+	}
+
+	if now.Before(g.EndDate) {
+		if len(notices) == 0 {
+			return nil, nil
+		}
+
+		return notices, tx.UpdateDomain(d)
+	}
+
+	// 삭제된 도메인의 정보는 삭제하기 전에 만들어야 합니다.
+	n, err := r.domainChangeNotice(
+		tx, d, fmt.Sprintf("Domain %s was deleted.", d.Name),
+		r.changeData(Change{Operation: types.ChangePollOperationAutoPurge}), types.ChangePollStateBefore,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := purgeDomain(tx, d); err != nil {
+		return nil, err
+	}
+
+	return append(notices, n), nil
+}
+
+// 도메인을 삭제합니다. 생성 유예 기간 안이거나 복구 기간을 사용하지 않으면 바로
+// 삭제하고, 아니면 유예 기간의 등록 기간을 취소한 후 복구 기간에 들어갑니다. 도메인이
+// 복구 기간에 들어가면 true 를 반환합니다.
+func (r *Registry) removeDomain(tx Repository, d *Domain) (bool, error) {
+	r.pruneGracePeriods(d)
+
+	if d.gracePeriod(GraceAdd) != nil || (r.Lifecycle.RedemptionPeriod <= 0 && r.Lifecycle.PendingDeletePeriod <= 0) {
+		return false, tx.DeleteDomain(d.Name)
+	}
+
+	for _, g := range d.GracePeriods {
+		d.ExpireDate = d.ExpireDate.AddDate(0, -g.Months, 0)
+	}
+
+	return true, r.enterRedemption(tx, d)
+}
+
+// 도메인을 pendingDelete 상태로 복구 기간에 들어가게 합니다.
+func (r *Registry) enterRedemption(tx Repository, d *Domain) error {
+	now := r.now()
+
+	d.DeleteDate = &now
+	d.Status = append(d.Status, types.DomainStatusPendingDelete)
+	d.GracePeriods = []GracePeriod{{
+		Status:  GraceRedemption,
+		EndDate: now.Add(r.Lifecycle.RedemptionPeriod),
+	}}
+
+	return tx.UpdateDomain(d)
+}
+
+// 유예 기간을 추가합니다. 기간이 0 이면 추가하지 않습니다.
+func (r *Registry) addGracePeriod(d *Domain, status GraceStatus, start time.Time, period time.Duration, months int) {
+	r.pruneGracePeriods(d)
+
+	if period <= 0 {
+		return
+	}
+
+	d.GracePeriods = append(d.GracePeriods, GracePeriod{
+		Status:  status,
+		EndDate: start.Add(period),
+		Months:  months,
+	})
+}
+
+// 끝난 유예 기간을 제거합니다. 복구 기간과 pendingDelete 단계는 엔진에서 처리하므로
+// 제거하지 않습니다.
+func (r *Registry) pruneGracePeriods(d *Domain) {
+	now := r.now()
+	periods := []GracePeriod{}
+
+	for _, g := range d.GracePeriods {
+		switch g.Status {
+		case GraceAdd, GraceRenew, GraceAutoRenew, GraceTransfer:
+			if !now.Before(g.EndDate) {
+				continue
+			}
+		}
+
+		periods = append(periods, g)
+	}
+
+	d.GracePeriods = periods
+}
+
+// 도메인의 수명 주기 날짜를 iis-1.2 확장의 infData 로 반환합니다. 삭제되지 않은
+// 도메인은 자동으로 갱신되지 않는 경우에만 만료 후에 예정된 날짜를 반환합니다.
+func (r *Registry) IISInfoData(d *Domain) types.IISExtensionInfoData {
+	info := types.IISExtensionInfoData{State: "active"}
+
+	var deactDate, delDate, relDate time.Time
+
+	switch {
+	case d.gracePeriod(GraceRedemption) != nil:
+		info.State = "deactivated"
+		delDate = d.gracePeriod(GraceRedemption).EndDate
+		relDate = delDate.Add(r.Lifecycle.PendingDeletePeriod)
+	case d.gracePeriod(GracePendingDelete) != nil:
+		info.State = "deleted"
+		relDate = d.gracePeriod(GracePendingDelete).EndDate
+		delDate = relDate.Add(-r.Lifecycle.PendingDeletePeriod)
+	case !r.Lifecycle.AutoRenew:
+		deactDate = d.ExpireDate
+		delDate = deactDate.Add(r.Lifecycle.RedemptionPeriod)
+		relDate = delDate.Add(r.Lifecycle.PendingDeletePeriod)
+	default:
+		return info
+	}
+
+	if d.DeleteDate != nil {
+		deactDate = *d.DeleteDate
+	}
+
+	info.DeactivationDate = &deactDate
+	info.DeleteDate = &delDate
+	info.ReleaseDate = &relDate
+
+	return info
+}
+
+// 도메인과 종속된 호스트를 저장소에서 삭제합니다. 종속된 호스트를 네임서버로 사용하는
+// 다른 도메인에서는 호스트가 제거됩니다.
+func purgeDomain(tx Repository, d *Domain) error {
+	subordinates, err := tx.HostsBySuperordinate(d.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, host := range subordinates {
+		names, err := tx.DomainsByHost(host)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if name == d.Name {
+				continue
+			}
+
+			linked, err := tx.Domain(name)
+			if err != nil {
+				return err
+			}
+
+			if i := indexOf(linked.Hosts, host); i >= 0 {
+				linked.Hosts = append(linked.Hosts[:i:i], linked.Hosts[i+1:]...)
+			}
+
+			if err := tx.UpdateDomain(linked); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.DeleteHost(host); err != nil {
+			return err
+		}
+	}
+
+	return tx.DeleteDomain(d.Name)
+}
+
+func (d *Domain) gracePeriod(status GraceStatus) *GracePeriod {
+	for i := range d.GracePeriods {
+		if d.GracePeriods[i].Status == status {
+			return &d.GracePeriods[i]
+		}
+	}
+
+	return nil
+}
+
+// 주어진 상태의 유예 기간을 제거합니다.
+func (d *Domain) removeGracePeriod(status GraceStatus) {
+	periods := []GracePeriod{}
+
+	for _, g := range d.GracePeriods {
+		if g.Status != status {
+			periods = append(periods, g)
+		}
+	}
+
+	d.GracePeriods = periods
+}
+
+// 두 날짜 사이의 개월 수를 반환합니다.
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
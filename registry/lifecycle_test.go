@@ -0,0 +1,187 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func domainDelete(name string) types.DomainDeleteType {
+	return types.DomainDeleteType{Delete: types.DomainDelete{Name: name}}
+}
+
+func (tr *testRegistry) domain(name string) *Domain {
+	tr.t.Helper()
+
+	d, err := tr.registry.Repository.Domain(name)
+	require.Nil(tr.t, err)
+
+	return d
+}
+
+func testRegistryDomainExpiry(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	day := 24 * time.Hour
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.se", "192.0.2.1"))
+	tr.send(s, epp.EppOk, domainCreate("other.se", "ns1.example.se"))
+	tr.send(s, epp.EppOk, domainCreate("plain.se"))
+
+	expireDate := tr.now.AddDate(2, 0, 0)
+	assert.Equal(t, GraceAdd, tr.domain("example.se").GracePeriods[0].Status)
+
+	// Expired domains are renewed automatically and get an auto renew grace period.
+	tr.now = expireDate.Add(time.Hour)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	d := tr.domain("example.se")
+	assert.True(t, expireDate.AddDate(1, 0, 0).Equal(d.ExpireDate))
+	assert.Equal(t, []GracePeriod{{Status: GraceAutoRenew, EndDate: expireDate.Add(45 * day), Months: 12}}, d.GracePeriods)
+	assert.Equal(t, 3, tr.messageCount("ClientX"))
+
+	// Running the engine again does nothing.
+	require.Nil(t, tr.registry.ProcessLifecycle())
+	assert.Equal(t, 3, tr.messageCount("ClientX"))
+
+	// Deleting a domain in the auto renew grace period reverts the renewal and
+	// starts the redemption period.
+	tr.send(s, epp.EppOkPending, domainDelete("plain.se"))
+
+	deleteDate := tr.now
+	d = tr.domain("plain.se")
+
+	assert.True(t, expireDate.Equal(d.ExpireDate))
+	assert.True(t, deleteDate.Equal(*d.DeleteDate))
+	assert.True(t, d.hasStatus(types.DomainStatusPendingDelete))
+	assert.Equal(t, []GracePeriod{{Status: GraceRedemption, EndDate: deleteDate.Add(30 * day)}}, d.GracePeriods)
+
+	info := tr.registry.IISInfoData(d)
+	assert.Equal(t, "deactivated", info.State)
+	assert.True(t, deleteDate.Equal(*info.DeactivationDate))
+	assert.True(t, deleteDate.Add(30*day).Equal(*info.DeleteDate))
+	assert.True(t, deleteDate.Add(35*day).Equal(*info.ReleaseDate))
+
+	tr.send(s, epp.EppStatusProhibitsOp, domainDelete("plain.se"))
+
+	// Info responses include the dates from the iis-1.2 extension.
+	response := tr.send(s, epp.EppOk, types.DomainInfoType{
+		Info: types.DomainInfo{Name: types.DomainInfoName{Name: "plain.se"}},
+	})
+
+	extension := types.IISExtensionInfoDataType{}
+	require.Nil(t, epp.Decode(response, &types.Response{Extension: &extension}))
+	assert.Equal(t, "deactivated", extension.InfoData.State)
+	assert.True(t, deleteDate.Add(35*day).Equal(*extension.InfoData.ReleaseDate))
+
+	// The domain stays in pending delete when the redemption period ends.
+	tr.now = deleteDate.Add(30 * day)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	d = tr.domain("plain.se")
+	assert.Equal(t, []GracePeriod{{Status: GracePendingDelete, EndDate: deleteDate.Add(35 * day)}}, d.GracePeriods)
+	assert.Equal(t, "deleted", tr.registry.IISInfoData(d).State)
+	assert.Equal(t, 4, tr.messageCount("ClientX"))
+
+	tr.now = deleteDate.Add(35 * day)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	_, err := tr.registry.Repository.Domain("plain.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+	assert.Equal(t, 5, tr.messageCount("ClientX"))
+
+	// Without auto renew expired domains are deleted together with their
+	// subordinate hosts.
+	tr.send(s, epp.EppOk, types.DomainRenewType{
+		Renew: types.DomainRenew{
+			Name:       "other.se",
+			ExpireDate: types.Date{Time: expireDate.AddDate(1, 0, 0)},
+			Period:     &types.Period{Value: 1, Unit: "y"},
+		},
+	})
+
+	tr.registry.Lifecycle.AutoRenew = false
+
+	tr.now = expireDate.AddDate(1, 0, 0)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	d = tr.domain("example.se")
+	assert.True(t, d.hasStatus(types.DomainStatusPendingDelete))
+	assert.True(t, tr.now.Equal(*d.DeleteDate))
+	assert.Equal(t, 6, tr.messageCount("ClientX"))
+
+	tr.now = tr.now.Add(35 * day)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	_, err = tr.registry.Repository.Domain("example.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	_, err = tr.registry.Repository.Host("ns1.example.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	assert.Empty(t, tr.domain("other.se").Hosts)
+	assert.Equal(t, "active", tr.registry.IISInfoData(tr.domain("other.se")).State)
+	assert.Equal(t, 8, tr.messageCount("ClientX"))
+}
+
+func testRegistryDomainGracePeriods(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	day := 24 * time.Hour
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+
+	expireDate := tr.now.AddDate(2, 0, 0)
+
+	// Deleting a renewed domain in the renew grace period reverts the renewal.
+	tr.now = tr.now.Add(6 * day)
+	tr.send(s, epp.EppOk, types.DomainRenewType{
+		Renew: types.DomainRenew{
+			Name:       "example.se",
+			ExpireDate: types.Date{Time: expireDate},
+			Period:     &types.Period{Value: 1, Unit: "y"},
+		},
+	})
+
+	assert.Equal(t, []GracePeriod{{Status: GraceRenew, EndDate: tr.now.Add(5 * day), Months: 12}}, tr.domain("example.se").GracePeriods)
+
+	tr.send(s, epp.EppOkPending, domainDelete("example.se"))
+	assert.True(t, expireDate.Equal(tr.domain("example.se").ExpireDate))
+
+	// Without auto renew expired domains enter the redemption period.
+	tr.registry.Lifecycle.AutoRenew = false
+
+	tr.send(s, epp.EppOk, domainCreate("expired.se"))
+
+	info := tr.registry.IISInfoData(tr.domain("expired.se"))
+	assert.Equal(t, "active", info.State)
+	assert.True(t, tr.now.AddDate(2, 0, 0).Equal(*info.DeactivationDate))
+
+	tr.now = tr.now.AddDate(2, 0, 0)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	d := tr.domain("expired.se")
+	assert.True(t, d.hasStatus(types.DomainStatusPendingDelete))
+	assert.Equal(t, GraceRedemption, d.GracePeriods[0].Status)
+
+	// The deleted example.se was purged in the same run.
+	m, count, err := tr.registry.Poll.Oldest("ClientX")
+	require.Nil(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, "Domain expired.se expired and will be deleted.", m.Message)
+
+	_, err = tr.registry.Repository.Domain("example.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	// Without redemption and pending delete periods domains are deleted at once.
+	tr.registry.Lifecycle = LifecyclePolicy{}
+
+	tr.send(s, epp.EppOk, domainCreate("direct.se"))
+	tr.send(s, epp.EppOk, domainDelete("direct.se"))
+	tr.send(s, epp.EppObjectDoesNotExist, domainDelete("direct.se"))
+}
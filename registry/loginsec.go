@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"crypto/tls"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+)
+
+// 만료일이 가까워졌다고 경고하는 기본 기간입니다.
+const defaultExpiryWarning = 14 * 24 * time.Hour
+
+// 경고를 보내는 TLS 버전과 이벤트에 사용되는 이름입니다. TLS 1.2 미만은 안전하지 않은
+// 것으로 처리합니다.
+var insecureTLSVersions = map[uint16]string{
+	tls.VersionTLS10: "TLSv1.0",
+	tls.VersionTLS11: "TLSv1.1",
+}
+
+// 로그인 보안 정책입니다. (RFC 8807) Registry.LoginSecurity 가 nil 이면
+// loginSec-1.0 확장을 지원하지 않습니다.
+//
+// 로그인 명령어의 pw 와 newPW 가 [LOGIN-SECURITY] 이면 확장의 비밀번호를 사용하므로
+// 16자보다 긴 비밀번호를 사용할 수 있습니다. 클라이언트가 svcExtension 에 확장을
+// 포함하면 비밀번호와 인증서의 만료, 안전하지 않은 암호화 방식과 TLS 버전을 보안
+// 이벤트로 응답합니다.
+type LoginSecurity struct {
+	// 클라이언트 비밀번호의 만료일을 반환합니다. nil 이거나 0 을 반환하면 비밀번호는
+	// 만료되지 않습니다. 만료된 비밀번호로는 newPW 없이 로그인할 수 없습니다.
+	PasswordExpiry func(clientID string) (time.Time, error)
+
+	// 클라이언트의 비밀번호를 바꿉니다. nil 이면 newPW 를 지원하지 않습니다. 새로운
+	// 비밀번호가 정책에 맞지 않으면 오류를 반환하며, 오류는 newPW 이벤트로 전달됩니다.
+	ChangePassword func(clientID, password string) error
+
+	// 비밀번호와 인증서의 만료일이 이 기간 안에 있으면 경고합니다. 0 이면 14일입니다.
+	ExpiryWarning time.Duration
+}
+
+// loginSec-1.0 확장을 사용하면 확장의 비밀번호로 로그인 명령어의 비밀번호를 바꿉니다.
+func (r *Registry) loginPasswords(login *types.Login, data []byte) error {
+	if login.Password != types.LoginSecPassword && login.NewPassword != types.LoginSecPassword {
+		return nil
+	}
+
+	if r.LoginSecurity == nil {
+		return errorf(epp.EppUnimplementedExtension, "the loginSec extension is not supported")
+	}
+
+	ext := types.LoginSecExtensionType{}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return err
+	}
+
+	if login.Password == types.LoginSecPassword {
+		if ext.LoginSec.Password == "" {
+			return errorf(epp.EppMissingParam, "pw must be set in the loginSec extension")
+		}
+
+		login.Password = ext.LoginSec.Password
+	}
+
+	if login.NewPassword == types.LoginSecPassword {
+		if ext.LoginSec.NewPassword == "" {
+			return errorf(epp.EppMissingParam, "newPW must be set in the loginSec extension")
+		}
+
+		login.NewPassword = ext.LoginSec.NewPassword
+	}
+
+	return nil
+}
+
+// 인증된 클라이언트의 비밀번호를 확인하고 바꾼 후 보안 이벤트를 반환합니다. 로그인이
+// 거부되어야 하면 error 수준의 이벤트와 false 를 반환합니다.
+func (r *Registry) loginSecurityEvents(s *epp.Session, login types.Login) ([]types.LoginSecEvent, bool, error) {
+	policy := r.LoginSecurity
+	if policy == nil {
+		return nil, true, nil
+	}
+
+	warning := policy.ExpiryWarning
+	if warning <= 0 {
+		warning = defaultExpiryWarning
+	}
+
+	now := r.now()
+	events := []types.LoginSecEvent{}
+
+	if login.NewPassword != "" {
+		if policy.ChangePassword == nil {
+			return nil, false, errorf(epp.EppUnimplementedOption, "password changes are not supported")
+		}
+
+		if err := policy.ChangePassword(login.ClientID, login.NewPassword); err != nil {
+			return []types.LoginSecEvent{{
+				Type:    types.LoginSecEventNewPassword,
+				Level:   types.LoginSecLevelError,
+				Message: err.Error(),
+			}}, false, nil
+		}
+	} else if policy.PasswordExpiry != nil {
+		expireDate, err := policy.PasswordExpiry(login.ClientID)
+		if err != nil {
+			return nil, false, err
+		}
+
+		switch {
+		case expireDate.IsZero():
+		case !now.Before(expireDate):
+			return []types.LoginSecEvent{
+				expiryEvent(types.LoginSecEventPassword, types.LoginSecLevelError, expireDate, "Password has expired"),
+			}, false, nil
+		case now.Add(warning).After(expireDate):
+			events = append(events, expiryEvent(types.LoginSecEventPassword, types.LoginSecLevelWarning, expireDate, "Password expiring soon"))
+		}
+	}
+
+	if s.ConnectionState == nil {
+		return events, true, nil
+	}
+
+	state := s.ConnectionState()
+
+	if len(state.PeerCertificates) > 0 {
+		expireDate := state.PeerCertificates[0].NotAfter.UTC()
+
+		if now.Add(warning).After(expireDate) {
+			events = append(events, expiryEvent(types.LoginSecEventCertificate, types.LoginSecLevelWarning, expireDate, "Certificate expiring soon"))
+		}
+	}
+
+	for _, suite := range tls.InsecureCipherSuites() {
+		if suite.ID == state.CipherSuite {
+			events = append(events, types.LoginSecEvent{
+				Type:    types.LoginSecEventCipher,
+				Level:   types.LoginSecLevelWarning,
+				Value:   suite.Name,
+				Message: "Insecure cipher suite",
+			})
+		}
+	}
+
+	if name, ok := insecureTLSVersions[state.Version]; ok {
+		events = append(events, types.LoginSecEvent{
+			Type:    types.LoginSecEventTLSProtocol,
+			Level:   types.LoginSecLevelWarning,
+			Value:   name,
+			Message: "Insecure TLS protocol",
+		})
+	}
+
+	return events, true, nil
+}
+
+func expiryEvent(eventType types.LoginSecEventType, level types.LoginSecLevelType, expireDate time.Time, message string) types.LoginSecEvent {
+	return types.LoginSecEvent{
+		Type:       eventType,
+		Level:      level,
+		ExpireDate: &expireDate,
+		Message:    message,
+	}
+}
+
+// 클라이언트가 svcExtension 에 loginSec-1.0 을 포함했는지 확인합니다.
+func usesLoginSecurity(login types.Login) bool {
+	if login.Services.ServiceExtension == nil {
+		return false
+	}
+
+	return indexOf(login.Services.ServiceExtension.ExtensionURI, types.NameSpaceLoginSec10) >= 0
+}
@@ -0,0 +1,140 @@
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loginSec-1.0 확장을 사용하는 로그인 명령어를 생성합니다.
+func loginSec(clientID, password, newPassword string) (types.Login, types.LoginSecExtensionType) {
+	login := types.Login{
+		ClientID: clientID,
+		Password: types.LoginSecPassword,
+		Options:  types.LoginOptions{Version: "1.0", Language: "en"},
+		Services: types.LoginServices{
+			ObjectURI:        []string{types.NameSpaceDomain},
+			ServiceExtension: &types.LoginServiceExtension{ExtensionURI: []string{types.NameSpaceLoginSec10}},
+		},
+	}
+
+	ext := types.LoginSecExtensionType{
+		LoginSec: types.LoginSec{
+			UserAgent: &types.LoginSecUserAgent{Application: "epp-go"},
+			Password:  password,
+		},
+	}
+
+	if newPassword != "" {
+		login.NewPassword = types.LoginSecPassword
+		ext.LoginSec.NewPassword = newPassword
+	}
+
+	return login, ext
+}
+
+// 응답의 extension 에서 보안 이벤트를 반환합니다.
+func loginSecEvents(t *testing.T, response []byte) []types.LoginSecEvent {
+	ext := types.LoginSecExtensionDataType{}
+	require.Nil(t, epp.Decode(response, &types.Response{Extension: &ext}))
+
+	return ext.LoginSecData.Event
+}
+
+func testRegistryLoginSecurity(t *testing.T, tr *testRegistry) {
+	day := 24 * time.Hour
+	passwords := map[string]string{"ClientX": "a password longer than sixteen characters"}
+	expiry := map[string]time.Time{"ClientX": tr.now.Add(7 * day)}
+
+	tr.registry.Authenticate = func(clientID, password string) bool {
+		return passwords[clientID] == password
+	}
+
+	login, ext := loginSec("ClientX", passwords["ClientX"], "")
+
+	// Without a policy the extension is not supported.
+	tr.send(&epp.Session{}, epp.EppUnimplementedExtension, login, ext)
+
+	tr.registry.LoginSecurity = &LoginSecurity{
+		PasswordExpiry: func(clientID string) (time.Time, error) {
+			return expiry[clientID], nil
+		},
+		ChangePassword: func(clientID, password string) error {
+			if len(password) < 16 {
+				return errors.New("password must be at least 16 characters")
+			}
+
+			passwords[clientID] = password
+			expiry[clientID] = tr.now.AddDate(1, 0, 0)
+
+			return nil
+		},
+	}
+
+	greeting, err := tr.registry.Greeting(&epp.Session{})
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceLoginSec10)
+
+	s := &epp.Session{
+		ConnectionState: func() tls.ConnectionState {
+			return tls.ConnectionState{
+				Version:          tls.VersionTLS10,
+				CipherSuite:      tls.TLS_RSA_WITH_RC4_128_SHA,
+				PeerCertificates: []*x509.Certificate{{NotAfter: tr.now.Add(10 * day)}},
+			}
+		},
+	}
+
+	wrong, wrongExt := loginSec("ClientX", "not the password", "")
+	tr.send(s, epp.EppAuthenticationError, wrong, wrongExt)
+
+	// Security events are returned for the password, certificate, cipher and
+	// TLS version.
+	events := loginSecEvents(t, tr.send(s, epp.EppOk, login, ext))
+	require.Len(t, events, 4)
+	assert.Equal(t, types.LoginSecEventPassword, events[0].Type)
+	assert.Equal(t, types.LoginSecLevelWarning, events[0].Level)
+	assert.True(t, expiry["ClientX"].Equal(*events[0].ExpireDate))
+	assert.Equal(t, types.LoginSecEventCertificate, events[1].Type)
+	assert.True(t, tr.now.Add(10*day).Equal(*events[1].ExpireDate))
+	assert.Equal(t, types.LoginSecEventCipher, events[2].Type)
+	assert.Equal(t, "TLS_RSA_WITH_RC4_128_SHA", events[2].Value)
+	assert.Equal(t, types.LoginSecEventTLSProtocol, events[3].Type)
+	assert.Equal(t, "TLSv1.0", events[3].Value)
+	assert.Equal(t, "ClientX", s.ClientID)
+
+	// An expired password must be changed to log in.
+	tr.now = tr.now.Add(7 * day)
+	s = &epp.Session{}
+
+	events = loginSecEvents(t, tr.send(s, epp.EppAuthenticationError, login, ext))
+	require.Len(t, events, 1)
+	assert.Equal(t, types.LoginSecLevelError, events[0].Level)
+	assert.Empty(t, s.ClientID)
+
+	login, ext = loginSec("ClientX", passwords["ClientX"], "shortpass")
+
+	events = loginSecEvents(t, tr.send(s, epp.EppAuthenticationError, login, ext))
+	require.Len(t, events, 1)
+	assert.Equal(t, types.LoginSecEventNewPassword, events[0].Type)
+
+	login, ext = loginSec("ClientX", passwords["ClientX"], "another password that is long")
+
+	response := tr.send(s, epp.EppOk, login, ext)
+	assert.Empty(t, loginSecEvents(t, response))
+	assert.Equal(t, "another password that is long", passwords["ClientX"])
+
+	// Events are only returned to clients using the extension.
+	login, ext = loginSec("ClientX", passwords["ClientX"], "")
+	login.Services.ServiceExtension = nil
+	expiry["ClientX"] = tr.now.Add(day)
+
+	assert.Empty(t, loginSecEvents(t, tr.send(&epp.Session{}, epp.EppOk, login, ext)))
+}
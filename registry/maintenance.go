@@ -0,0 +1,264 @@
+package registry
+
+import (
+	"fmt"
+	"sort"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// 레지스트리 점검 알림 정책입니다. (RFC 9167) 점검 일정은 저장소에 저장되고
+// 클라이언트는 maintenance-1.0 의 info 명령어로 일정을 조회합니다. 일정이 추가, 변경,
+// 취소되면 모든 클라이언트에게 서비스 메시지를 보냅니다.
+type Maintenance struct {
+	// 점검 일정을 저장하는 저장소입니다.
+	Store MaintenanceStore
+
+	// 점검 알림을 받는 클라이언트(등록 대행자)의 ID 입니다.
+	ClientIDs []string
+}
+
+// 점검 일정을 저장하는 저장소입니다.
+type MaintenanceStore interface {
+	// 점검 일정을 반환합니다. 일정이 없으면 ErrObjectNotFound 를 반환합니다.
+	Maintenance(id string) (*types.MaintenanceItem, error)
+
+	// 모든 점검 일정을 시작 시간 순서로 반환합니다.
+	Maintenances() ([]types.MaintenanceItem, error)
+
+	// 점검 일정을 저장합니다. 같은 ID 의 일정이 있으면 바꿉니다.
+	SaveMaintenance(m *types.MaintenanceItem) error
+
+	// 점검 일정을 삭제합니다. 일정이 없으면 ErrObjectNotFound 를 반환합니다.
+	DeleteMaintenance(id string) error
+}
+
+// 점검 일정을 추가하고 모든 클라이언트에게 알립니다. ID 가 비어있으면 새로운 ID 를
+// 생성하며, 추가된 일정의 ID 를 반환합니다.
+func (r *Registry) ScheduleMaintenance(m types.MaintenanceItem) (string, error) {
+	store, err := r.maintenanceStore()
+	if err != nil {
+		return "", err
+	}
+
+	if m.ID.ID == "" {
+		m.ID.ID = uuid.New().String()
+	}
+
+	if _, err := store.Maintenance(m.ID.ID); errors.Cause(err) != ErrObjectNotFound {
+		if err != nil {
+			return "", err
+		}
+
+		return "", errors.Errorf("maintenance %s already exists", m.ID.ID)
+	}
+
+	if err := validMaintenance(&m); err != nil {
+		return "", err
+	}
+
+	m.PollType = ""
+	m.CreateDate = r.now()
+	m.UpdateDate = nil
+
+	if err := store.SaveMaintenance(&m); err != nil {
+		return "", err
+	}
+
+	r.notifyMaintenance(m, types.MaintenancePollCreate)
+
+	return m.ID.ID, nil
+}
+
+// 점검 일정을 바꾸고 모든 클라이언트에게 알립니다. fn 이 반환한 오류는 그대로
+// 반환되며 일정은 바뀌지 않습니다.
+func (r *Registry) UpdateMaintenance(id string, fn func(m *types.MaintenanceItem) error) error {
+	store, err := r.maintenanceStore()
+	if err != nil {
+		return err
+	}
+
+	m, err := store.Maintenance(id)
+	if err != nil {
+		return notFound(err, "maintenance %s does not exist", id)
+	}
+
+	createDate := m.CreateDate
+
+	if err := fn(m); err != nil {
+		return err
+	}
+
+	if err := validMaintenance(m); err != nil {
+		return err
+	}
+
+	now := r.now()
+
+	m.ID.ID = id
+	m.PollType = ""
+	m.CreateDate = createDate
+	m.UpdateDate = &now
+
+	if err := store.SaveMaintenance(m); err != nil {
+		return err
+	}
+
+	r.notifyMaintenance(*m, types.MaintenancePollUpdate)
+
+	return nil
+}
+
+// 점검 일정을 취소하고 모든 클라이언트에게 알립니다.
+func (r *Registry) CancelMaintenance(id string) error {
+	store, err := r.maintenanceStore()
+	if err != nil {
+		return err
+	}
+
+	m, err := store.Maintenance(id)
+	if err != nil {
+		return notFound(err, "maintenance %s does not exist", id)
+	}
+
+	if err := store.DeleteMaintenance(id); err != nil {
+		return err
+	}
+
+	now := r.now()
+	m.UpdateDate = &now
+
+	r.notifyMaintenance(*m, types.MaintenancePollDelete)
+
+	return nil
+}
+
+// 점검 일정을 모든 클라이언트에게 다시 알립니다. 점검이 시작되기 전의 안내(courtesy)나
+// 점검이 끝났다는 알림(end)을 보낼 때 사용합니다.
+func (r *Registry) NotifyMaintenance(id string, pollType types.MaintenancePollType) error {
+	store, err := r.maintenanceStore()
+	if err != nil {
+		return err
+	}
+
+	m, err := store.Maintenance(id)
+	if err != nil {
+		return notFound(err, "maintenance %s does not exist", id)
+	}
+
+	r.notifyMaintenance(*m, pollType)
+
+	return nil
+}
+
+func (r *Registry) notifyMaintenance(m types.MaintenanceItem, pollType types.MaintenancePollType) {
+	m.PollType = pollType
+
+	notices := make([]notice, len(r.Maintenance.ClientIDs))
+
+	for i, clientID := range r.Maintenance.ClientIDs {
+		notices[i] = notice{
+			clientID: clientID,
+			message:  fmt.Sprintf("Registry maintenance %s.", maintenanceMessages[pollType]),
+			data: &types.PollResultData{
+				MaintenanceInfoData: &types.MaintenanceInfoData{Item: &m},
+			},
+		}
+	}
+
+	r.sendNotices(notices)
+}
+
+// 서비스 메시지에 사용할 poll type 별 설명입니다.
+var maintenanceMessages = map[types.MaintenancePollType]string{
+	types.MaintenancePollCreate:   "scheduled",
+	types.MaintenancePollUpdate:   "updated",
+	types.MaintenancePollDelete:   "cancelled",
+	types.MaintenancePollCourtesy: "reminder",
+	types.MaintenancePollEnd:      "ended",
+}
+
+func (r *Registry) maintenanceStore() (MaintenanceStore, error) {
+	if r.Maintenance == nil || r.Maintenance.Store == nil {
+		return nil, errorf(epp.EppUnimplementedCommand, "maintenance notifications are not supported")
+	}
+
+	return r.Maintenance.Store, nil
+}
+
+// 점검 일정에 필요한 값이 모두 있는지 확인합니다.
+func validMaintenance(m *types.MaintenanceItem) error {
+	switch {
+	case len(m.Systems.System) == 0:
+		return errors.New("maintenance must affect at least one system")
+	case m.Environment.Type == "":
+		return errors.New("maintenance must have an environment")
+	case m.Reason == "":
+		return errors.New("maintenance must have a reason")
+	case !m.End.After(m.Start):
+		return errors.New("maintenance must end after it starts")
+	}
+
+	return nil
+}
+
+func (r *Registry) infoMaintenance(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.MaintenanceInfoType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	store, err := r.maintenanceStore()
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Info.List != nil {
+		maintenances, err := store.Maintenances()
+		if err != nil {
+			return nil, err
+		}
+
+		list := &types.MaintenanceList{}
+
+		for _, m := range maintenances {
+			list.Items = append(list.Items, types.MaintenanceListItem{
+				ID:         m.ID,
+				Start:      m.Start,
+				End:        m.End,
+				CreateDate: m.CreateDate,
+				UpdateDate: m.UpdateDate,
+			})
+		}
+
+		return epp.NewResponse(epp.EppOk).WithResData(types.MaintenanceInfoDataType{
+			InfoData: types.MaintenanceInfoData{List: list},
+		}), nil
+	}
+
+	m, err := store.Maintenance(cmd.Info.ID)
+	if err != nil {
+		return nil, notFound(err, "maintenance %s does not exist", cmd.Info.ID)
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.MaintenanceInfoDataType{
+		InfoData: types.MaintenanceInfoData{Item: m},
+	}), nil
+}
+
+// 점검 일정을 시작 시간 순서로 정렬합니다. 시작 시간이 같으면 ID 순서로 정렬합니다.
+func sortMaintenances(maintenances []types.MaintenanceItem) {
+	sort.Slice(maintenances, func(i, j int) bool {
+		a, b := maintenances[i], maintenances[j]
+
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+
+		return a.ID.ID < b.ID.ID
+	})
+}
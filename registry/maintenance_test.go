@@ -0,0 +1,145 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 가장 오래된 서비스 메시지를 확인하고 점검 일정을 반환합니다.
+func pollMaintenance(t *testing.T, tr *testRegistry, s *epp.Session) *types.MaintenanceItem {
+	response := tr.send(s, epp.EppOkMessages, pollCommand(types.PollOperationRequest, ""))
+
+	data := types.PollResultData{}
+	result := types.Response{ResultData: &data}
+	require.Nil(t, epp.Decode(response, &result))
+	require.NotNil(t, data.MaintenanceInfoData)
+
+	tr.send(s, epp.EppOk, pollCommand(types.PollOperationAcknowledge, result.MessageQ.ID))
+
+	return data.MaintenanceInfoData.Item
+}
+
+func maintenanceInfo(t *testing.T, tr *testRegistry, s *epp.Session, info types.MaintenanceInfo) types.MaintenanceInfoData {
+	infoData := types.MaintenanceInfoDataType{}
+	decodeResData(t, tr.send(s, epp.EppOk, types.MaintenanceInfoType{Info: info}), &infoData)
+
+	return infoData.InfoData
+}
+
+func testRegistryMaintenance(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	info := types.MaintenanceInfoType{Info: types.MaintenanceInfo{List: types.Empty()}}
+	tr.send(s, epp.EppUnimplementedCommand, info)
+
+	tr.registry.Maintenance = &Maintenance{
+		Store:     NewMemoryMaintenanceStore(),
+		ClientIDs: []string{"ClientX", "ClientY"},
+	}
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceMaintenance10)
+
+	start := tr.now.AddDate(0, 0, 7)
+	item := types.MaintenanceItem{
+		Type: []types.MaintenanceText{{Value: "Routine Maintenance"}},
+		Systems: types.MaintenanceSystems{
+			System: []types.MaintenanceSystem{
+				{Name: "EPP", Host: "epp.example.se", Impact: types.MaintenanceImpactFull},
+			},
+		},
+		Environment: types.MaintenanceEnvironment{Type: types.MaintenanceEnvironmentProduction},
+		Start:       start,
+		End:         start,
+		Reason:      types.MaintenanceReasonPlanned,
+	}
+
+	_, err = tr.registry.ScheduleMaintenance(item)
+	assert.NotNil(t, err)
+
+	// Scheduled maintenances are sent to all clients.
+	item.End = start.Add(2 * time.Hour)
+
+	id, err := tr.registry.ScheduleMaintenance(item)
+	require.Nil(t, err)
+	assert.NotEmpty(t, id)
+
+	for _, session := range []*epp.Session{s, other} {
+		polled := pollMaintenance(t, tr, session)
+		assert.Equal(t, id, polled.ID.ID)
+		assert.Equal(t, types.MaintenancePollCreate, polled.PollType)
+		assert.True(t, tr.now.Equal(polled.CreateDate))
+	}
+
+	item.ID.ID = id
+	_, err = tr.registry.ScheduleMaintenance(item)
+	assert.NotNil(t, err)
+
+	// Info returns the maintenance without a poll type.
+	data := maintenanceInfo(t, tr, s, types.MaintenanceInfo{ID: id})
+	require.NotNil(t, data.Item)
+	assert.Equal(t, types.MaintenancePollType(""), data.Item.PollType)
+	assert.Equal(t, "epp.example.se", data.Item.Systems.System[0].Host)
+	assert.True(t, start.Equal(data.Item.Start))
+
+	tr.send(s, epp.EppObjectDoesNotExist, types.MaintenanceInfoType{Info: types.MaintenanceInfo{ID: "unknown"}})
+
+	// Updates set the update date and are sent to all clients.
+	tr.now = tr.now.Add(time.Hour)
+
+	err = tr.registry.UpdateMaintenance(id, func(m *types.MaintenanceItem) error {
+		m.Reason = types.MaintenanceReasonEmergency
+		m.Start, m.End = tr.now, tr.now.Add(time.Hour)
+
+		return nil
+	})
+	require.Nil(t, err)
+
+	for _, session := range []*epp.Session{s, other} {
+		polled := pollMaintenance(t, tr, session)
+		assert.Equal(t, types.MaintenancePollUpdate, polled.PollType)
+		assert.Equal(t, types.MaintenanceReasonEmergency, polled.Reason)
+		assert.True(t, tr.now.Equal(*polled.UpdateDate))
+	}
+
+	// The list is ordered by the start of the maintenances.
+	item.ID.ID = ""
+
+	later, err := tr.registry.ScheduleMaintenance(item)
+	require.Nil(t, err)
+
+	pollMaintenance(t, tr, s)
+	pollMaintenance(t, tr, other)
+
+	data = maintenanceInfo(t, tr, other, types.MaintenanceInfo{List: types.Empty()})
+	require.NotNil(t, data.List)
+	require.Len(t, data.List.Items, 2)
+	assert.Equal(t, id, data.List.Items[0].ID.ID)
+	assert.NotNil(t, data.List.Items[0].UpdateDate)
+	assert.Equal(t, later, data.List.Items[1].ID.ID)
+	assert.Nil(t, data.List.Items[1].UpdateDate)
+
+	// Courtesy and end notifications are sent by the backend.
+	require.Nil(t, tr.registry.NotifyMaintenance(later, types.MaintenancePollCourtesy))
+	assert.Equal(t, types.MaintenancePollCourtesy, pollMaintenance(t, tr, s).PollType)
+	assert.Equal(t, types.MaintenancePollCourtesy, pollMaintenance(t, tr, other).PollType)
+
+	// Cancelled maintenances are removed.
+	require.Nil(t, tr.registry.CancelMaintenance(id))
+	assert.Equal(t, types.MaintenancePollDelete, pollMaintenance(t, tr, s).PollType)
+	assert.Equal(t, types.MaintenancePollDelete, pollMaintenance(t, tr, other).PollType)
+
+	tr.send(s, epp.EppObjectDoesNotExist, types.MaintenanceInfoType{Info: types.MaintenanceInfo{ID: id}})
+	assert.NotNil(t, tr.registry.CancelMaintenance(id))
+
+	data = maintenanceInfo(t, tr, s, types.MaintenanceInfo{List: types.Empty()})
+	require.Len(t, data.List.Items, 1)
+	assert.Equal(t, later, data.List.Items[0].ID.ID)
+}
@@ -0,0 +1,772 @@
+package registry
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/bombsimon/epp-go/types"
+)
+
+// 모든 개체를 메모리에 저장하는 저장소입니다. 통합 테스트나 로컬 개발에 사용할 수 있습니다.
+type MemoryRepository struct {
+	*memoryStore
+
+	// 트랜잭션이 동시에 하나만 실행되도록 보장하기 위한 Mutex 입니다. 트랜잭션 밖에서의
+	// 쓰기도 이 Mutex 를 사용하므로 트랜잭션이 끝날 때까지 기다립니다.
+	txMu *sync.Mutex
+
+	// 트랜잭션 안에서 사용되는 저장소인지 여부입니다.
+	inTx bool
+}
+
+// 저장소의 개체 목록입니다. 트랜잭션 안에서 사용되는 저장소와 공유됩니다.
+type memoryStore struct {
+	domains      map[string]*Domain
+	hosts        map[string]*Host
+	contacts     map[string]*Contact
+	applications map[string]*Application
+	orgs         map[string]*Organization
+	lastID       int64
+
+	// 개체 목록의 읽기, 쓰기 작업에 Thread Safe 접근을 보장하기 위한 Mutex 입니다.
+	mu sync.RWMutex
+}
+
+// 비어있는 새로운 메모리 저장소를 생성합니다.
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{
+		memoryStore: &memoryStore{
+			domains:      map[string]*Domain{},
+			hosts:        map[string]*Host{},
+			contacts:     map[string]*Contact{},
+			applications: map[string]*Application{},
+			orgs:         map[string]*Organization{},
+		},
+		txMu: &sync.Mutex{},
+	}
+}
+
+// 트랜잭션 안에서 함수를 실행합니다. 함수가 오류를 반환하면 트랜잭션 시작 전의
+// 상태로 되돌립니다. 트랜잭션 밖에서의 쓰기는 트랜잭션이 끝난 후에 실행되므로
+// 되돌릴 때 사라지지 않습니다. 이미 트랜잭션 안에 있으면 함수를 바로 실행합니다.
+func (m *MemoryRepository) Transaction(fn func(tx Repository) error) error {
+	if m.inTx {
+		return fn(m)
+	}
+
+	m.txMu.Lock()
+	defer m.txMu.Unlock()
+
+	// 저장된 개체는 항상 복사본으로 교체되므로 맵만 복사하면 됩니다.
+	m.mu.RLock()
+
+	domains := make(map[string]*Domain, len(m.domains))
+	for k, v := range m.domains {
+		domains[k] = v
+	}
+
+	hosts := make(map[string]*Host, len(m.hosts))
+	for k, v := range m.hosts {
+		hosts[k] = v
+	}
+
+	contacts := make(map[string]*Contact, len(m.contacts))
+	for k, v := range m.contacts {
+		contacts[k] = v
+	}
+
+	applications := make(map[string]*Application, len(m.applications))
+	for k, v := range m.applications {
+		applications[k] = v
+	}
+
+	orgs := make(map[string]*Organization, len(m.orgs))
+	for k, v := range m.orgs {
+		orgs[k] = v
+	}
+
+	m.mu.RUnlock()
+
+	tx := &MemoryRepository{memoryStore: m.memoryStore, txMu: m.txMu, inTx: true}
+
+	if err := fn(tx); err != nil {
+		m.mu.Lock()
+		m.domains, m.hosts, m.contacts, m.applications, m.orgs = domains, hosts, contacts, applications, orgs
+		m.mu.Unlock()
+
+		return err
+	}
+
+	return nil
+}
+
+// 개체 목록을 쓰기 위해 잠그고 잠금을 해제하는 함수를 반환합니다. 트랜잭션 밖에서는
+// 실행 중인 트랜잭션이 끝날 때까지 기다립니다.
+func (m *MemoryRepository) lock() func() {
+	if !m.inTx {
+		m.txMu.Lock()
+	}
+
+	m.mu.Lock()
+
+	return func() {
+		m.mu.Unlock()
+
+		if !m.inTx {
+			m.txMu.Unlock()
+		}
+	}
+}
+
+// ROID 를 생성하기 위한 고유한 숫자를 반환합니다. 트랜잭션이 취소되어도 숫자는
+// 다시 사용하지 않습니다.
+func (m *MemoryRepository) NextID() (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.lastID++
+
+	return m.lastID, nil
+}
+
+// 이름으로 도메인의 복사본을 반환합니다. 도메인이 없으면 ErrObjectNotFound 를
+// 반환합니다.
+func (m *MemoryRepository) Domain(name string) (*Domain, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	d, ok := m.domains[name]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	return d.copy(), nil
+}
+
+// 도메인의 복사본을 저장합니다.
+func (m *MemoryRepository) CreateDomain(d *Domain) error {
+	defer m.lock()()
+
+	m.domains[d.Name] = d.copy()
+
+	return nil
+}
+
+// 저장된 도메인을 주어진 도메인의 복사본으로 바꿉니다.
+func (m *MemoryRepository) UpdateDomain(d *Domain) error {
+	defer m.lock()()
+
+	if _, ok := m.domains[d.Name]; !ok {
+		return ErrObjectNotFound
+	}
+
+	m.domains[d.Name] = d.copy()
+
+	return nil
+}
+
+// 이름으로 도메인을 삭제합니다.
+func (m *MemoryRepository) DeleteDomain(name string) error {
+	defer m.lock()()
+
+	if _, ok := m.domains[name]; !ok {
+		return ErrObjectNotFound
+	}
+
+	delete(m.domains, name)
+
+	return nil
+}
+
+// 주어진 호스트를 네임서버로 사용하는 도메인 이름들을 정렬하여 반환합니다.
+func (m *MemoryRepository) DomainsByHost(name string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := []string{}
+
+	for _, d := range m.domains {
+		for _, h := range d.Hosts {
+			if h == name {
+				names = append(names, d.Name)
+
+				break
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// 주어진 상태를 가진 도메인 이름들을 정렬하여 반환합니다.
+func (m *MemoryRepository) DomainsByStatus(status types.DomainStatusType) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := []string{}
+
+	for _, d := range m.domains {
+		if d.hasStatus(status) {
+			names = append(names, d.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// 만료일이 주어진 시간과 같거나 이전인 도메인 이름들을 정렬하여 반환합니다.
+func (m *MemoryRepository) DomainsExpiring(before time.Time) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := []string{}
+
+	for _, d := range m.domains {
+		if !d.ExpireDate.After(before) {
+			names = append(names, d.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// 주어진 연락처를 등록자 또는 연락처로 사용하는 도메인 이름들을 정렬하여
+// 반환합니다.
+func (m *MemoryRepository) DomainsByContact(id string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := []string{}
+
+	for _, d := range m.domains {
+		linked := d.Registrant == id
+
+		for _, c := range d.Contacts {
+			if c.Name == id {
+				linked = true
+			}
+		}
+
+		if linked {
+			names = append(names, d.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// 이름으로 호스트의 복사본을 반환합니다. 호스트가 없으면 ErrObjectNotFound 를
+// 반환합니다.
+func (m *MemoryRepository) Host(name string) (*Host, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	h, ok := m.hosts[name]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	return h.copy(), nil
+}
+
+// 호스트의 복사본을 저장합니다.
+func (m *MemoryRepository) CreateHost(h *Host) error {
+	defer m.lock()()
+
+	m.hosts[h.Name] = h.copy()
+
+	return nil
+}
+
+// 기존 이름으로 저장된 호스트를 주어진 호스트의 복사본으로 바꿉니다. 호스트의
+// 이름이 바뀌면 새로운 이름으로 저장됩니다.
+func (m *MemoryRepository) UpdateHost(name string, h *Host) error {
+	defer m.lock()()
+
+	if _, ok := m.hosts[name]; !ok {
+		return ErrObjectNotFound
+	}
+
+	delete(m.hosts, name)
+	m.hosts[h.Name] = h.copy()
+
+	return nil
+}
+
+// 이름으로 호스트를 삭제합니다.
+func (m *MemoryRepository) DeleteHost(name string) error {
+	defer m.lock()()
+
+	if _, ok := m.hosts[name]; !ok {
+		return ErrObjectNotFound
+	}
+
+	delete(m.hosts, name)
+
+	return nil
+}
+
+// 주어진 도메인에 종속된 호스트 이름들을 정렬하여 반환합니다.
+func (m *MemoryRepository) HostsBySuperordinate(domain string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	names := []string{}
+
+	for _, h := range m.hosts {
+		if h.Superordinate == domain {
+			names = append(names, h.Name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// ID 로 연락처의 복사본을 반환합니다. 연락처가 없으면 ErrObjectNotFound 를
+// 반환합니다.
+func (m *MemoryRepository) Contact(id string) (*Contact, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	c, ok := m.contacts[id]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	return c.copy(), nil
+}
+
+// 연락처의 복사본을 저장합니다.
+func (m *MemoryRepository) CreateContact(c *Contact) error {
+	defer m.lock()()
+
+	m.contacts[c.ID] = c.copy()
+
+	return nil
+}
+
+// 저장된 연락처를 주어진 연락처의 복사본으로 바꿉니다.
+func (m *MemoryRepository) UpdateContact(c *Contact) error {
+	defer m.lock()()
+
+	if _, ok := m.contacts[c.ID]; !ok {
+		return ErrObjectNotFound
+	}
+
+	m.contacts[c.ID] = c.copy()
+
+	return nil
+}
+
+// ID 로 연락처를 삭제합니다.
+func (m *MemoryRepository) DeleteContact(id string) error {
+	defer m.lock()()
+
+	if _, ok := m.contacts[id]; !ok {
+		return ErrObjectNotFound
+	}
+
+	delete(m.contacts, id)
+
+	return nil
+}
+
+// ID 로 출시 단계 신청의 복사본을 반환합니다. 신청이 없으면 ErrObjectNotFound 를
+// 반환합니다.
+func (m *MemoryRepository) Application(id string) (*Application, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	a, ok := m.applications[id]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	return a.copy(), nil
+}
+
+// 출시 단계 신청의 복사본을 저장합니다.
+func (m *MemoryRepository) CreateApplication(a *Application) error {
+	defer m.lock()()
+
+	m.applications[a.ID] = a.copy()
+
+	return nil
+}
+
+// 저장된 출시 단계 신청을 주어진 신청의 복사본으로 바꿉니다.
+func (m *MemoryRepository) UpdateApplication(a *Application) error {
+	defer m.lock()()
+
+	if _, ok := m.applications[a.ID]; !ok {
+		return ErrObjectNotFound
+	}
+
+	m.applications[a.ID] = a.copy()
+
+	return nil
+}
+
+// ID 로 출시 단계 신청을 삭제합니다.
+func (m *MemoryRepository) DeleteApplication(id string) error {
+	defer m.lock()()
+
+	if _, ok := m.applications[id]; !ok {
+		return ErrObjectNotFound
+	}
+
+	delete(m.applications, id)
+
+	return nil
+}
+
+// ID 로 조직의 복사본을 반환합니다. 조직이 없으면 ErrObjectNotFound 를 반환합니다.
+func (m *MemoryRepository) Organization(id string) (*Organization, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	o, ok := m.orgs[id]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	return o.copy(), nil
+}
+
+// 조직의 복사본을 저장합니다.
+func (m *MemoryRepository) CreateOrganization(o *Organization) error {
+	defer m.lock()()
+
+	m.orgs[o.ID] = o.copy()
+
+	return nil
+}
+
+// 저장된 조직을 주어진 조직의 복사본으로 바꿉니다.
+func (m *MemoryRepository) UpdateOrganization(o *Organization) error {
+	defer m.lock()()
+
+	if _, ok := m.orgs[o.ID]; !ok {
+		return ErrObjectNotFound
+	}
+
+	m.orgs[o.ID] = o.copy()
+
+	return nil
+}
+
+// ID 로 조직을 삭제합니다.
+func (m *MemoryRepository) DeleteOrganization(id string) error {
+	defer m.lock()()
+
+	if _, ok := m.orgs[id]; !ok {
+		return ErrObjectNotFound
+	}
+
+	delete(m.orgs, id)
+
+	return nil
+}
+
+// 조직이 도메인이나 연락처에 연결되어 있거나 다른 조직의 상위 조직인지 확인합니다.
+func (m *MemoryRepository) OrganizationLinked(id string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	linked := func(links []types.OrgExtensionID) bool {
+		for _, l := range links {
+			if l.ID == id {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, d := range m.domains {
+		if linked(d.Organizations) {
+			return true, nil
+		}
+	}
+
+	for _, c := range m.contacts {
+		if linked(c.Organizations) {
+			return true, nil
+		}
+	}
+
+	for _, o := range m.orgs {
+		if o.ParentID == id {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// 서비스 메시지를 메모리에 저장하는 큐입니다.
+type MemoryPollQueue struct {
+	messages map[string][]*PollMessage
+	lastID   int64
+
+	// 메시지 목록의 읽기, 쓰기 작업에 Thread Safe 접근을 보장하기 위한 Mutex 입니다.
+	mu sync.RWMutex
+}
+
+// 비어있는 새로운 메모리 큐를 생성합니다.
+func NewMemoryPollQueue() *MemoryPollQueue {
+	return &MemoryPollQueue{
+		messages: map[string][]*PollMessage{},
+	}
+}
+
+// 클라이언트의 큐에 메시지를 추가하고 메시지에 새로운 ID 를 설정합니다.
+func (q *MemoryPollQueue) Enqueue(m *PollMessage) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.lastID++
+	m.ID = q.lastID
+
+	c := *m
+	q.messages[m.ClientID] = append(q.messages[m.ClientID], &c)
+
+	return nil
+}
+
+// 클라이언트의 가장 오래된 메시지와 큐에 있는 메시지의 수를 반환합니다. 큐가
+// 비어있으면 nil 을 반환합니다.
+func (q *MemoryPollQueue) Oldest(clientID string) (*PollMessage, int, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	messages := q.messages[clientID]
+	if len(messages) == 0 {
+		return nil, 0, nil
+	}
+
+	m := *messages[0]
+
+	return &m, len(messages), nil
+}
+
+// 클라이언트의 큐에서 메시지를 삭제하고 남은 메시지의 수를 반환합니다. 메시지가
+// 없으면 ErrObjectNotFound 를 반환합니다.
+func (q *MemoryPollQueue) Acknowledge(clientID string, id int64) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	messages := q.messages[clientID]
+
+	for i, m := range messages {
+		if m.ID != id {
+			continue
+		}
+
+		q.messages[clientID] = append(messages[:i:i], messages[i+1:]...)
+
+		return len(messages) - 1, nil
+	}
+
+	return 0, ErrObjectNotFound
+}
+
+// 할당 토큰을 메모리에 저장하는 저장소입니다.
+type MemoryAllocationTokenStore struct {
+	tokens map[string]*AllocationToken
+
+	// 토큰 목록의 읽기, 쓰기 작업에 Thread Safe 접근을 보장하기 위한 Mutex 입니다.
+	mu sync.RWMutex
+}
+
+// 주어진 토큰을 가진 새로운 메모리 저장소를 생성합니다.
+func NewMemoryAllocationTokenStore(tokens ...AllocationToken) *MemoryAllocationTokenStore {
+	m := &MemoryAllocationTokenStore{
+		tokens: map[string]*AllocationToken{},
+	}
+
+	for _, t := range tokens {
+		m.Add(t)
+	}
+
+	return m
+}
+
+// 토큰을 추가합니다. 같은 토큰이 이미 있으면 바꿉니다.
+func (m *MemoryAllocationTokenStore) Add(t AllocationToken) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tokens[t.Token] = &t
+}
+
+// 토큰의 복사본을 반환합니다. 토큰이 없으면 ErrObjectNotFound 를 반환합니다.
+func (m *MemoryAllocationTokenStore) AllocationToken(token string) (*AllocationToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	t, ok := m.tokens[token]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	c := *t
+
+	return &c, nil
+}
+
+// 사용되지 않은 토큰에 도메인 이름이 있어서 생성할 때 토큰이 필요한지 확인합니다.
+func (m *MemoryAllocationTokenStore) TokenRequired(name string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, t := range m.tokens {
+		if t.UsedDate == nil && indexOf(normalizeAll(t.Names), name) >= 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// 토큰을 도메인에 사용된 것으로 표시합니다. 이미 사용된 토큰이면
+// ErrAllocationTokenUsed 를 반환합니다.
+func (m *MemoryAllocationTokenStore) UseAllocationToken(token, name string, date time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tokens[token]
+	if !ok {
+		return ErrObjectNotFound
+	}
+
+	if t.UsedDate != nil {
+		return ErrAllocationTokenUsed
+	}
+
+	t.UsedBy = name
+	t.UsedDate = &date
+
+	return nil
+}
+
+// 도메인에 마지막으로 사용된 토큰을 반환합니다. 사용된 토큰이 없으면 nil 을
+// 반환합니다.
+func (m *MemoryAllocationTokenStore) DomainAllocationToken(name string) (*AllocationToken, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var last *AllocationToken
+
+	for _, t := range m.tokens {
+		if t.UsedBy == name && (last == nil || t.UsedDate.After(*last.UsedDate)) {
+			last = t
+		}
+	}
+
+	if last == nil {
+		return nil, nil
+	}
+
+	c := *last
+
+	return &c, nil
+}
+
+// 점검 일정을 메모리에 저장하는 저장소입니다.
+type MemoryMaintenanceStore struct {
+	maintenances map[string]*types.MaintenanceItem
+
+	// 일정 목록의 읽기, 쓰기 작업에 Thread Safe 접근을 보장하기 위한 Mutex 입니다.
+	mu sync.RWMutex
+}
+
+// 새로운 메모리 저장소를 생성합니다.
+func NewMemoryMaintenanceStore() *MemoryMaintenanceStore {
+	return &MemoryMaintenanceStore{
+		maintenances: map[string]*types.MaintenanceItem{},
+	}
+}
+
+// ID 로 점검 일정의 복사본을 반환합니다. 일정이 없으면 ErrObjectNotFound 를
+// 반환합니다.
+func (m *MemoryMaintenanceStore) Maintenance(id string) (*types.MaintenanceItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	item, ok := m.maintenances[id]
+	if !ok {
+		return nil, ErrObjectNotFound
+	}
+
+	return copyMaintenance(item), nil
+}
+
+// 모든 점검 일정을 시작 시간 순서로 반환합니다.
+func (m *MemoryMaintenanceStore) Maintenances() ([]types.MaintenanceItem, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	maintenances := make([]types.MaintenanceItem, 0, len(m.maintenances))
+
+	for _, item := range m.maintenances {
+		maintenances = append(maintenances, *copyMaintenance(item))
+	}
+
+	sortMaintenances(maintenances)
+
+	return maintenances, nil
+}
+
+// 점검 일정의 복사본을 저장합니다. 같은 ID 의 일정이 있으면 바꿉니다.
+func (m *MemoryMaintenanceStore) SaveMaintenance(item *types.MaintenanceItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.maintenances[item.ID.ID] = copyMaintenance(item)
+
+	return nil
+}
+
+// ID 로 점검 일정을 삭제합니다.
+func (m *MemoryMaintenanceStore) DeleteMaintenance(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.maintenances[id]; !ok {
+		return ErrObjectNotFound
+	}
+
+	delete(m.maintenances, id)
+
+	return nil
+}
+
+func copyMaintenance(item *types.MaintenanceItem) *types.MaintenanceItem {
+	c := *item
+	c.Type = append([]types.MaintenanceText(nil), item.Type...)
+	c.Systems.System = append([]types.MaintenanceSystem(nil), item.Systems.System...)
+	c.Description = append([]types.MaintenanceDescription(nil), item.Description...)
+
+	if item.TLDs != nil {
+		c.TLDs = &types.MaintenanceTLDs{TLD: append([]string(nil), item.TLDs.TLD...)}
+	}
+
+	if item.Intervention != nil {
+		i := *item.Intervention
+		c.Intervention = &i
+	}
+
+	return &c
+}
@@ -0,0 +1,763 @@
+package registry
+
+import (
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 클라이언트가 추가하거나 제거할 수 있는 조직 상태입니다.
+var clientOrgStatuses = map[types.OrgStatusType]struct{}{
+	types.OrgStatusClientDeleteProhibited: {},
+	types.OrgStatusClientUpdateProhibited: {},
+	types.OrgStatusClientLinkProhibited:   {},
+}
+
+// 조직을 저장하는 저장소입니다. (RFC 8543) Registry.Repository 가 이 인터페이스를
+// 구현하면 org-1.0 개체와 도메인, 연락처를 조직에 연결하는 orgext-1.0 확장을
+// 지원합니다. 트랜잭션에 전달되는 저장소도 이 인터페이스를 구현해야 합니다.
+type OrganizationRepository interface {
+	Organization(id string) (*Organization, error)
+	CreateOrganization(o *Organization) error
+	UpdateOrganization(o *Organization) error
+	DeleteOrganization(id string) error
+
+	// 주어진 조직에 연결된 도메인, 연락처와 하위 조직이 있는지 확인합니다.
+	OrganizationLinked(id string) (bool, error)
+}
+
+// 등록된 조직입니다. 리셀러와 같이 도메인과 연락처에 연결되는 조직을 나타냅니다.
+type Organization struct {
+	ID         string
+	ROID       string
+	Roles      []types.OrgRole
+	Status     []types.OrgStatusType
+	ParentID   string
+	PostalInfo []types.OrgPostalInfo
+	Voice      *types.E164Type
+	Fax        *types.E164Type
+	Email      string
+	URL        string
+	Contacts   []types.OrgContact
+	ClientID   string
+	CreateID   string
+	CreateDate time.Time
+	UpdateID   string
+	UpdateDate *time.Time
+}
+
+func (o *Organization) hasStatus(status ...types.OrgStatusType) bool {
+	for _, s := range o.Status {
+		for _, want := range status {
+			if s == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// 조직에서 주어진 유형의 역할을 반환합니다. 역할이 없으면 nil 을 반환합니다.
+func (o *Organization) role(roleType string) *types.OrgRole {
+	for i := range o.Roles {
+		if o.Roles[i].Type == roleType {
+			return &o.Roles[i]
+		}
+	}
+
+	return nil
+}
+
+func (o *Organization) copy() *Organization {
+	c := *o
+	c.Roles = append([]types.OrgRole(nil), o.Roles...)
+	c.Status = append([]types.OrgStatusType(nil), o.Status...)
+	c.PostalInfo = append([]types.OrgPostalInfo(nil), o.PostalInfo...)
+	c.Contacts = append([]types.OrgContact(nil), o.Contacts...)
+
+	return &c
+}
+
+// 저장소가 조직을 지원하면 조직 저장소를 반환합니다. 지원하지 않으면 2101 로 응답합니다.
+func organizations(tx Repository) (OrganizationRepository, error) {
+	orgs, ok := tx.(OrganizationRepository)
+	if !ok {
+		return nil, errorf(epp.EppUnimplementedCommand, "organizations are not supported")
+	}
+
+	return orgs, nil
+}
+
+func (r *Registry) checkOrg(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.OrgCheckType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	orgs, err := organizations(r.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	result := types.OrgCheckDataType{}
+
+	for _, id := range cmd.Check.IDs {
+		cd := types.CheckOrg{
+			ID: types.CheckName{
+				Value:     id,
+				Available: true,
+			},
+		}
+
+		if _, err := orgs.Organization(id); err == nil {
+			cd.ID.Available = false
+			cd.Reason = "In use"
+		} else if errors.Cause(err) != ErrObjectNotFound {
+			return nil, err
+		}
+
+		result.CheckData.CheckData = append(result.CheckData.CheckData, cd)
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(result), nil
+}
+
+func (r *Registry) infoOrg(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.OrgInfoType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	orgs, err := organizations(r.Repository)
+	if err != nil {
+		return nil, err
+	}
+
+	o, err := orgs.Organization(cmd.Info.ID)
+	if err != nil {
+		return nil, notFound(err, "organization %s does not exist", cmd.Info.ID)
+	}
+
+	status, err := orgStatus(orgs, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.OrgInfoDataType{
+		InfoData: types.OrgInfoData{
+			ID:         o.ID,
+			ROID:       o.ROID,
+			Role:       o.Roles,
+			Status:     status,
+			ParentID:   o.ParentID,
+			PostalInfo: o.PostalInfo,
+			Voice:      o.Voice,
+			Fax:        o.Fax,
+			Email:      o.Email,
+			URL:        o.URL,
+			Contact:    o.Contacts,
+			ClientID:   o.ClientID,
+			CreateID:   o.CreateID,
+			CreateDate: o.CreateDate,
+			UpdateID:   o.UpdateID,
+			UpdateDate: o.UpdateDate,
+		},
+	}), nil
+}
+
+func (r *Registry) createOrg(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.OrgCreateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	if len(cmd.Create.Role) == 0 {
+		return nil, errorf(epp.EppMissingParam, "at least one role is required")
+	}
+
+	now := r.now()
+	o := &Organization{
+		ID:         cmd.Create.ID,
+		ParentID:   cmd.Create.ParentID,
+		PostalInfo: cmd.Create.PostalInfo,
+		Voice:      cmd.Create.Voice,
+		Fax:        cmd.Create.Fax,
+		Email:      cmd.Create.Email,
+		URL:        cmd.Create.URL,
+		ClientID:   s.ClientID,
+		CreateID:   s.ClientID,
+		CreateDate: now,
+	}
+
+	if err := addOrgRoles(o, cmd.Create.Role); err != nil {
+		return nil, err
+	}
+
+	if err := addOrgStatuses(o, cmd.Create.Status); err != nil {
+		return nil, err
+	}
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		orgs, err := organizations(tx)
+		if err != nil {
+			return err
+		}
+
+		if _, err := orgs.Organization(o.ID); err == nil {
+			return errorf(epp.EppObjectExists, "organization %s already exists", o.ID)
+		} else if errors.Cause(err) != ErrObjectNotFound {
+			return err
+		}
+
+		if err := addOrgContacts(tx, o, cmd.Create.Contact); err != nil {
+			return err
+		}
+
+		if err := checkOrgParent(orgs, o); err != nil {
+			return err
+		}
+
+		roid, err := r.roid(tx, "O")
+		if err != nil {
+			return err
+		}
+
+		o.ROID = roid
+
+		return orgs.CreateOrganization(o)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk).WithResData(types.OrgCreateDataType{
+		CreateData: types.OrgCreateData{
+			ID:         o.ID,
+			CreateDate: o.CreateDate,
+		},
+	}), nil
+}
+
+func (r *Registry) updateOrg(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.OrgUpdateType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	id := cmd.Update.ID
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		orgs, err := organizations(tx)
+		if err != nil {
+			return err
+		}
+
+		o, err := sponsoredOrganization(orgs, s, id)
+		if err != nil {
+			return err
+		}
+
+		removesUpdateProhibited := false
+
+		if cmd.Update.Remove != nil {
+			for _, status := range cmd.Update.Remove.Status {
+				if status.OrgStatusType == types.OrgStatusClientUpdateProhibited {
+					removesUpdateProhibited = true
+				}
+			}
+		}
+
+		if o.hasStatus(types.OrgStatusServerUpdateProhibited) ||
+			(o.hasStatus(types.OrgStatusClientUpdateProhibited) && !removesUpdateProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "organization %s has status update prohibited", id)
+		}
+
+		if rem := cmd.Update.Remove; rem != nil {
+			if err := removeFromOrg(o, rem); err != nil {
+				return err
+			}
+		}
+
+		if add := cmd.Update.Add; add != nil {
+			if err := addOrgRoles(o, add.Role); err != nil {
+				return err
+			}
+
+			if err := addOrgStatuses(o, add.Status); err != nil {
+				return err
+			}
+
+			if err := addOrgContacts(tx, o, add.Contact); err != nil {
+				return err
+			}
+		}
+
+		if len(o.Roles) == 0 {
+			return errorf(epp.EppParamPolicyError, "organization %s must have at least one role", id)
+		}
+
+		if chg := cmd.Update.Change; chg != nil {
+			changeOrg(o, chg)
+
+			if err := checkOrgParent(orgs, o); err != nil {
+				return err
+			}
+		}
+
+		now := r.now()
+		o.UpdateID = s.ClientID
+		o.UpdateDate = &now
+
+		return orgs.UpdateOrganization(o)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func (r *Registry) deleteOrg(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.OrgDeleteType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	id := cmd.Delete.ID
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		orgs, err := organizations(tx)
+		if err != nil {
+			return err
+		}
+
+		o, err := sponsoredOrganization(orgs, s, id)
+		if err != nil {
+			return err
+		}
+
+		if o.hasStatus(types.OrgStatusClientDeleteProhibited, types.OrgStatusServerDeleteProhibited) {
+			return errorf(epp.EppStatusProhibitsOp, "organization %s has status delete prohibited", id)
+		}
+
+		linked, err := orgs.OrganizationLinked(id)
+		if err != nil {
+			return err
+		}
+
+		if linked {
+			return errorf(epp.EppAssocProhibitsOp, "organization %s is linked to other objects", id)
+		}
+
+		return orgs.DeleteOrganization(id)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return epp.NewResponse(epp.EppOk), nil
+}
+
+func sponsoredOrganization(orgs OrganizationRepository, s *epp.Session, id string) (*Organization, error) {
+	o, err := orgs.Organization(id)
+	if err != nil {
+		return nil, notFound(err, "organization %s does not exist", id)
+	}
+
+	if o.ClientID != s.ClientID {
+		return nil, errorf(epp.EppAuthorisationError, "organization %s is not sponsored by %s", id, s.ClientID)
+	}
+
+	return o, nil
+}
+
+// 조직의 상태를 반환합니다. 다른 개체에 연결된 조직은 linked 상태를 가지며, 다른
+// 상태가 없는 경우 ok 상태를 가집니다.
+func orgStatus(orgs OrganizationRepository, o *Organization) ([]types.OrgStatus, error) {
+	status := []types.OrgStatus{}
+
+	for _, s := range o.Status {
+		status = append(status, types.OrgStatus{OrgStatusType: s})
+	}
+
+	linked, err := orgs.OrganizationLinked(o.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if linked {
+		status = append(status, types.OrgStatus{OrgStatusType: types.OrgStatusLinked})
+	}
+
+	if len(o.Status) == 0 {
+		status = append([]types.OrgStatus{{OrgStatusType: types.OrgStatusOk}}, status...)
+	}
+
+	return status, nil
+}
+
+// 조직에 역할을 추가합니다. 조직은 유형별로 하나의 역할만 가질 수 있습니다.
+func addOrgRoles(o *Organization, roles []types.OrgRole) error {
+	for _, role := range roles {
+		if role.Type == "" {
+			return errorf(epp.EppParamSyntaxError, "role type is required")
+		}
+
+		if o.role(role.Type) != nil {
+			return errorf(epp.EppParamPolicyError, "organization %s already has role %s", o.ID, role.Type)
+		}
+
+		for _, status := range role.Status {
+			if status.OrgRoleStatusType == types.OrgRoleStatusServerLinkProhibited {
+				return errorf(epp.EppParamPolicyError, "status %s can not be set by the client", status.OrgRoleStatusType)
+			}
+		}
+
+		o.Roles = append(o.Roles, role)
+	}
+
+	return nil
+}
+
+func addOrgStatuses(o *Organization, statuses []types.OrgStatus) error {
+	for _, status := range statuses {
+		if _, ok := clientOrgStatuses[status.OrgStatusType]; !ok {
+			return errorf(epp.EppParamPolicyError, "status %s can not be added by the client", status.OrgStatusType)
+		}
+
+		if o.hasStatus(status.OrgStatusType) {
+			return errorf(epp.EppParamPolicyError, "organization %s already has status %s", o.ID, status.OrgStatusType)
+		}
+
+		o.Status = append(o.Status, status.OrgStatusType)
+	}
+
+	return nil
+}
+
+// 조직에 연락처를 추가합니다. 연락처는 저장소에 존재해야 합니다.
+func addOrgContacts(tx Repository, o *Organization, contacts []types.OrgContact) error {
+	for _, c := range contacts {
+		if _, err := tx.Contact(c.Name); err != nil {
+			return notFound(err, "contact %s does not exist", c.Name)
+		}
+
+		for _, existing := range o.Contacts {
+			if existing.Name == c.Name && existing.Type == c.Type && existing.TypeName == c.TypeName {
+				return errorf(epp.EppParamPolicyError, "contact %s is already %s contact", c.Name, c.Type)
+			}
+		}
+
+		o.Contacts = append(o.Contacts, c)
+	}
+
+	return nil
+}
+
+// 조직 갱신 명령어의 제거할 연락처, 역할, 상태를 조직에서 제거합니다. 역할은 유형으로
+// 찾습니다.
+func removeFromOrg(o *Organization, rem *types.OrgAddRemove) error {
+	for _, c := range rem.Contact {
+		found := false
+
+		for i, existing := range o.Contacts {
+			if existing.Name == c.Name && existing.Type == c.Type && existing.TypeName == c.TypeName {
+				o.Contacts = append(o.Contacts[:i], o.Contacts[i+1:]...)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return errorf(epp.EppParamPolicyError, "contact %s is not %s contact", c.Name, c.Type)
+		}
+	}
+
+	for _, role := range rem.Role {
+		found := false
+
+		for i, existing := range o.Roles {
+			if existing.Type == role.Type {
+				o.Roles = append(o.Roles[:i], o.Roles[i+1:]...)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return errorf(epp.EppParamPolicyError, "organization %s does not have role %s", o.ID, role.Type)
+		}
+	}
+
+	for _, status := range rem.Status {
+		if _, ok := clientOrgStatuses[status.OrgStatusType]; !ok {
+			return errorf(epp.EppParamPolicyError, "status %s can not be removed by the client", status.OrgStatusType)
+		}
+
+		found := false
+
+		for i, existing := range o.Status {
+			if existing == status.OrgStatusType {
+				o.Status = append(o.Status[:i], o.Status[i+1:]...)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return errorf(epp.EppParamPolicyError, "organization %s does not have status %s", o.ID, status.OrgStatusType)
+		}
+	}
+
+	return nil
+}
+
+// 조직 갱신 명령어의 변경 사항을 적용합니다. 우편 정보는 같은 유형의 정보를 대체합니다.
+func changeOrg(o *Organization, chg *types.OrgChange) {
+	if chg.ParentID != nil {
+		o.ParentID = *chg.ParentID
+	}
+
+	for _, pi := range chg.PostalInfo {
+		replaced := false
+
+		for i, existing := range o.PostalInfo {
+			if existing.Type == pi.Type {
+				o.PostalInfo[i] = pi
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			o.PostalInfo = append(o.PostalInfo, pi)
+		}
+	}
+
+	if chg.Voice != nil {
+		o.Voice = chg.Voice
+	}
+
+	if chg.Fax != nil {
+		o.Fax = chg.Fax
+	}
+
+	if chg.Email != "" {
+		o.Email = chg.Email
+	}
+
+	if chg.URL != "" {
+		o.URL = chg.URL
+	}
+}
+
+// 상위 조직이 존재하고 조직 자신이나 하위 조직이 아닌지 확인합니다.
+func checkOrgParent(orgs OrganizationRepository, o *Organization) error {
+	for parentID := o.ParentID; parentID != ""; {
+		if parentID == o.ID {
+			return errorf(epp.EppParamPolicyError, "organization %s can not be its own parent", o.ID)
+		}
+
+		parent, err := orgs.Organization(parentID)
+		if err != nil {
+			return notFound(err, "organization %s does not exist", parentID)
+		}
+
+		parentID = parent.ParentID
+	}
+
+	return nil
+}
+
+// 명령어의 orgext-1.0 create 확장에서 개체에 연결할 조직을 반환합니다. 저장소가 조직을
+// 지원하지 않으면 2103 으로 응답합니다.
+func (r *Registry) orgExtensionCreate(data []byte) ([]types.OrgExtensionID, error) {
+	ext := types.OrgExtensionCreateType{}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	if len(ext.Create.ID) == 0 {
+		return nil, nil
+	}
+
+	if _, ok := r.Repository.(OrganizationRepository); !ok {
+		return nil, errorf(epp.EppUnimplementedExtension, "the orgext extension is not supported")
+	}
+
+	links, err := updateOrgLinks(nil, types.OrgExtensionUpdate{Add: &ext.Create})
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// 명령어의 orgext-1.0 update 확장을 개체의 조직 목록에 적용합니다. 확장이 없으면 목록을
+// 그대로 반환합니다.
+func (r *Registry) orgExtensionUpdate(data []byte, links []types.OrgExtensionID) ([]types.OrgExtensionID, error) {
+	ext := types.OrgExtensionUpdateType{}
+
+	if err := epp.Decode(data, &ext); err != nil {
+		return nil, err
+	}
+
+	if ext.Update.Add == nil && ext.Update.Remove == nil && ext.Update.Change == nil {
+		return links, nil
+	}
+
+	if _, ok := r.Repository.(OrganizationRepository); !ok {
+		return nil, errorf(epp.EppUnimplementedExtension, "the orgext extension is not supported")
+	}
+
+	return updateOrgLinks(links, ext.Update)
+}
+
+// 조직 목록에 제거, 추가, 변경을 순서대로 적용합니다. 개체는 역할별로 하나의 조직에만
+// 연결될 수 있으며, 변경의 조직 ID 가 비어있으면 해당 역할의 조직을 제거합니다.
+func updateOrgLinks(links []types.OrgExtensionID, update types.OrgExtensionUpdate) ([]types.OrgExtensionID, error) {
+	result := append([]types.OrgExtensionID(nil), links...)
+
+	indexOfRole := func(role string) int {
+		for i, l := range result {
+			if l.Role == role {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	if rem := update.Remove; rem != nil {
+		for _, id := range rem.ID {
+			i := indexOfRole(id.Role)
+			if i < 0 || result[i].ID != id.ID {
+				return nil, errorf(epp.EppParamPolicyError, "organization %s is not linked as %s", id.ID, id.Role)
+			}
+
+			result = append(result[:i], result[i+1:]...)
+		}
+	}
+
+	if add := update.Add; add != nil {
+		for _, id := range add.ID {
+			if id.ID == "" {
+				return nil, errorf(epp.EppParamSyntaxError, "organization id is required")
+			}
+
+			if indexOfRole(id.Role) >= 0 {
+				return nil, errorf(epp.EppParamPolicyError, "an organization is already linked as %s", id.Role)
+			}
+
+			result = append(result, id)
+		}
+	}
+
+	if chg := update.Change; chg != nil {
+		for _, id := range chg.ID {
+			i := indexOfRole(id.Role)
+			if i < 0 {
+				return nil, errorf(epp.EppParamPolicyError, "no organization is linked as %s", id.Role)
+			}
+
+			if id.ID == "" {
+				result = append(result[:i], result[i+1:]...)
+				continue
+			}
+
+			result[i] = id
+		}
+	}
+
+	return result, nil
+}
+
+// 갱신된 조직 목록에서 기존 목록에 없던 연결을 반환합니다. 이미 연결된 조직은 다시
+// 검사하지 않습니다.
+func newOrgLinks(before, after []types.OrgExtensionID) []types.OrgExtensionID {
+	links := []types.OrgExtensionID{}
+
+	for _, l := range after {
+		found := false
+
+		for _, existing := range before {
+			if existing == l {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			links = append(links, l)
+		}
+	}
+
+	return links
+}
+
+// 개체에 연결된 조직이 존재하고 연결할 수 있는지 확인합니다. 조직은 연결된 역할을
+// 가져야 하며 개체의 관리 클라이언트가 관리하는 조직이어야 합니다.
+func checkOrgLinks(tx Repository, clientID string, links []types.OrgExtensionID) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	orgs, err := organizations(tx)
+	if err != nil {
+		return err
+	}
+
+	for _, l := range links {
+		o, err := orgs.Organization(l.ID)
+		if err != nil {
+			return notFound(err, "organization %s does not exist", l.ID)
+		}
+
+		if o.ClientID != clientID {
+			return errorf(epp.EppAuthorisationError, "organization %s is not sponsored by %s", l.ID, clientID)
+		}
+
+		role := o.role(l.Role)
+		if role == nil {
+			return errorf(epp.EppParamPolicyError, "organization %s does not have role %s", l.ID, l.Role)
+		}
+
+		if o.hasStatus(types.OrgStatusClientLinkProhibited, types.OrgStatusServerLinkProhibited, types.OrgStatusHold, types.OrgStatusTerminated) {
+			return errorf(epp.EppStatusProhibitsOp, "organization %s can not be linked", l.ID)
+		}
+
+		for _, status := range role.Status {
+			if status.OrgRoleStatusType != types.OrgRoleStatusOk {
+				return errorf(epp.EppStatusProhibitsOp, "role %s of organization %s can not be linked", l.Role, l.ID)
+			}
+		}
+	}
+
+	return nil
+}
+
+// 개체에 연결된 조직이 있으면 orgext-1.0 infData 를 응답에 추가합니다.
+func withOrganizations(response *epp.ResponseBuilder, links []types.OrgExtensionID) *epp.ResponseBuilder {
+	if len(links) == 0 {
+		return response
+	}
+
+	return response.WithExtension(types.OrgExtensionInfoDataType{
+		InfoData: types.OrgExtensionIDs{ID: links},
+	})
+}
@@ -0,0 +1,189 @@
+package registry
+
+import (
+	"testing"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func orgCreate(id string, roles ...string) types.OrgCreateType {
+	o := types.OrgCreateType{
+		Create: types.OrgCreate{
+			ID: id,
+			PostalInfo: []types.OrgPostalInfo{
+				{
+					Name:    "Example Reseller AB",
+					Address: types.Address{City: "Stockholm", CountryCode: "SE"},
+					Type:    types.PostalInfoInternational,
+				},
+			},
+		},
+	}
+
+	for _, role := range roles {
+		o.Create.Role = append(o.Create.Role, types.OrgRole{Type: role})
+	}
+
+	return o
+}
+
+func orgLinks(links ...string) types.OrgExtensionIDs {
+	ids := types.OrgExtensionIDs{}
+
+	for i := 0; i < len(links); i += 2 {
+		ids.ID = append(ids.ID, types.OrgExtensionID{Role: links[i], ID: links[i+1]})
+	}
+
+	return ids
+}
+
+func testRegistryOrganization(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	if _, ok := tr.registry.Repository.(OrganizationRepository); !ok {
+		tr.send(s, epp.EppUnimplementedCommand, orgCreate("res1523", types.OrgRoleReseller))
+		t.Skip("the repository does not support organizations")
+	}
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceOrg10)
+	assert.Contains(t, string(greeting), types.NameSpaceOrgExt10)
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+
+	// Organizations can only have one role of each type and existing contacts.
+	tr.send(s, epp.EppParamPolicyError, orgCreate("res1523", types.OrgRoleReseller, types.OrgRoleReseller))
+
+	create := orgCreate("res1523", types.OrgRoleReseller)
+	create.Create.Contact = []types.OrgContact{{Name: "sh8013", Type: types.OrgContactAdmin}}
+	tr.send(s, epp.EppObjectDoesNotExist, create)
+
+	create.Create.Contact = []types.OrgContact{{Name: "jd1234", Type: types.OrgContactAdmin}}
+	tr.send(s, epp.EppOk, create)
+	tr.send(s, epp.EppObjectExists, create)
+	tr.send(other, epp.EppOk, orgCreate("proxy2345", types.OrgRolePrivacyProxy))
+
+	check := types.OrgCheckType{Check: types.OrgCheck{IDs: []string{"res1523", "res9999"}}}
+	checkData := types.OrgCheckDataType{}
+	decodeResData(t, tr.send(s, epp.EppOk, check), &checkData)
+	require.Len(t, checkData.CheckData.CheckData, 2)
+	assert.False(t, checkData.CheckData.CheckData[0].ID.Available)
+	assert.True(t, checkData.CheckData.CheckData[1].ID.Available)
+
+	// Parents must exist and can not create a cycle.
+	parent := "res1523"
+	child := orgCreate("sub1523", types.OrgRoleReseller)
+	child.Create.ParentID = "res9999"
+	tr.send(s, epp.EppObjectDoesNotExist, child)
+
+	child.Create.ParentID = parent
+	tr.send(s, epp.EppOk, child)
+
+	self := "sub1523"
+	tr.send(s, epp.EppParamPolicyError, types.OrgUpdateType{
+		Update: types.OrgUpdate{ID: "res1523", Change: &types.OrgChange{ParentID: &self}},
+	})
+
+	// Domains and contacts can only be linked to organizations with the role
+	// sponsored by the same client.
+	domain := domainCreate("example.se")
+	withOrgs := func(links ...string) types.OrgExtensionCreateType {
+		return types.OrgExtensionCreateType{Create: orgLinks(links...)}
+	}
+
+	tr.send(s, epp.EppObjectDoesNotExist, domain, withOrgs(types.OrgRoleReseller, "res9999"))
+	tr.send(s, epp.EppParamPolicyError, domain, withOrgs(types.OrgRolePrivacyProxy, "res1523"))
+	tr.send(s, epp.EppAuthorisationError, domain, withOrgs(types.OrgRolePrivacyProxy, "proxy2345"))
+	tr.send(s, epp.EppParamPolicyError, domain, withOrgs(types.OrgRoleReseller, "res1523", types.OrgRoleReseller, "sub1523"))
+	tr.send(s, epp.EppOk, domain, withOrgs(types.OrgRoleReseller, "res1523"))
+
+	info := types.DomainInfoType{Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}}}
+	domainOrgs := func() []types.OrgExtensionID {
+		ext := types.OrgExtensionInfoDataType{}
+		require.Nil(t, epp.Decode(tr.send(s, epp.EppOk, info), &types.Response{Extension: &ext}))
+
+		return ext.InfoData.ID
+	}
+
+	assert.Equal(t, orgLinks(types.OrgRoleReseller, "res1523").ID, domainOrgs())
+
+	// Linked organizations can not be deleted.
+	orgInfo := types.OrgInfoType{Info: types.OrgInfo{ID: "res1523"}}
+	infoData := types.OrgInfoDataType{}
+	decodeResData(t, tr.send(s, epp.EppOk, orgInfo), &infoData)
+	assert.Equal(t, "res1523", infoData.InfoData.ID)
+	assert.Contains(t, infoData.InfoData.Status, types.OrgStatus{OrgStatusType: types.OrgStatusLinked})
+
+	tr.send(s, epp.EppAssocProhibitsOp, types.OrgDeleteType{Delete: types.OrgDelete{ID: "res1523"}})
+	tr.send(other, epp.EppAuthorisationError, types.OrgDeleteType{Delete: types.OrgDelete{ID: "sub1523"}})
+	tr.send(s, epp.EppOk, types.OrgDeleteType{Delete: types.OrgDelete{ID: "sub1523"}})
+
+	// Links are changed with the update extension, an empty id in chg
+	// removes the organization for the role.
+	update := types.DomainUpdateType{Update: types.DomainUpdate{Name: "example.se"}}
+	linked := orgLinks(types.OrgRoleReseller, "res1523")
+	unlinked := orgLinks(types.OrgRoleReseller, "")
+
+	tr.send(s, epp.EppParamPolicyError, update, types.OrgExtensionUpdateType{Update: types.OrgExtensionUpdate{Add: &linked}})
+	tr.send(s, epp.EppOk, update, types.OrgExtensionUpdateType{Update: types.OrgExtensionUpdate{Change: &unlinked}})
+	assert.Empty(t, domainOrgs())
+
+	// Organizations with link prohibited status can not be linked.
+	tr.send(s, epp.EppOk, types.OrgUpdateType{
+		Update: types.OrgUpdate{
+			ID: "res1523",
+			Add: &types.OrgAddRemove{
+				Status: []types.OrgStatus{{OrgStatusType: types.OrgStatusClientLinkProhibited}},
+			},
+		},
+	})
+
+	tr.send(s, epp.EppStatusProhibitsOp, update, types.OrgExtensionUpdateType{Update: types.OrgExtensionUpdate{Add: &linked}})
+
+	contact := contactCreate("sh8013")
+	tr.send(s, epp.EppStatusProhibitsOp, contact, types.OrgExtensionCreateType{Create: linked})
+
+	tr.send(s, epp.EppOk, types.OrgUpdateType{
+		Update: types.OrgUpdate{
+			ID: "res1523",
+			Remove: &types.OrgAddRemove{
+				Status: []types.OrgStatus{{OrgStatusType: types.OrgStatusClientLinkProhibited}},
+			},
+		},
+	})
+
+	tr.send(s, epp.EppOk, contact, types.OrgExtensionCreateType{Create: linked})
+
+	contactData := types.OrgExtensionInfoDataType{}
+	contactInfo := types.ContactInfoType{Info: types.ContactInfo{Name: "sh8013"}}
+	require.Nil(t, epp.Decode(tr.send(s, epp.EppOk, contactInfo), &types.Response{Extension: &contactData}))
+	assert.Equal(t, linked.ID, contactData.InfoData.ID)
+
+	tr.send(s, epp.EppOk, types.ContactDeleteType{Delete: types.ContactDelete{Name: "sh8013"}})
+	tr.send(s, epp.EppOk, types.OrgDeleteType{Delete: types.OrgDelete{ID: "res1523"}})
+	tr.send(s, epp.EppObjectDoesNotExist, orgInfo)
+
+	// Applications keep their links and the allocated domain is linked.
+	tr.send(s, epp.EppOk, orgCreate("res2000", types.OrgRoleReseller))
+	tr.registry.Launch = &Launch{
+		Phases: []LaunchPhase{{Phase: launchPhase(types.LaunchPhaseSunrise), Start: tr.now, Applications: true}},
+	}
+
+	application := launchCreate(types.LaunchPhaseSunrise, "c21k")
+	application.Create.Type = types.LaunchObjectApplication
+
+	tr.send(s, epp.EppObjectDoesNotExist, domainCreate("applied.se"), application, withOrgs(types.OrgRoleReseller, "res9999"))
+
+	created := types.LaunchExtensionCreateDataType{}
+	response := tr.send(s, epp.EppOkPending, domainCreate("applied.se"), application, withOrgs(types.OrgRoleReseller, "res2000"))
+	require.Nil(t, epp.Decode(response, &types.Response{Extension: &created}))
+	require.Nil(t, tr.registry.SetApplicationStatus(created.CreateData.ApplicationID, types.LaunchStatusAllocated))
+
+	info.Info.Name.Name = "applied.se"
+	assert.Equal(t, orgLinks(types.OrgRoleReseller, "res2000").ID, domainOrgs())
+}
@@ -0,0 +1,230 @@
+package registry
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 클라이언트가 poll 명령어로 가져가는 서비스 메시지입니다.
+type PollMessage struct {
+	ID        int64
+	ClientID  string
+	QueueDate time.Time
+
+	// 사람이 읽을 수 있는 메시지와 언어입니다. 언어가 비어있으면 영어입니다.
+	Message  string
+	Language string
+
+	// 응답의 resData 로 전달되는 개체의 정보입니다. 정보가 없는 메시지는 nil 입니다.
+	Data *types.PollResultData
+
+	// 서버에서 개체를 바꾼 작업의 정보입니다. 응답의 extension 으로 전달되며,
+	// 서버에서 바꾼 개체가 아니면 nil 입니다.
+	ChangeData *types.ChangePollChangeData
+}
+
+// 클라이언트 ID 별로 서비스 메시지를 저장하는 큐입니다. 메시지는 추가된 순서대로
+// 전달되며 클라이언트가 확인(ack)할 때까지 큐에 남아있습니다.
+type PollQueue interface {
+	// 메시지를 큐의 끝에 추가하고 메시지의 ID 를 설정합니다.
+	Enqueue(m *PollMessage) error
+
+	// 클라이언트의 가장 오래된 메시지와 큐에 있는 메시지의 수를 반환합니다.
+	// 메시지가 없으면 nil 과 0 을 반환합니다.
+	Oldest(clientID string) (*PollMessage, int, error)
+
+	// 클라이언트의 메시지를 큐에서 삭제하고 남은 메시지의 수를 반환합니다. 클라이언트의
+	// 큐에 해당 메시지가 없으면 ErrObjectNotFound 를 반환합니다.
+	Acknowledge(clientID string, id int64) (int, error)
+}
+
+// 클라이언트의 큐에 메시지를 추가합니다. 백엔드에서 이전 요청이나 대기중인 작업의
+// 결과를 클라이언트에게 알릴 때 사용합니다.
+func (r *Registry) Notify(clientID, message string, data *types.PollResultData) (*PollMessage, error) {
+	m := &PollMessage{
+		ClientID:  clientID,
+		QueueDate: r.now(),
+		Message:   message,
+		Data:      data,
+	}
+
+	if err := r.Poll.Enqueue(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// 도메인의 현재 이전 상태를 클라이언트에게 알립니다.
+func (r *Registry) NotifyDomainTransfer(clientID string, d *Domain) (*PollMessage, error) {
+	if d.Transfer == nil {
+		return nil, errors.Errorf("domain %s has no transfer", d.Name)
+	}
+
+	n := domainTransferNotices(d, clientID)[0]
+
+	return r.Notify(clientID, n.message, n.data)
+}
+
+// 연락처의 현재 이전 상태를 클라이언트에게 알립니다.
+func (r *Registry) NotifyContactTransfer(clientID string, c *Contact) (*PollMessage, error) {
+	if c.Transfer == nil {
+		return nil, errors.Errorf("contact %s has no transfer", c.ID)
+	}
+
+	return r.Notify(clientID, fmt.Sprintf("Transfer %s.", c.Transfer.Status), &types.PollResultData{
+		ContactTransferData: &types.ContactTransferData{
+			Name:           c.ID,
+			TransferStatus: types.ContactTransferStatusType(c.Transfer.Status),
+			RequestingID:   c.Transfer.RequestingID,
+			RequestingDate: c.Transfer.RequestingDate,
+			ActingID:       c.Transfer.ActingID,
+			ActingDate:     c.Transfer.ActingDate,
+		},
+	})
+}
+
+// 대기중이던 도메인 작업이 완료된 결과를 클라이언트에게 알립니다. (RFC 5731 3.3)
+func (r *Registry) NotifyDomainPendingAction(clientID, name string, result bool, trID types.PendingActivationTransactionID) (*PollMessage, error) {
+	return r.Notify(clientID, pendingActionMessage(result), &types.PollResultData{
+		DomainPendingActivationNotificationData: &types.DomainPendingActivationNotificationData{
+			Name: types.PendingActivationNotificationName{
+				Name:                    name,
+				PendingActivationResult: result,
+			},
+			TransactionID: trID,
+			Date:          r.now(),
+		},
+	})
+}
+
+// 대기중이던 연락처 작업이 완료된 결과를 클라이언트에게 알립니다. (RFC 5733 3.3)
+func (r *Registry) NotifyContactPendingAction(clientID, id string, result bool, trID types.PendingActivationTransactionID) (*PollMessage, error) {
+	return r.Notify(clientID, pendingActionMessage(result), &types.PollResultData{
+		ContactPendingActivationNotificationData: &types.ContactPendingActivationNotificationData{
+			Name: types.PendingActivationNotificationName{
+				Name:                    id,
+				PendingActivationResult: result,
+			},
+			TransactionID: trID,
+			Date:          r.now(),
+		},
+	})
+}
+
+func pendingActionMessage(result bool) string {
+	if result {
+		return "Pending action completed successfully."
+	}
+
+	return "Pending action rejected."
+}
+
+// 트랜잭션이 완료된 후 클라이언트에게 보낼 서비스 메시지입니다. 트랜잭션 안에서
+// 메시지를 추가하면 저장소와 큐가 같은 데이터베이스를 사용할 때 교착 상태가 될 수
+// 있으므로 트랜잭션이 끝난 후에 추가합니다.
+type notice struct {
+	clientID string
+	message  string
+	data     *types.PollResultData
+	change   *types.ChangePollChangeData
+}
+
+// 메시지들을 큐에 추가합니다. 작업은 이미 완료되었으므로 메시지를 추가하지 못한 경우
+// 로그만 남깁니다.
+func (r *Registry) sendNotices(notices []notice) {
+	for _, n := range notices {
+		m := &PollMessage{
+			ClientID:   n.clientID,
+			QueueDate:  r.now(),
+			Message:    n.message,
+			Data:       n.data,
+			ChangeData: n.change,
+		}
+
+		if err := r.Poll.Enqueue(m); err != nil {
+			log.Printf("could not queue message for %s: %s", n.clientID, err.Error())
+		}
+	}
+}
+
+func (r *Registry) poll(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.Poll{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, errorf(epp.EppSyntaxError, "could not decode poll")
+	}
+
+	switch cmd.Poll.Operation {
+	case types.PollOperationRequest:
+		return r.pollRequest(s)
+	case types.PollOperationAcknowledge:
+		return r.pollAcknowledge(s, cmd.Poll.MessageID)
+	}
+
+	return nil, errorf(epp.EppParamSyntaxError, "unknown poll operation %s", cmd.Poll.Operation)
+}
+
+// 가장 오래된 메시지를 큐에서 삭제하지 않고 반환합니다.
+func (r *Registry) pollRequest(s *epp.Session) (*epp.ResponseBuilder, error) {
+	m, count, err := r.Poll.Oldest(s.ClientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if m == nil {
+		return epp.NewResponse(epp.EppOkNoMessages), nil
+	}
+
+	queueDate := m.QueueDate.UTC()
+
+	response := epp.NewResponse(epp.EppOkMessages).WithMsgQ(types.MessageQueue{
+		QueueDate: &queueDate,
+		Message: &types.MessageQueueMessage{
+			Value:    m.Message,
+			Language: m.Language,
+		},
+		Count: count,
+		ID:    strconv.FormatInt(m.ID, 10),
+	})
+
+	if m.Data != nil {
+		response = response.WithResData(m.Data)
+	}
+
+	if m.ChangeData != nil {
+		response = response.WithExtension(types.ChangePollExtensionChangeDataType{
+			ChangeData: *m.ChangeData,
+		})
+	}
+
+	return response, nil
+}
+
+// 메시지를 큐에서 삭제하고 남은 메시지의 수를 반환합니다.
+func (r *Registry) pollAcknowledge(s *epp.Session, msgID string) (*epp.ResponseBuilder, error) {
+	if msgID == "" {
+		return nil, errorf(epp.EppMissingParam, "msgID is required to acknowledge a message")
+	}
+
+	id, err := strconv.ParseInt(msgID, 10, 64)
+	if err != nil {
+		return nil, errorf(epp.EppObjectDoesNotExist, "message %s does not exist", msgID)
+	}
+
+	count, err := r.Poll.Acknowledge(s.ClientID, id)
+	if err != nil {
+		return nil, notFound(err, "message %s does not exist", msgID)
+	}
+
+	return epp.NewResponse(epp.EppOk).WithMsgQ(types.MessageQueue{
+		Count: count,
+		ID:    msgID,
+	}), nil
+}
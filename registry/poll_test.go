@@ -0,0 +1,116 @@
+package registry
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func msgID(m *PollMessage) string {
+	return strconv.FormatInt(m.ID, 10)
+}
+
+func pollCommand(op types.PollOperation, msgID string) types.Poll {
+	return types.Poll{Poll: types.PollCommand{Operation: op, MessageID: msgID}}
+}
+
+func testRegistryPoll(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	tr.send(s, epp.EppOkNoMessages, pollCommand(types.PollOperationRequest, ""))
+
+	// The schema only allows known operations but servers without validation
+	// must still reject unknown operations as a syntax error.
+	data, err := epp.NewCommand(pollCommand(types.PollOperation("purge"), "")).Encode()
+	require.Nil(t, err)
+
+	response, err := tr.mux.Handle(s, data)
+	require.Nil(t, err)
+
+	result := types.Response{}
+	require.Nil(t, epp.Decode(response, &result))
+	assert.Equal(t, epp.EppParamSyntaxError.Code(), result.Result[0].Code, string(response))
+
+	exDate := tr.now.AddDate(1, 0, 0)
+
+	first, err := tr.registry.NotifyDomainTransfer("ClientX", &Domain{
+		Name: "example.se",
+		Transfer: &Transfer{
+			Status:         types.DomainTransferPending,
+			RequestingID:   "ClientY",
+			RequestingDate: tr.now,
+			ActingID:       "ClientX",
+			ActingDate:     tr.now.AddDate(0, 0, 5),
+			ExpireDate:     &exDate,
+		},
+	})
+	require.Nil(t, err)
+
+	tr.now = tr.now.Add(time.Hour)
+
+	second, err := tr.registry.NotifyDomainPendingAction("ClientX", "example.se", true, types.PendingActivationTransactionID{
+		ClientTransactionID: "ABC-12345",
+		ServerTransactionID: "54321-XYZ",
+	})
+	require.Nil(t, err)
+
+	_, err = tr.registry.Notify("ClientY", "Maintenance tonight.", nil)
+	require.Nil(t, err)
+
+	// The oldest message is returned until it is acknowledged.
+	for i := 0; i < 2; i++ {
+		response := tr.send(s, epp.EppOkMessages, pollCommand(types.PollOperationRequest, ""))
+
+		result := types.Response{ResultData: &types.DomainTransferDataType{}}
+		require.Nil(t, epp.Decode(response, &result))
+
+		require.NotNil(t, result.MessageQ)
+		assert.Equal(t, 2, result.MessageQ.Count)
+		assert.Equal(t, msgID(first), result.MessageQ.ID)
+		assert.Equal(t, "Transfer pending.", result.MessageQ.Message.Value)
+		assert.True(t, tr.now.Add(-time.Hour).Equal(*result.MessageQ.QueueDate))
+
+		data := result.ResultData.(*types.DomainTransferDataType).TransferData
+		assert.Equal(t, "example.se", data.Name)
+		assert.Equal(t, types.DomainTransferPending, data.TransferStatus)
+		assert.True(t, exDate.Equal(*data.ExpireDate))
+	}
+
+	// Messages can only be acknowledged by the owning client.
+	tr.send(other, epp.EppObjectDoesNotExist, pollCommand(types.PollOperationAcknowledge, msgID(first)))
+	tr.send(s, epp.EppObjectDoesNotExist, pollCommand(types.PollOperationAcknowledge, "12345"))
+	tr.send(s, epp.EppObjectDoesNotExist, pollCommand(types.PollOperationAcknowledge, "abc"))
+
+	response = tr.send(s, epp.EppOk, pollCommand(types.PollOperationAcknowledge, msgID(first)))
+
+	result = types.Response{}
+	require.Nil(t, epp.Decode(response, &result))
+	assert.Equal(t, 1, result.MessageQ.Count)
+	assert.Equal(t, msgID(first), result.MessageQ.ID)
+
+	response = tr.send(s, epp.EppOkMessages, pollCommand(types.PollOperationRequest, ""))
+
+	pan := types.DomainPendingActivationNotificationDataType{}
+	result = types.Response{ResultData: &pan}
+	require.Nil(t, epp.Decode(response, &result))
+
+	assert.Equal(t, msgID(second), result.MessageQ.ID)
+	assert.Equal(t, 1, result.MessageQ.Count)
+	assert.Equal(t, "example.se", pan.PendingActivationNotificationData.Name.Name)
+	assert.True(t, pan.PendingActivationNotificationData.Name.PendingActivationResult)
+	assert.Equal(t, "54321-XYZ", pan.PendingActivationNotificationData.TransactionID.ServerTransactionID)
+
+	tr.send(s, epp.EppOk, pollCommand(types.PollOperationAcknowledge, msgID(second)))
+	tr.send(s, epp.EppObjectDoesNotExist, pollCommand(types.PollOperationAcknowledge, msgID(second)))
+	tr.send(s, epp.EppOkNoMessages, pollCommand(types.PollOperationRequest, ""))
+
+	// Messages without data have no resData.
+	response = tr.send(other, epp.EppOkMessages, pollCommand(types.PollOperationRequest, ""))
+	assert.NotContains(t, string(response), "resData")
+}
@@ -0,0 +1,418 @@
+// registry 패키지는 Repository 를 사용하여 도메인, 호스트, 연락처 명령어를 모두
+// 처리하는 참조용 레지스트리를 구현합니다. RFC 5731-5733 에 정의된 규칙(관리
+// 클라이언트, 상태에 의한 금지, 개체 간의 연관)을 지키며, 통합 테스트를 위한 로컬
+// 레지스트리로 사용할 수 있습니다.
+//
+//	r := registry.New(registry.NewMemoryRepository())
+//	mux := epp.NewMux()
+//
+//	r.Register(mux)
+//
+//	server := epp.Server{
+//	    SessionConfig: epp.SessionConfig{
+//	        Greeting: r.Greeting,
+//	        Handler:  mux.Handle,
+//	    },
+//	}
+package registry
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/dnssec"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// 명령어를 처리하는 동안 발생한 EPP 결과 코드를 가진 오류입니다.
+// 핸들러가 이 오류를 반환하면 해당 결과 코드와 사유로 응답합니다.
+type Error struct {
+	Code   epp.ResultCode
+	Reason string
+}
+
+// 오류를 문자열로 반환합니다.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d: %s", e.Code.Code(), e.Reason)
+}
+
+// 주어진 결과 코드와 형식화된 사유로 새로운 오류를 생성합니다.
+func errorf(code epp.ResultCode, format string, args ...interface{}) *Error {
+	return &Error{
+		Code:   code,
+		Reason: fmt.Sprintf(format, args...),
+	}
+}
+
+// Repository 를 사용하여 EPP 명령어를 처리하는 레지스트리입니다.
+type Registry struct {
+	// 개체를 저장하는 저장소입니다.
+	Repository Repository
+
+	// 클라이언트에게 전달할 서비스 메시지를 저장하는 큐입니다.
+	Poll PollQueue
+
+	// Greeting 에서 사용되는 서버 ID 입니다.
+	ServerID string
+
+	// ROID 의 접미사입니다. 최대 8자의 영문자와 숫자로 이루어져야 합니다.
+	ROIDSuffix string
+
+	// 로그인 할 때 클라이언트 ID 와 비밀번호를 인증하는 함수입니다.
+	// nil 이면 모든 클라이언트의 로그인을 허용합니다.
+	Authenticate func(clientID, password string) bool
+
+	// 도메인 이전 요청이 서버에서 자동으로 승인되기까지의 기간입니다. 0 이면 이전
+	// 요청을 즉시 승인합니다.
+	TransferPeriod time.Duration
+
+	// 도메인의 유예 기간, 자동 갱신과 삭제 단계를 정하는 정책입니다.
+	Lifecycle LifecyclePolicy
+
+	// 런치 단계의 정책입니다. nil 이면 launch-1.0 확장을 지원하지 않습니다.
+	Launch *Launch
+
+	// 도메인 명령어의 요금을 정하는 엔진입니다. nil 이면 fee-1.0 확장을 지원하지 않습니다.
+	Pricing PricingEngine
+
+	// 로그인 보안 정책입니다. nil 이면 loginSec-1.0 확장을 지원하지 않습니다.
+	LoginSecurity *LoginSecurity
+
+	// 서버에서 바꾼 개체의 서비스 메시지에 바뀌기 전과 후의 개체 정보를 changePoll-1.0
+	// 확장으로 포함합니다.
+	ChangePoll bool
+
+	// 할당 토큰의 저장소입니다. nil 이면 allocationToken-1.0 확장을 지원하지 않습니다.
+	AllocationTokens AllocationTokenStore
+
+	// 안전한 인증 정보 처리 정책입니다. nil 이면 인증 정보를 평문으로 저장하고 관리
+	// 클라이언트의 info 응답으로 반환합니다.
+	SecureAuthInfo *SecureAuthInfo
+
+	// 레지스트리 점검 알림 정책입니다. nil 이면 maintenance-1.0 확장을 지원하지 않습니다.
+	Maintenance *Maintenance
+
+	// keyrelay-1.0 의 keyrelay 명령어로 받은 DNSKEY 를 도메인의 관리 클라이언트에게
+	// 전달합니다.
+	KeyRelay bool
+
+	// 도메인의 DNSSEC 위임 정보를 관리하는 secDNS-1.1 인터페이스입니다. nil 이면
+	// secDNS-1.1 확장을 지원하지 않습니다.
+	DNSSEC *dnssec.Interface
+
+	// 현재 시간을 반환하는 함수입니다. 테스트에서 시간을 고정하기 위해 사용할 수 있습니다.
+	Now func() time.Time
+}
+
+// 주어진 저장소를 사용하는 새로운 레지스트리를 생성합니다.
+// 저장소가 PollQueue 를 구현하면 저장소를 큐로 사용하고, 아니면 메모리 큐를 사용합니다.
+func New(repository Repository) *Registry {
+	poll, ok := repository.(PollQueue)
+	if !ok {
+		poll = NewMemoryPollQueue()
+	}
+
+	return &Registry{
+		Repository:     repository,
+		Poll:           poll,
+		ServerID:       "epp-go registry",
+		ROIDSuffix:     "EPPGO",
+		TransferPeriod: defaultTransferPeriod,
+		Lifecycle:      DefaultLifecyclePolicy(),
+		Now:            time.Now,
+	}
+}
+
+// 레지스트리가 처리하는 모든 명령어의 핸들러를 Mux 에 등록합니다.
+func (r *Registry) Register(m *epp.Mux) {
+	m.AddHandler("hello", r.hello)
+	m.AddHandler("command/login", r.handle(r.login))
+	m.AddHandler("command/logout", r.handle(r.logout))
+	m.AddHandler("command/poll", r.handle(r.poll))
+
+	m.AddHandler("command/check/domain", r.handle(r.checkDomain))
+	m.AddHandler("command/info/domain", r.handle(r.infoDomain))
+	m.AddHandler("command/create/domain", r.handle(r.createDomain))
+	m.AddHandler("command/update/domain", r.handle(r.updateDomain))
+	m.AddHandler("command/delete/domain", r.handle(r.deleteDomain))
+	m.AddHandler("command/renew/domain", r.handle(r.renewDomain))
+	m.AddHandler("command/transfer/domain", r.handle(r.transferDomain))
+
+	m.AddHandler("command/check/domain/launch", r.handle(r.checkDomainExtension))
+	m.AddHandler("command/info/domain/launch", r.handle(r.infoDomainLaunch))
+	m.AddHandler("command/create/domain/launch", r.handle(r.createDomainLaunch))
+	m.AddHandler("command/update/domain/launch", r.handle(r.updateDomainLaunch))
+	m.AddHandler("command/delete/domain/launch", r.handle(r.deleteDomainLaunch))
+
+	m.AddHandler("command/check/domain/fee", r.handle(r.checkDomainExtension))
+
+	m.AddHandler("command/check/host", r.handle(r.checkHost))
+	m.AddHandler("command/info/host", r.handle(r.infoHost))
+	m.AddHandler("command/create/host", r.handle(r.createHost))
+	m.AddHandler("command/update/host", r.handle(r.updateHost))
+	m.AddHandler("command/delete/host", r.handle(r.deleteHost))
+
+	m.AddHandler("command/check/contact", r.handle(r.checkContact))
+	m.AddHandler("command/info/contact", r.handle(r.infoContact))
+	m.AddHandler("command/create/contact", r.handle(r.createContact))
+	m.AddHandler("command/update/contact", r.handle(r.updateContact))
+	m.AddHandler("command/delete/contact", r.handle(r.deleteContact))
+	m.AddHandler("command/transfer/contact", r.handle(r.transferContact))
+
+	m.AddHandler("command/check/org", r.handle(r.checkOrg))
+	m.AddHandler("command/info/org", r.handle(r.infoOrg))
+	m.AddHandler("command/create/org", r.handle(r.createOrg))
+	m.AddHandler("command/update/org", r.handle(r.updateOrg))
+	m.AddHandler("command/delete/org", r.handle(r.deleteOrg))
+
+	m.AddHandler("command/info/maint", r.handle(r.infoMaintenance))
+
+	m.AddHandler("extension/keyrelay", r.handle(r.keyRelay))
+}
+
+// 레지스트리가 지원하는 개체로 greeting 을 생성합니다.
+func (r *Registry) Greeting(s *epp.Session) ([]byte, error) {
+	extensions := []string{
+		types.NameSpaceIIS12,
+		types.NameSpaceRGP10,
+		types.NameSpaceUnhandledNamespaces10,
+	}
+
+	if r.Launch != nil {
+		extensions = append(extensions, types.NameSpaceLaunch10)
+	}
+
+	if r.Pricing != nil {
+		extensions = append(extensions, types.NameSpaceFee10)
+	}
+
+	if r.LoginSecurity != nil {
+		extensions = append(extensions, types.NameSpaceLoginSec10)
+	}
+
+	if r.ChangePoll {
+		extensions = append(extensions, types.NameSpaceChangePoll10)
+	}
+
+	if r.AllocationTokens != nil {
+		extensions = append(extensions, types.NameSpaceAllocationToken10)
+	}
+
+	if r.SecureAuthInfo != nil {
+		extensions = append(extensions, types.NameSpaceSecureAuthInfoTransfer10)
+	}
+
+	if r.Maintenance != nil {
+		extensions = append(extensions, types.NameSpaceMaintenance10)
+	}
+
+	if r.KeyRelay {
+		extensions = append(extensions, types.NameSpaceKeyRelay10)
+	}
+
+	if r.DNSSEC != nil {
+		extensions = append(extensions, types.NameSpaceDNSSEC11)
+	}
+
+	objects := []string{
+		types.NameSpaceDomain,
+		types.NameSpaceContact,
+		types.NameSpaceHost,
+	}
+
+	if _, ok := r.Repository.(OrganizationRepository); ok {
+		objects = append(objects, types.NameSpaceOrg10)
+		extensions = append(extensions, types.NameSpaceOrgExt10)
+	}
+
+	greeting := types.EPPGreeting{
+		Greeting: types.Greeting{
+			ServerID:   r.ServerID,
+			ServerDate: r.Now().UTC(),
+			ServiceMenu: types.ServiceMenu{
+				Version:   []string{"1.0"},
+				Language:  []string{"en"},
+				ObjectURI: objects,
+				ServiceExtension: &types.ServiceExtension{
+					ExtensionURI: extensions,
+				},
+			},
+			DCP: types.DCP{
+				Access: types.DCPAccess{
+					All: types.Empty(),
+				},
+				Statement: types.DCPStatement{
+					Purpose: types.DCPPurpose{
+						Admin: types.Empty(),
+						Prov:  types.Empty(),
+					},
+					Recipient: types.DCPRecipient{
+						Ours:   []types.DCPOurs{{}},
+						Public: types.Empty(),
+					},
+					Retention: types.DCPRetention{
+						Stated: types.Empty(),
+					},
+				},
+			},
+		},
+	}
+
+	return epp.Encode(greeting, epp.ServerXMLAttributes())
+}
+
+func (r *Registry) hello(s *epp.Session, data []byte) ([]byte, error) {
+	return r.Greeting(s)
+}
+
+// 명령어를 처리하고 응답을 만드는 함수입니다.
+type commandFunc func(s *epp.Session, data []byte) (*epp.ResponseBuilder, error)
+
+// 명령어 처리 함수를 Mux 에서 사용할 수 있는 핸들러로 변환합니다.
+// 로그인 외의 명령어는 로그인 된 세션에서만 처리하며, 처리 중 발생한 오류는 EPP
+// 결과 코드를 가진 응답으로 변환됩니다.
+func (r *Registry) handle(f commandFunc) epp.HandlerFunc {
+	return func(s *epp.Session, data []byte) ([]byte, error) {
+		trID := types.ClientTransactionIDType{}
+		extTrID := types.ExtensionClientTransactionIDType{}
+
+		if err := epp.Decode(data, &trID); err != nil {
+			return nil, err
+		}
+
+		// 프로토콜 확장 명령어는 클라이언트 트랜잭션 ID 를 확장 안에 가집니다.
+		if err := epp.Decode(data, &extTrID); err != nil {
+			return nil, err
+		}
+
+		if trID.ClientTransactionID == "" {
+			trID.ClientTransactionID = extTrID.ClientTransactionID
+		}
+
+		var (
+			response *epp.ResponseBuilder
+			err      error
+		)
+
+		if s.ClientID == "" && !isLogin(data) {
+			err = errorf(epp.EppUseError, "command requires a logged in session")
+		} else {
+			response, err = f(s, data)
+		}
+
+		if err != nil {
+			response = errorResponse(err)
+		}
+
+		return response.
+			WithTrID(trID.ClientTransactionID, uuid.New().String()).
+			WithLoginServices(s.Services).
+			Encode()
+	}
+}
+
+// 오류를 결과 코드와 사유를 가진 응답으로 변환합니다. 결과 코드가 없는 오류는
+// 클라이언트에게 내용을 노출하지 않고 2400 으로 응답합니다.
+func errorResponse(err error) *epp.ResponseBuilder {
+	if eppErr, ok := errors.Cause(err).(*Error); ok {
+		return epp.NewResponse(eppErr.Code).WithReason(eppErr.Reason)
+	}
+
+	log.Printf("error while handling command: %s", err.Error())
+
+	return epp.NewResponse(epp.EppCommandFailed)
+}
+
+func isLogin(data []byte) bool {
+	login := types.Login{}
+
+	if err := epp.Decode(data, &login); err != nil {
+		return false
+	}
+
+	return login.ClientID != ""
+}
+
+func (r *Registry) login(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	login := types.Login{}
+
+	if err := epp.Decode(data, &login); err != nil {
+		return nil, errorf(epp.EppSyntaxError, "could not decode login")
+	}
+
+	if s.ClientID != "" {
+		return nil, errorf(epp.EppUseError, "already logged in")
+	}
+
+	if err := r.loginPasswords(&login, data); err != nil {
+		return nil, err
+	}
+
+	if r.Authenticate != nil && !r.Authenticate(login.ClientID, login.Password) {
+		return nil, errorf(epp.EppAuthenticationError, "invalid client id or password")
+	}
+
+	events, ok, err := r.loginSecurityEvents(s, login)
+	if err != nil {
+		return nil, err
+	}
+
+	response := epp.NewResponse(epp.EppOk)
+
+	if ok {
+		s.ClientID = login.ClientID
+		s.Services = &login.Services
+	} else {
+		response = epp.NewResponse(epp.EppAuthenticationError).WithReason(events[0].Message)
+	}
+
+	// 보안 이벤트는 확장을 사용하는 클라이언트에게만 반환합니다.
+	if len(events) > 0 && usesLoginSecurity(login) {
+		response = response.WithExtension(types.LoginSecExtensionDataType{
+			LoginSecData: types.LoginSecData{Event: events},
+		})
+	}
+
+	return response, nil
+}
+
+func (r *Registry) logout(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	s.ClientID = ""
+	s.Services = nil
+
+	return epp.NewResponse(epp.EppOkBye), nil
+}
+
+// 새로운 ROID 를 생성합니다. 개체 종류에 따라 다른 접두사를 사용합니다.
+func (r *Registry) roid(tx Repository, prefix string) (string, error) {
+	id, err := tx.NextID()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%d-%s", prefix, id, r.ROIDSuffix), nil
+}
+
+// 현재 시간을 초 단위까지 UTC 로 반환합니다.
+func (r *Registry) now() time.Time {
+	return r.Now().UTC().Truncate(time.Second)
+}
+
+// 저장소에서 발생한 오류를 EPP 오류로 변환합니다. 개체가 없는 경우 2303 으로 응답합니다.
+func notFound(err error, format string, args ...interface{}) error {
+	if errors.Cause(err) == ErrObjectNotFound {
+		return errorf(epp.EppObjectDoesNotExist, format, args...)
+	}
+
+	return err
+}
+
+// 도메인과 호스트 이름은 대소문자를 구분하지 않으므로 소문자로 저장합니다.
+func normalize(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
@@ -0,0 +1,553 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testRegistry struct {
+	t         *testing.T
+	registry  *Registry
+	mux       *epp.Mux
+	validator *epp.XMLValidator
+	now       time.Time
+}
+
+// 모든 시나리오를 실행할 저장소입니다.
+var testRepositories = map[string]func(t *testing.T) Repository{
+	"memory": func(*testing.T) Repository { return NewMemoryRepository() },
+	"sqlite": newTestSQLRepository,
+}
+
+func newTestRegistry(t *testing.T, repo Repository) *testRegistry {
+	validator, err := epp.NewValidator("../xml/index.xsd")
+	require.Nil(t, err)
+
+	tr := &testRegistry{
+		t:         t,
+		registry:  New(repo),
+		mux:       epp.NewMux(),
+		validator: validator,
+		now:       time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	tr.registry.Now = func() time.Time { return tr.now }
+	tr.registry.Register(tr.mux)
+
+	return tr
+}
+
+// 로그인 된 세션을 생성합니다.
+func (tr *testRegistry) login(clientID string) *epp.Session {
+	s := &epp.Session{}
+
+	tr.send(s, epp.EppOk, types.Login{
+		ClientID: clientID,
+		Password: "secret",
+		Options:  types.LoginOptions{Version: "1.0", Language: "en"},
+		Services: types.LoginServices{ObjectURI: []string{types.NameSpaceDomain}},
+	})
+
+	require.Equal(tr.t, clientID, s.ClientID)
+
+	return s
+}
+
+// 명령어를 확장과 함께 전송하고 응답의 결과 코드를 확인한 후 응답을 반환합니다.
+func (tr *testRegistry) send(s *epp.Session, want epp.ResultCode, command interface{}, extensions ...interface{}) []byte {
+	tr.t.Helper()
+
+	data, err := epp.NewCommand(command).WithExtension(extensions...).WithClientTransactionID("ABC-12345").Encode()
+	require.Nil(tr.t, err)
+	require.Nil(tr.t, tr.validator.Validate(data), string(data))
+
+	response, err := tr.mux.Handle(s, data)
+	require.Nil(tr.t, err)
+	require.Nil(tr.t, tr.validator.Validate(response), string(response))
+
+	result := types.Response{}
+	require.Nil(tr.t, epp.Decode(response, &result))
+
+	if !assert.Equal(tr.t, want.Code(), result.Result[0].Code, string(response)) {
+		tr.t.FailNow()
+	}
+
+	assert.Equal(tr.t, "ABC-12345", result.TransactionID.ClientTransactionID)
+	assert.NotEmpty(tr.t, result.TransactionID.ServerTransactionID)
+
+	return response
+}
+
+// 응답의 resData 를 주어진 타입으로 Decode 합니다.
+func decodeResData(t *testing.T, response []byte, resData interface{}) {
+	require.Nil(t, epp.Decode(response, &types.Response{ResultData: resData}))
+}
+
+func contactCreate(id string) types.ContactCreateType {
+	return types.ContactCreateType{
+		Create: types.ContactCreate{
+			ID: id,
+			PostalInfo: []types.PostalInfo{
+				{
+					Name:    "John Doe",
+					Address: types.Address{City: "Stockholm", CountryCode: "SE"},
+					Type:    types.PostalInfoInternational,
+				},
+			},
+			Email:    "jdoe@example.se",
+			AuthInfo: types.AuthInfo{Password: "2fooBAR"},
+		},
+	}
+}
+
+func hostCreate(name string, addresses ...string) types.HostCreateType {
+	h := types.HostCreateType{Create: types.HostCreate{Name: name}}
+
+	for _, a := range addresses {
+		h.Create.Address = append(h.Create.Address, types.HostAddress{Address: a})
+	}
+
+	return h
+}
+
+func domainCreate(name string, hosts ...string) types.DomainCreateType {
+	d := types.DomainCreateType{
+		Create: types.DomainCreate{
+			Name:       name,
+			Period:     &types.Period{Value: 2, Unit: "y"},
+			Registrant: "jd1234",
+			Contacts:   []types.Contact{{Name: "jd1234", Type: "admin"}},
+			AuthInfo:   &types.AuthInfo{Password: "2fooBAR"},
+		},
+	}
+
+	if len(hosts) > 0 {
+		d.Create.NameServer = &types.NameServer{HostObject: hosts}
+	}
+
+	return d
+}
+
+func TestRegistry(t *testing.T) {
+	scenarios := map[string]func(t *testing.T, tr *testRegistry){
+		"requiresLogin":       testRegistryRequiresLogin,
+		"domainLifecycle":     testRegistryDomainLifecycle,
+		"renewDomain":         testRegistryRenewDomain,
+		"transfer":            testRegistryTransfer,
+		"updateHost":          testRegistryUpdateHost,
+		"poll":                testRegistryPoll,
+		"transferFlow":        testRegistryTransferWorkflow,
+		"domainExpiry":        testRegistryDomainExpiry,
+		"gracePeriods":        testRegistryDomainGracePeriods,
+		"restore":             testRegistryRestore,
+		"launch":              testRegistryLaunch,
+		"applications":        testRegistryLaunchApplications,
+		"fee":                 testRegistryFee,
+		"loginSecurity":       testRegistryLoginSecurity,
+		"changePoll":          testRegistryChangePoll,
+		"allocationToken":     testRegistryAllocationToken,
+		"organization":        testRegistryOrganization,
+		"secureAuthInfo":      testRegistrySecureAuthInfo,
+		"unhandledNamespaces": testRegistryUnhandledNamespaces,
+		"maintenance":         testRegistryMaintenance,
+		"keyRelay":            testRegistryKeyRelay,
+		"dnssec":              testRegistryDNSSEC,
+	}
+
+	for repoName, newRepository := range testRepositories {
+		for name, scenario := range scenarios {
+			newRepository, scenario := newRepository, scenario
+
+			t.Run(repoName+"/"+name, func(t *testing.T) {
+				scenario(t, newTestRegistry(t, newRepository(t)))
+			})
+		}
+	}
+}
+
+func testRegistryRequiresLogin(t *testing.T, tr *testRegistry) {
+
+	tr.send(&epp.Session{}, epp.EppUseError, types.DomainCheckType{
+		Check: types.DomainCheck{Names: []string{"example.se"}},
+	})
+
+	s := tr.login("ClientX")
+	tr.send(s, epp.EppOkBye, types.Logout{Logout: types.Empty()})
+
+	assert.Empty(t, s.ClientID)
+}
+
+func testRegistryDomainLifecycle(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppObjectExists, contactCreate("jd1234"))
+
+	// Host with address but without a superordinate domain in the registry.
+	tr.send(s, epp.EppParamPolicyError, hostCreate("ns1.example.net", "192.0.2.1"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.net"))
+
+	tr.send(s, epp.EppObjectDoesNotExist, domainCreate("example.se", "ns2.example.net"))
+	tr.send(s, epp.EppOk, domainCreate("Example.SE", "ns1.example.net"))
+	tr.send(s, epp.EppObjectExists, domainCreate("example.se"))
+
+	check := types.DomainChekDataType{}
+
+	response := tr.send(s, epp.EppOk, types.DomainCheckType{
+		Check: types.DomainCheck{Names: []string{"example.se", "example2.se"}},
+	})
+
+	decodeResData(t, response, &check)
+	assert.False(t, check.CheckData.CheckDomain[0].Name.Available)
+	assert.True(t, check.CheckData.CheckDomain[1].Name.Available)
+
+	// Internal hosts must have an address and be sponsored by the domain sponsor.
+	tr.send(s, epp.EppParamPolicyError, hostCreate("ns1.example.se"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.se", "192.0.2.2", "2001:db8::1"))
+
+	info := types.DomainInfoDataType{}
+
+	response = tr.send(s, epp.EppOk, types.DomainInfoType{
+		Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}},
+	})
+
+	decodeResData(t, response, &info)
+	assert.Regexp(t, `^D\d+-EPPGO$`, info.InfoData.ROID)
+	assert.Equal(t, []string{"ns1.example.net"}, info.InfoData.NameServer.HostObject)
+	assert.Equal(t, []string{"ns1.example.se"}, info.InfoData.Host)
+	assert.Equal(t, "2fooBAR", info.InfoData.AuthInfo.Password)
+	assert.Equal(t, time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC), *info.InfoData.ExpireDate)
+
+	// Other clients only get limited information without the authorization info.
+	other := tr.login("ClientY")
+
+	info = types.DomainInfoDataType{}
+
+	response = tr.send(other, epp.EppOk, types.DomainInfoType{
+		Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}},
+	})
+
+	decodeResData(t, response, &info)
+	assert.Nil(t, info.InfoData.NameServer)
+	assert.Nil(t, info.InfoData.AuthInfo)
+
+	tr.send(other, epp.EppAuthorisationError, types.DomainDeleteType{Delete: types.DomainDelete{Name: "example.se"}})
+	tr.send(other, epp.EppAuthorisationError, hostCreate("ns2.example.se", "192.0.2.3"))
+
+	// Linked objects can not be deleted.
+	tr.send(s, epp.EppAssocProhibitsOp, types.HostDeleteType{Delete: types.HostDelete{Name: "ns1.example.net"}})
+	tr.send(s, epp.EppAssocProhibitsOp, types.ContactDeleteType{Delete: types.ContactDelete{Name: "jd1234"}})
+	tr.send(s, epp.EppAssocProhibitsOp, types.DomainDeleteType{Delete: types.DomainDelete{Name: "example.se"}})
+
+	// Status prohibits delete until it's removed.
+	tr.send(s, epp.EppOk, types.DomainUpdateType{
+		Update: types.DomainUpdate{
+			Name: "example.se",
+			Add: &types.DomainAddRemove{
+				Status: []types.DomainStatus{{DomainStatusType: types.DomainStatusClientDeleteProhibited}},
+			},
+			Remove: &types.DomainAddRemove{
+				NameServer: &types.NameServer{HostObject: []string{"ns1.example.net"}},
+			},
+		},
+	})
+
+	tr.send(s, epp.EppParamPolicyError, types.DomainUpdateType{
+		Update: types.DomainUpdate{
+			Name: "example.se",
+			Add: &types.DomainAddRemove{
+				Status: []types.DomainStatus{{DomainStatusType: types.DomainStatusServerHold}},
+			},
+		},
+	})
+
+	tr.send(s, epp.EppOk, types.HostDeleteType{Delete: types.HostDelete{Name: "ns1.example.se"}})
+	tr.send(s, epp.EppOk, types.HostDeleteType{Delete: types.HostDelete{Name: "ns1.example.net"}})
+	tr.send(s, epp.EppStatusProhibitsOp, types.DomainDeleteType{Delete: types.DomainDelete{Name: "example.se"}})
+
+	tr.send(s, epp.EppOk, types.DomainUpdateType{
+		Update: types.DomainUpdate{
+			Name: "example.se",
+			Remove: &types.DomainAddRemove{
+				Status: []types.DomainStatus{{DomainStatusType: types.DomainStatusClientDeleteProhibited}},
+			},
+		},
+	})
+
+	tr.send(s, epp.EppOk, types.DomainDeleteType{Delete: types.DomainDelete{Name: "example.se"}})
+	tr.send(s, epp.EppObjectDoesNotExist, types.DomainDeleteType{Delete: types.DomainDelete{Name: "example.se"}})
+	tr.send(s, epp.EppOk, types.ContactDeleteType{Delete: types.ContactDelete{Name: "jd1234"}})
+}
+
+func testRegistryRenewDomain(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+
+	renew := func(expireDate time.Time, years int) types.DomainRenewType {
+		return types.DomainRenewType{
+			Renew: types.DomainRenew{
+				Name:       "example.se",
+				ExpireDate: types.Date{Time: expireDate},
+				Period:     &types.Period{Value: years, Unit: "y"},
+			},
+		}
+	}
+
+	tr.send(s, epp.EppParamPolicyError, renew(time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC), 1))
+	tr.send(s, epp.EppParamPolicyError, renew(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), 9))
+
+	result := types.DomainRenewDataType{}
+
+	response := tr.send(s, epp.EppOk, renew(time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC), 3))
+
+	decodeResData(t, response, &result)
+	assert.Equal(t, time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), result.RenewData.ExpireDate)
+}
+
+func testRegistryTransfer(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.se", "192.0.2.1"))
+
+	transfer := func(op types.TransferOperation, password string) types.DomainTransferType {
+		cmd := types.DomainTransferType{
+			Transfer: types.DomainTransferCommand{
+				Operation: op,
+				Domain:    types.DomainTransfer{Name: "example.se"},
+			},
+		}
+
+		if password != "" {
+			cmd.Transfer.Domain.Authinfo = &types.AuthInfo{Password: password}
+		}
+
+		return cmd
+	}
+
+	tr.send(other, epp.EppObjectNotPendingTransfer, transfer(types.TransferOperationQuery, "2fooBAR"))
+	tr.send(other, epp.EppInvalidAuthInfo, transfer(types.TransferOperationRequest, "wrong"))
+	tr.send(s, epp.EppNotTransferrable, transfer(types.TransferOperationRequest, "2fooBAR"))
+	tr.send(other, epp.EppOkPending, transfer(types.TransferOperationRequest, "2fooBAR"))
+	tr.send(other, epp.EppAuthorisationError, transfer(types.TransferOperationApprove, ""))
+	tr.send(s, epp.EppOk, transfer(types.TransferOperationApprove, ""))
+	tr.send(s, epp.EppObjectNotPendingTransfer, transfer(types.TransferOperationApprove, ""))
+
+	result := types.DomainTransferDataType{}
+
+	response := tr.send(s, epp.EppOk, transfer(types.TransferOperationQuery, ""))
+
+	decodeResData(t, response, &result)
+	assert.Equal(t, types.DomainTransferClientApproved, result.TransferData.TransferStatus)
+	assert.Equal(t, "ClientY", result.TransferData.RequestingID)
+	assert.Equal(t, "ClientX", result.TransferData.ActingID)
+
+	// The subordinate host follows the domain.
+	tr.send(s, epp.EppAuthorisationError, types.HostDeleteType{Delete: types.HostDelete{Name: "ns1.example.se"}})
+	tr.send(other, epp.EppOk, types.HostDeleteType{Delete: types.HostDelete{Name: "ns1.example.se"}})
+
+	// Contacts are transferred the same way.
+	tr.send(other, epp.EppAuthorisationError, types.ContactInfoType{Info: types.ContactInfo{Name: "jd1234"}})
+	tr.send(other, epp.EppOk, types.ContactTransferType{
+		Transfer: types.ContactTransferCommand{
+			Operation: types.TransferOperationRequest,
+			Contact: types.ContactTransfer{
+				Name:     "jd1234",
+				AuthInfo: &types.AuthInfo{Password: "2fooBAR"},
+			},
+		},
+	})
+	tr.send(other, epp.EppOk, types.ContactInfoType{Info: types.ContactInfo{Name: "jd1234"}})
+}
+
+func testRegistryUpdateHost(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.se", "192.0.2.1"))
+	tr.send(s, epp.EppOk, domainCreate("example2.se", "ns1.example.se"))
+
+	tr.send(s, epp.EppOk, types.HostUpdateType{
+		Update: types.HostUpdate{
+			Name: "ns1.example.se",
+			Add: &types.HostAddRemove{
+				Address: []types.HostAddress{{Address: "2001:db8::1", IP: types.HostIPv6}},
+				Status:  []types.HostStatus{{HostStatusType: types.HostStatusClientUpdateProhibited}},
+			},
+		},
+	})
+
+	tr.send(s, epp.EppStatusProhibitsOp, types.HostUpdateType{
+		Update: types.HostUpdate{
+			Name:   "ns1.example.se",
+			Change: &types.HostChange{Name: "ns2.example.se"},
+		},
+	})
+
+	tr.send(s, epp.EppOk, types.HostUpdateType{
+		Update: types.HostUpdate{
+			Name: "ns1.example.se",
+			Remove: &types.HostAddRemove{
+				Status: []types.HostStatus{{HostStatusType: types.HostStatusClientUpdateProhibited}},
+			},
+			Change: &types.HostChange{Name: "ns2.example.se"},
+		},
+	})
+
+	info := types.DomainInfoDataType{}
+
+	response := tr.send(s, epp.EppOk, types.DomainInfoType{
+		Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example2.se"}},
+	})
+
+	decodeResData(t, response, &info)
+	assert.Equal(t, []string{"ns2.example.se"}, info.InfoData.NameServer.HostObject)
+
+	hostInfo := types.HostInfoDataType{}
+
+	response = tr.send(s, epp.EppOk, types.HostInfoType{Info: types.HostInfo{Name: "ns2.example.se"}})
+
+	decodeResData(t, response, &hostInfo)
+	assert.Len(t, hostInfo.InfoData.Address, 2)
+	assert.Equal(t, []types.HostStatus{
+		{HostStatusType: types.HostStatusOk},
+		{HostStatusType: types.HostStatusLinked},
+	}, hostInfo.InfoData.Status)
+}
+
+func TestMemoryRepository_Transaction(t *testing.T) {
+	repo := NewMemoryRepository()
+
+	require.Nil(t, repo.CreateDomain(&Domain{Name: "example.se", ClientID: "ClientX"}))
+
+	err := repo.Transaction(func(tx Repository) error {
+		d, err := tx.Domain("example.se")
+		require.Nil(t, err)
+
+		d.ClientID = "ClientY"
+
+		require.Nil(t, tx.UpdateDomain(d))
+		require.Nil(t, tx.DeleteDomain("example.se"))
+		require.Nil(t, tx.CreateHost(&Host{Name: "ns1.example.se"}))
+
+		return errorf(epp.EppCommandFailed, "rollback")
+	})
+
+	require.NotNil(t, err)
+
+	d, err := repo.Domain("example.se")
+	require.Nil(t, err)
+	assert.Equal(t, "ClientX", d.ClientID)
+
+	_, err = repo.Host("ns1.example.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+}
+
+func TestMemoryRepository_TransactionConcurrentWrite(t *testing.T) {
+	repo := NewMemoryRepository()
+	started := make(chan struct{})
+	done := make(chan error)
+
+	go func() {
+		done <- repo.Transaction(func(tx Repository) error {
+			assert.Nil(t, tx.CreateDomain(&Domain{Name: "rollback.se"}))
+
+			// Nested transactions run in the same transaction.
+			assert.Nil(t, tx.Transaction(func(tx Repository) error {
+				return tx.CreateDomain(&Domain{Name: "nested.se"})
+			}))
+
+			close(started)
+
+			// Give the write outside the transaction time to run.
+			time.Sleep(50 * time.Millisecond)
+
+			return errorf(epp.EppCommandFailed, "rollback")
+		})
+	}()
+
+	<-started
+
+	// Writes outside the transaction wait for it to finish so they're not
+	// lost when the transaction is rolled back.
+	require.Nil(t, repo.CreateDomain(&Domain{Name: "outside.se"}))
+	assert.NotNil(t, <-done)
+
+	for _, name := range []string{"rollback.se", "nested.se"} {
+		_, err := repo.Domain(name)
+		assert.Equal(t, ErrObjectNotFound, err, name)
+	}
+
+	_, err := repo.Domain("outside.se")
+	assert.Nil(t, err)
+}
+
+// 개체를 찾을 수 없을 때 ErrObjectNotFound 를 감싸서 반환하는 저장소입니다.
+type wrappedNotFoundRepository struct {
+	*MemoryRepository
+}
+
+func (w wrappedNotFoundRepository) Transaction(fn func(tx Repository) error) error {
+	return w.MemoryRepository.Transaction(func(tx Repository) error {
+		return fn(wrappedNotFoundRepository{tx.(*MemoryRepository)})
+	})
+}
+
+func (w wrappedNotFoundRepository) Domain(name string) (*Domain, error) {
+	d, err := w.MemoryRepository.Domain(name)
+	return d, errors.Wrapf(err, "domain %s", name)
+}
+
+func (w wrappedNotFoundRepository) Host(name string) (*Host, error) {
+	h, err := w.MemoryRepository.Host(name)
+	return h, errors.Wrapf(err, "host %s", name)
+}
+
+func (w wrappedNotFoundRepository) Contact(id string) (*Contact, error) {
+	c, err := w.MemoryRepository.Contact(id)
+	return c, errors.Wrapf(err, "contact %s", id)
+}
+
+func TestRegistry_wrappedNotFound(t *testing.T) {
+	tr := newTestRegistry(t, wrappedNotFoundRepository{NewMemoryRepository()})
+	s := tr.login("ClientX")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.net"))
+	tr.send(s, epp.EppOk, domainCreate("example.se", "ns1.example.net"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.se", "192.0.2.1"))
+
+	check := types.DomainChekDataType{}
+	decodeResData(t, tr.send(s, epp.EppOk, types.DomainCheckType{
+		Check: types.DomainCheck{Names: []string{"example.se", "example.nu"}},
+	}), &check)
+
+	assert.False(t, check.CheckData.CheckDomain[0].Name.Available)
+	assert.True(t, check.CheckData.CheckDomain[1].Name.Available)
+
+	// Applications are allocated when the name is free.
+	tr.registry.Launch = &Launch{
+		Phases: []LaunchPhase{{Phase: launchPhase(types.LaunchPhaseSunrise), Start: tr.now, Applications: true}},
+	}
+
+	application := launchCreate(types.LaunchPhaseSunrise, "c21k")
+	application.Create.Type = types.LaunchObjectApplication
+
+	created := types.LaunchExtensionCreateDataType{}
+	response := tr.send(s, epp.EppOkPending, domainCreate("example.nu"), application)
+	require.Nil(t, epp.Decode(response, &types.Response{Extension: &created}))
+
+	require.Nil(t, tr.registry.SetApplicationStatus(created.CreateData.ApplicationID, types.LaunchStatusAllocated))
+	assert.Equal(t, "ClientX", tr.domain("example.nu").ClientID)
+}
@@ -0,0 +1,255 @@
+package registry
+
+import (
+	"time"
+
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 저장소에서 개체를 찾을 수 없을 때 반환되는 오류입니다.
+var ErrObjectNotFound = errors.New("object not found")
+
+// 레지스트리의 개체를 저장하는 저장소입니다. 저장소는 저장 방식에 상관없이 개체를
+// 저장하고 조회하는 것만 담당하며 RFC 5731-5733 의 규칙은 Registry 에서 처리합니다.
+// 개체를 찾을 수 없는 경우 ErrObjectNotFound 를 반환해야 합니다.
+// 도메인과 호스트 이름은 항상 소문자로 전달됩니다.
+type Repository interface {
+	// 하나의 트랜잭션 안에서 함수를 실행합니다. 함수가 오류를 반환하면 함수
+	// 안에서 변경된 모든 내용은 취소됩니다. 함수에 전달된 저장소만 사용해야 합니다.
+	Transaction(fn func(tx Repository) error) error
+
+	// ROID 를 생성하기 위한 고유한 숫자를 반환합니다.
+	NextID() (int64, error)
+
+	Domain(name string) (*Domain, error)
+	CreateDomain(d *Domain) error
+	UpdateDomain(d *Domain) error
+	DeleteDomain(name string) error
+
+	// 주어진 호스트를 네임서버로 사용하는 도메인 이름들을 반환합니다.
+	DomainsByHost(name string) ([]string, error)
+
+	// 주어진 연락처를 등록자 또는 연락처로 사용하는 도메인 이름들을 반환합니다.
+	DomainsByContact(id string) ([]string, error)
+
+	// 주어진 상태를 가진 도메인 이름들을 반환합니다.
+	DomainsByStatus(status types.DomainStatusType) ([]string, error)
+
+	// 만료일이 주어진 시간과 같거나 이전인 도메인 이름들을 반환합니다.
+	DomainsExpiring(before time.Time) ([]string, error)
+
+	Host(name string) (*Host, error)
+	CreateHost(h *Host) error
+
+	// 호스트를 갱신합니다. 호스트의 이름이 바뀔 수 있으므로 기존 이름을 같이 전달합니다.
+	UpdateHost(name string, h *Host) error
+	DeleteHost(name string) error
+
+	// 주어진 도메인에 종속된 호스트 이름들을 반환합니다.
+	HostsBySuperordinate(domain string) ([]string, error)
+
+	Contact(id string) (*Contact, error)
+	CreateContact(c *Contact) error
+	UpdateContact(c *Contact) error
+	DeleteContact(id string) error
+
+	Application(id string) (*Application, error)
+	CreateApplication(a *Application) error
+	UpdateApplication(a *Application) error
+	DeleteApplication(id string) error
+}
+
+// 등록된 도메인입니다.
+type Domain struct {
+	Name         string
+	ROID         string
+	Status       []types.DomainStatusType
+	Registrant   string
+	Contacts     []types.Contact
+	Hosts        []string
+	ClientID     string
+	CreateID     string
+	CreateDate   time.Time
+	UpdateID     string
+	UpdateDate   *time.Time
+	ExpireDate   time.Time
+	TransferDate *time.Time
+	AuthInfo     string
+	Transfer     *Transfer
+
+	// 인증 정보가 설정된 시간입니다. SecureAuthInfo 의 TTL 을 확인하는데 사용합니다.
+	AuthInfoDate *time.Time
+
+	// 도메인에 적용된 유예 기간과 삭제 단계입니다.
+	GracePeriods []GracePeriod
+
+	// 도메인이 삭제되어 복구 기간에 들어간 시간입니다. 삭제되지 않은 도메인은 nil 입니다.
+	DeleteDate *time.Time
+
+	// orgext 확장으로 도메인에 역할별로 연결된 조직입니다.
+	Organizations []types.OrgExtensionID
+
+	// secDNS-1.1 확장으로 등록된 DNSSEC 위임 정보입니다. 등록된 정보가 없으면 nil
+	// 입니다.
+	DNSSEC *types.DNSSECOrKeyData
+}
+
+// 등록된 호스트입니다. 종속된 도메인이 없는 외부 호스트는 Superordinate 가 빈
+// 문자열입니다.
+type Host struct {
+	Name          string
+	ROID          string
+	Status        []types.HostStatusType
+	Addresses     []types.HostAddress
+	Superordinate string
+	ClientID      string
+	CreateID      string
+	CreateDate    time.Time
+	UpdateID      string
+	UpdateDate    *time.Time
+	TransferDate  *time.Time
+}
+
+// 등록된 연락처입니다.
+type Contact struct {
+	ID           string
+	ROID         string
+	Status       []types.ContactStatusType
+	PostalInfo   []types.PostalInfo
+	Voice        *types.E164Type
+	Fax          *types.E164Type
+	Email        string
+	ClientID     string
+	CreateID     string
+	CreateDate   time.Time
+	UpdateID     string
+	UpdateDate   *time.Time
+	TransferDate *time.Time
+	AuthInfo     string
+	Disclose     *types.Disclose
+	Transfer     *Transfer
+
+	// orgext 확장으로 연락처에 역할별로 연결된 조직입니다.
+	Organizations []types.OrgExtensionID
+}
+
+// 런치 단계에서 접수된 도메인 신청입니다. 신청이 할당되면 Domain 으로 도메인이
+// 등록됩니다.
+type Application struct {
+	ID         string
+	Phase      types.LaunchPhase
+	Status     types.LaunchStatusType
+	Domain     Domain
+	Months     int
+	Marks      []types.Mark
+	ClientID   string
+	CreateDate time.Time
+	UpdateID   string
+	UpdateDate *time.Time
+
+	// 신청할 때 확인된 할당 토큰입니다. 토큰은 신청이 할당될 때 사용되며, 토큰 없이
+	// 신청했으면 빈 문자열입니다.
+	AllocationToken string
+}
+
+// 개체의 가장 최근 이전 요청입니다. 도메인과 연락처의 이전 상태 값은 같으므로
+// 도메인의 이전 상태 타입을 같이 사용합니다.
+type Transfer struct {
+	Status         types.DomainTransferStatusType
+	RequestingID   string
+	RequestingDate time.Time
+	ActingID       string
+	ActingDate     time.Time
+	ExpireDate     *time.Time
+}
+
+func (d *Domain) hasStatus(status ...types.DomainStatusType) bool {
+	for _, s := range d.Status {
+		for _, want := range status {
+			if s == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (h *Host) hasStatus(status ...types.HostStatusType) bool {
+	for _, s := range h.Status {
+		for _, want := range status {
+			if s == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (c *Contact) hasStatus(status ...types.ContactStatusType) bool {
+	for _, s := range c.Status {
+		for _, want := range status {
+			if s == want {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// 저장소가 반환한 개체를 수정해도 저장된 개체가 바뀌지 않도록 복사합니다.
+func (d *Domain) copy() *Domain {
+	c := *d
+	c.Status = append([]types.DomainStatusType(nil), d.Status...)
+	c.Contacts = append([]types.Contact(nil), d.Contacts...)
+	c.Hosts = append([]string(nil), d.Hosts...)
+	c.GracePeriods = append([]GracePeriod(nil), d.GracePeriods...)
+	c.Organizations = append([]types.OrgExtensionID(nil), d.Organizations...)
+
+	if d.Transfer != nil {
+		t := *d.Transfer
+		c.Transfer = &t
+	}
+
+	if d.DNSSEC != nil {
+		sec := *d.DNSSEC
+		sec.DNSSECData = append([]types.DNSSEC(nil), d.DNSSEC.DNSSECData...)
+		sec.KeyData = append([]types.DNSSECKeyData(nil), d.DNSSEC.KeyData...)
+		c.DNSSEC = &sec
+	}
+
+	return &c
+}
+
+func (h *Host) copy() *Host {
+	c := *h
+	c.Status = append([]types.HostStatusType(nil), h.Status...)
+	c.Addresses = append([]types.HostAddress(nil), h.Addresses...)
+
+	return &c
+}
+
+func (c *Contact) copy() *Contact {
+	n := *c
+	n.Status = append([]types.ContactStatusType(nil), c.Status...)
+	n.PostalInfo = append([]types.PostalInfo(nil), c.PostalInfo...)
+	n.Organizations = append([]types.OrgExtensionID(nil), c.Organizations...)
+
+	if c.Transfer != nil {
+		t := *c.Transfer
+		n.Transfer = &t
+	}
+
+	return &n
+}
+
+func (a *Application) copy() *Application {
+	c := *a
+	c.Domain = *a.Domain.copy()
+	c.Marks = append([]types.Mark(nil), a.Marks...)
+
+	return &c
+}
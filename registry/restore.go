@@ -0,0 +1,115 @@
+package registry
+
+import (
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+)
+
+// 복구 요청 후 복구 보고서를 받을 때까지 기다리는 기본 기간입니다. (RFC 3915 3.2)
+const defaultPendingRestorePeriod = 7 * 24 * time.Hour
+
+// rgp-1.0 확장으로 받은 복구 명령어를 처리합니다. 복구 기간의 도메인은 복구 요청으로
+// pendingRestore 단계가 되고, 복구 보고서를 받으면 복구됩니다. 보고서를 받지 못하고
+// PendingRestorePeriod 가 지나면 도메인은 복구 기간으로 돌아갑니다.
+func (r *Registry) restoreDomain(s *epp.Session, name string, restore types.RGPRestore) (*epp.ResponseBuilder, error) {
+	var status []types.RGPStatus
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		d, err := r.sponsoredDomain(tx, s, name)
+		if err != nil {
+			return err
+		}
+
+		switch restore.Operation {
+		case types.RGPRestoreRequest:
+			if d.gracePeriod(GraceRedemption) == nil || d.gracePeriod(GracePendingRestore) != nil {
+				return errorf(epp.EppStatusProhibitsOp, "domain %s is not in the redemption period", name)
+			}
+
+			if r.Lifecycle.PendingRestorePeriod <= 0 {
+				r.completeRestore(d)
+				break
+			}
+
+			d.GracePeriods = append(d.GracePeriods, GracePeriod{
+				Status:  GracePendingRestore,
+				EndDate: r.now().Add(r.Lifecycle.PendingRestorePeriod),
+			})
+
+			status = r.rgpStatus(d)
+		case types.RGPRestoreReport:
+			if d.gracePeriod(GracePendingRestore) == nil {
+				return errorf(epp.EppStatusProhibitsOp, "domain %s has no pending restore", name)
+			}
+
+			if restore.Report == nil {
+				return errorf(epp.EppMissingParam, "restore report is required")
+			}
+
+			r.completeRestore(d)
+		default:
+			return errorf(epp.EppParamPolicyError, "unknown restore operation %s", restore.Operation)
+		}
+
+		now := r.now()
+		d.UpdateID = s.ClientID
+		d.UpdateDate = &now
+
+		return tx.UpdateDomain(d)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := epp.NewResponse(epp.EppOk)
+
+	if len(status) > 0 {
+		response = response.WithExtension(types.RGPExtensionUpdateDataType{
+			UpdateData: types.RGPResponseData{Status: status},
+		})
+	}
+
+	return response, nil
+}
+
+// 도메인의 삭제를 취소합니다. 복구 기간 동안 만료된 도메인은 만료일이 지나지 않도록
+// 1년씩 갱신됩니다.
+func (r *Registry) completeRestore(d *Domain) {
+	now := r.now()
+
+	if i := indexOfDomainStatus(d.Status, types.DomainStatusPendingDelete); i >= 0 {
+		d.Status = append(d.Status[:i:i], d.Status[i+1:]...)
+	}
+
+	for !d.ExpireDate.After(now) {
+		d.ExpireDate = d.ExpireDate.AddDate(1, 0, 0)
+	}
+
+	d.GracePeriods = nil
+	d.DeleteDate = nil
+}
+
+// 도메인의 현재 유예 기간을 rgpStatus 로 반환합니다. 복구 요청중인 도메인은
+// pendingRestore 만 반환합니다.
+func (r *Registry) rgpStatus(d *Domain) []types.RGPStatus {
+	if d.gracePeriod(GracePendingRestore) != nil {
+		return []types.RGPStatus{{Status: types.RGPStatusPendingRestore}}
+	}
+
+	now := r.now()
+	status := []types.RGPStatus{}
+
+	for _, g := range d.GracePeriods {
+		// 복구 기간과 pendingDelete 단계는 엔진이 처리할 때까지 유지됩니다.
+		if g.Status != GraceRedemption && g.Status != GracePendingDelete && !now.Before(g.EndDate) {
+			continue
+		}
+
+		status = append(status, types.RGPStatus{Status: types.RGPStatusType(g.Status)})
+	}
+
+	return status
+}
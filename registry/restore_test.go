@@ -0,0 +1,136 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func restore(op types.RGPRestoreOperation, report *types.RGPReport) types.RGPExtensionUpdateType {
+	return types.RGPExtensionUpdateType{
+		Update: types.RGPUpdate{
+			Restore: types.RGPRestore{Operation: op, Report: report},
+		},
+	}
+}
+
+// 응답의 extension 에서 rgpStatus 를 반환합니다.
+func rgpStatus(t *testing.T, response []byte) []types.RGPStatusType {
+	ext := struct {
+		types.RGPExtensionInfoDataType
+		types.RGPExtensionUpdateDataType
+	}{}
+
+	require.Nil(t, epp.Decode(response, &types.Response{Extension: &ext}))
+
+	status := []types.RGPStatusType{}
+
+	for _, s := range append(ext.InfoData.Status, ext.UpdateData.Status...) {
+		status = append(status, s.Status)
+	}
+
+	return status
+}
+
+func testRegistryRestore(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+	day := 24 * time.Hour
+
+	info := func() []types.RGPStatusType {
+		return rgpStatus(t, tr.send(s, epp.EppOk, types.DomainInfoType{
+			Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}},
+		}))
+	}
+
+	// Restore commands are sent as an update with an empty change.
+	update := types.DomainUpdateType{
+		Update: types.DomainUpdate{Name: "example.se", Change: &types.DomainChange{}},
+	}
+
+	report := &types.RGPReport{
+		PreData:       "Pre-delete registration data.",
+		PostData:      "Post-restore registration data.",
+		DeleteTime:    tr.now,
+		RestoreTime:   tr.now,
+		RestoreReason: types.RGPReportText{Value: "Registrant error."},
+		Statement: []types.RGPReportText{
+			{Value: "This registrar has not restored the name for itself."},
+			{Value: "The information in this report is true."},
+		},
+	}
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+
+	expireDate := tr.now.AddDate(2, 0, 0)
+	assert.Equal(t, []types.RGPStatusType{types.RGPStatusAddPeriod}, info())
+
+	tr.now = tr.now.Add(6 * day)
+	assert.Empty(t, info())
+
+	tr.send(s, epp.EppStatusProhibitsOp, update, restore(types.RGPRestoreRequest, nil))
+	tr.send(s, epp.EppOkPending, domainDelete("example.se"))
+	assert.Equal(t, []types.RGPStatusType{types.RGPStatusRedemptionPeriod}, info())
+
+	// A report is only accepted after a restore request by the sponsoring client.
+	tr.send(s, epp.EppStatusProhibitsOp, update, restore(types.RGPRestoreReport, report))
+	tr.send(other, epp.EppAuthorisationError, update, restore(types.RGPRestoreRequest, nil))
+
+	response := tr.send(s, epp.EppOk, update, restore(types.RGPRestoreRequest, nil))
+	assert.Equal(t, []types.RGPStatusType{types.RGPStatusPendingRestore}, rgpStatus(t, response))
+	assert.Equal(t, []types.RGPStatusType{types.RGPStatusPendingRestore}, info())
+
+	tr.send(s, epp.EppStatusProhibitsOp, update, restore(types.RGPRestoreRequest, nil))
+	tr.send(s, epp.EppMissingParam, update, restore(types.RGPRestoreReport, nil))
+
+	// The report restores the domain.
+	tr.now = tr.now.Add(day)
+	tr.send(s, epp.EppOk, update, restore(types.RGPRestoreReport, report))
+
+	d := tr.domain("example.se")
+	assert.False(t, d.hasStatus(types.DomainStatusPendingDelete))
+	assert.Empty(t, d.GracePeriods)
+	assert.Nil(t, d.DeleteDate)
+	assert.True(t, expireDate.Equal(d.ExpireDate))
+	assert.Empty(t, info())
+
+	// Without a report the domain returns to the redemption period.
+	tr.send(s, epp.EppOkPending, domainDelete("example.se"))
+	tr.send(s, epp.EppOk, update, restore(types.RGPRestoreRequest, nil))
+
+	// The pending restore period is removed by its status, not its position.
+	d = tr.domain("example.se")
+	d.GracePeriods[0], d.GracePeriods[1] = d.GracePeriods[1], d.GracePeriods[0]
+	require.Equal(t, GracePendingRestore, d.GracePeriods[0].Status)
+	require.Nil(t, tr.registry.Repository.UpdateDomain(d))
+
+	tr.now = tr.now.Add(7 * day)
+	require.Nil(t, tr.registry.ProcessLifecycle())
+
+	assert.Equal(t, []GraceStatus{GraceRedemption}, graceStatuses(tr.domain("example.se")))
+
+	assert.Equal(t, []types.RGPStatusType{types.RGPStatusRedemptionPeriod}, info())
+	assert.Equal(t, 1, tr.messageCount("ClientX"))
+
+	// Without a pending restore period a request restores the domain at once.
+	tr.registry.Lifecycle.PendingRestorePeriod = 0
+
+	response = tr.send(s, epp.EppOk, update, restore(types.RGPRestoreRequest, nil))
+	assert.Empty(t, rgpStatus(t, response))
+	assert.False(t, tr.domain("example.se").hasStatus(types.DomainStatusPendingDelete))
+}
+
+func graceStatuses(d *Domain) []GraceStatus {
+	statuses := []GraceStatus{}
+
+	for _, g := range d.GracePeriods {
+		statuses = append(statuses, g.Status)
+	}
+
+	return statuses
+}
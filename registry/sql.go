@@ -0,0 +1,1113 @@
+package registry
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 데이터베이스마다 다른 드라이버 이름, DSN 과 SQL 문법을 정의합니다. 드라이버는
+// 이 패키지에서 불러오지 않으므로 사용하는 쪽에서 직접 import 해야 합니다.
+//
+//	import _ "github.com/mattn/go-sqlite3"
+type Dialect struct {
+	// database/sql 에 등록된 드라이버 이름입니다.
+	Driver string
+
+	// 1 부터 시작하는 n 번째 인자의 placeholder 를 반환합니다. nil 이면 ? 를 사용합니다.
+	Placeholder func(n int) string
+
+	// 길이 제한이 없는 문자열, 시간, 큰 정수를 저장하는 컬럼 타입입니다.
+	Text      string
+	Timestamp string
+	BigInt    string
+
+	// config.yml 의 데이터베이스 설정으로 DSN 을 생성합니다.
+	DSN func(info epp.DatabaseInfo) string
+}
+
+// 지원하는 데이터베이스의 Dialect 입니다.
+var (
+	DialectSQLite = &Dialect{
+		Driver:    "sqlite3",
+		Text:      "TEXT",
+		Timestamp: "TIMESTAMP",
+		BigInt:    "BIGINT",
+		DSN:       func(info epp.DatabaseInfo) string { return info.Database },
+	}
+
+	DialectMySQL = &Dialect{
+		Driver:    "mysql",
+		Text:      "TEXT",
+		Timestamp: "DATETIME",
+		BigInt:    "BIGINT",
+		DSN: func(info epp.DatabaseInfo) string {
+			return fmt.Sprintf(
+				"%s:%s@tcp(%s:%d)/%s?parseTime=true&loc=UTC",
+				info.User, info.Password, info.Host, info.Port, info.Database,
+			)
+		},
+	}
+
+	DialectPostgres = &Dialect{
+		Driver:      "postgres",
+		Placeholder: func(n int) string { return "$" + strconv.Itoa(n) },
+		Text:        "TEXT",
+		Timestamp:   "TIMESTAMP",
+		BigInt:      "BIGINT",
+		DSN: func(info epp.DatabaseInfo) string {
+			return fmt.Sprintf(
+				"postgres://%s:%s@%s:%d/%s?sslmode=disable",
+				info.User, info.Password, info.Host, info.Port, info.Database,
+			)
+		},
+	}
+
+	DialectOracle = &Dialect{
+		Driver:      "godror",
+		Placeholder: func(n int) string { return ":" + strconv.Itoa(n) },
+		Text:        "CLOB",
+		Timestamp:   "TIMESTAMP",
+		BigInt:      "NUMBER(19)",
+		DSN: func(info epp.DatabaseInfo) string {
+			return fmt.Sprintf(
+				`user="%s" password="%s" connectString="%s:%d/%s"`,
+				info.User, info.Password, info.Host, info.Port, info.Database,
+			)
+		},
+	}
+)
+
+// config.yml 의 type 값으로 Dialect 를 찾습니다.
+func DialectFor(name string) (*Dialect, error) {
+	switch strings.ToLower(name) {
+	case "sqlite", "sqlite3":
+		return DialectSQLite, nil
+	case "mysql", "mariadb":
+		return DialectMySQL, nil
+	case "postgres", "postgresql":
+		return DialectPostgres, nil
+	case "oracle", "godror":
+		return DialectOracle, nil
+	}
+
+	return nil, errors.Errorf("unsupported database type '%s'", name)
+}
+
+// database/sql 을 사용하는 저장소입니다. 모든 쓰기 작업은 트랜잭션 안에서 실행되며
+// 사용하기 전에 Migrate 로 스키마를 생성해야 합니다.
+type SQLRepository struct {
+	db      *sql.DB
+	dialect *Dialect
+
+	// 트랜잭션 안에서 사용되는 저장소인 경우에만 설정됩니다.
+	tx *sql.Tx
+}
+
+// 이미 연결된 데이터베이스로 저장소를 생성합니다.
+func NewSQLRepository(db *sql.DB, dialect *Dialect) *SQLRepository {
+	return &SQLRepository{
+		db:      db,
+		dialect: dialect,
+	}
+}
+
+// config.yml 의 데이터베이스 설정으로 데이터베이스에 연결하고 스키마를 최신 버전으로
+// 갱신한 저장소를 반환합니다. type 이 sqlite 이고 database 가 :memory: 이면 별도의
+// 데이터베이스 없이 프로세스 안에서 실행됩니다.
+func OpenSQLRepository(info epp.DatabaseInfo) (*SQLRepository, error) {
+	dialect, err := DialectFor(info.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(dialect.Driver, dialect.DSN(info))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open database")
+	}
+
+	if dialect == DialectSQLite {
+		// SQLite 는 동시에 하나의 쓰기만 허용하고 :memory: 데이터베이스는 연결마다
+		// 따로 생성되므로 연결을 하나만 사용합니다.
+		db.SetMaxOpenConns(1)
+	}
+
+	r := NewSQLRepository(db, dialect)
+
+	if err := r.Migrate(); err != nil {
+		db.Close()
+
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// 데이터베이스 연결을 닫습니다.
+func (r *SQLRepository) Close() error {
+	return r.db.Close()
+}
+
+// 데이터베이스에 적용되지 않은 마이그레이션을 순서대로 적용합니다. 적용된 버전은
+// schema_migrations 테이블에 저장됩니다.
+func (r *SQLRepository) Migrate() error {
+	var version int
+
+	// 모든 데이터베이스가 IF NOT EXISTS 를 지원하지 않으므로 조회가 실패하면 테이블을
+	// 생성합니다.
+	if err := r.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&version); err != nil {
+		if _, err := r.db.Exec("CREATE TABLE schema_migrations (version INTEGER NOT NULL PRIMARY KEY)"); err != nil {
+			return errors.Wrap(err, "could not create schema_migrations")
+		}
+	}
+
+	for i := version; i < len(migrations); i++ {
+		err := r.Transaction(func(tx Repository) error {
+			sqlTx := tx.(*SQLRepository)
+
+			for _, statement := range migrations[i](r.dialect) {
+				if _, err := sqlTx.exec(statement); err != nil {
+					return errors.Wrapf(err, "could not execute '%s'", statement)
+				}
+			}
+
+			_, err := sqlTx.exec("INSERT INTO schema_migrations (version) VALUES (?)", i+1)
+
+			return err
+		})
+
+		if err != nil {
+			return errors.Wrapf(err, "migration %d failed", i+1)
+		}
+	}
+
+	return nil
+}
+
+// 트랜잭션 안에서 함수를 실행합니다. 함수가 오류를 반환하면 롤백합니다.
+func (r *SQLRepository) Transaction(fn func(tx Repository) error) error {
+	if r.tx != nil {
+		return fn(r)
+	}
+
+	tx, err := r.db.Begin()
+	if err != nil {
+		return errors.Wrap(err, "could not begin transaction")
+	}
+
+	if err := fn(&SQLRepository{db: r.db, dialect: r.dialect, tx: tx}); err != nil {
+		tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// 여러 구문으로 이루어진 쓰기 작업을 하나의 트랜잭션 안에서 실행합니다.
+func (r *SQLRepository) write(fn func(tx *SQLRepository) error) error {
+	return r.Transaction(func(tx Repository) error {
+		return fn(tx.(*SQLRepository))
+	})
+}
+
+func (r *SQLRepository) NextID() (int64, error) {
+	return r.nextSequence("roid")
+}
+
+func (r *SQLRepository) nextSequence(name string) (int64, error) {
+	var id int64
+
+	err := r.write(func(tx *SQLRepository) error {
+		if _, err := tx.exec("UPDATE sequences SET current_id = current_id + 1 WHERE name = ?", name); err != nil {
+			return err
+		}
+
+		return tx.queryRow("SELECT current_id FROM sequences WHERE name = ?", name).Scan(&id)
+	})
+
+	return id, err
+}
+
+func (r *SQLRepository) Domain(name string) (*Domain, error) {
+	d := &Domain{}
+
+	var transfer, gracePeriods, dnssec string
+
+	err := r.queryRow(`
+		SELECT name, roid, registrant, client_id, create_id, create_date,
+			update_id, update_date, expire_date, transfer_date, auth_info, auth_info_date,
+			transfer, grace_periods, delete_date, dnssec
+		FROM domains WHERE name = ?`, name,
+	).Scan(
+		&d.Name, &d.ROID, nullString{&d.Registrant}, nullString{&d.ClientID},
+		nullString{&d.CreateID}, &d.CreateDate, nullString{&d.UpdateID},
+		nullTime{&d.UpdateDate}, &d.ExpireDate, nullTime{&d.TransferDate},
+		nullString{&d.AuthInfo}, nullTime{&d.AuthInfoDate}, nullString{&transfer},
+		nullString{&gracePeriods}, nullTime{&d.DeleteDate}, nullString{&dnssec},
+	)
+	if err != nil {
+		return nil, notFoundError(err)
+	}
+
+	if err := unmarshalJSON(transfer, &d.Transfer); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSON(gracePeriods, &d.GracePeriods); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSON(dnssec, &d.DNSSEC); err != nil {
+		return nil, err
+	}
+
+	statuses, err := r.selectStrings("SELECT status FROM domain_statuses WHERE domain_name = ? ORDER BY status", name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range statuses {
+		d.Status = append(d.Status, types.DomainStatusType(s))
+	}
+
+	d.Hosts, err = r.selectStrings("SELECT host_name FROM domain_hosts WHERE domain_name = ? ORDER BY ordinal", name)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.query("SELECT contact_id, contact_type FROM domain_contacts WHERE domain_name = ? ORDER BY ordinal", name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		c := types.Contact{}
+
+		if err := rows.Scan(&c.Name, &c.Type); err != nil {
+			return nil, err
+		}
+
+		d.Contacts = append(d.Contacts, c)
+	}
+
+	return d, rows.Err()
+}
+
+func (r *SQLRepository) CreateDomain(d *Domain) error {
+	return r.write(func(tx *SQLRepository) error {
+		transfer, gracePeriods, dnssec, err := domainJSON(d)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			INSERT INTO domains (name, roid, registrant, client_id, create_id, create_date,
+				update_id, update_date, expire_date, transfer_date, auth_info, auth_info_date,
+				transfer, grace_periods, delete_date, dnssec)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			d.Name, d.ROID, d.Registrant, d.ClientID, d.CreateID, d.CreateDate,
+			d.UpdateID, nullableTime(d.UpdateDate), d.ExpireDate, nullableTime(d.TransferDate),
+			d.AuthInfo, nullableTime(d.AuthInfoDate), transfer, gracePeriods, nullableTime(d.DeleteDate),
+			dnssec,
+		)
+		if err != nil {
+			return err
+		}
+
+		return tx.insertDomainRelations(d)
+	})
+}
+
+func (r *SQLRepository) UpdateDomain(d *Domain) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("domains", "name", d.Name); err != nil {
+			return err
+		}
+
+		transfer, gracePeriods, dnssec, err := domainJSON(d)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			UPDATE domains SET roid = ?, registrant = ?, client_id = ?, create_id = ?,
+				create_date = ?, update_id = ?, update_date = ?, expire_date = ?,
+				transfer_date = ?, auth_info = ?, auth_info_date = ?, transfer = ?,
+				grace_periods = ?, delete_date = ?, dnssec = ?
+			WHERE name = ?`,
+			d.ROID, d.Registrant, d.ClientID, d.CreateID, d.CreateDate, d.UpdateID,
+			nullableTime(d.UpdateDate), d.ExpireDate, nullableTime(d.TransferDate),
+			d.AuthInfo, nullableTime(d.AuthInfoDate), transfer, gracePeriods,
+			nullableTime(d.DeleteDate), dnssec, d.Name,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.deleteRelations("domain_name", d.Name, "domain_statuses", "domain_contacts", "domain_hosts"); err != nil {
+			return err
+		}
+
+		return tx.insertDomainRelations(d)
+	})
+}
+
+func (r *SQLRepository) DeleteDomain(name string) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("domains", "name", name); err != nil {
+			return err
+		}
+
+		if err := tx.deleteRelations("domain_name", name, "domain_statuses", "domain_contacts", "domain_hosts"); err != nil {
+			return err
+		}
+
+		_, err := tx.exec("DELETE FROM domains WHERE name = ?", name)
+
+		return err
+	})
+}
+
+// 도메인의 이전 요청, 유예 기간과 DNSSEC 위임 정보를 JSON 으로 반환합니다.
+func domainJSON(d *Domain) (interface{}, interface{}, interface{}, error) {
+	transfer, err := marshalJSON(d.Transfer)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var gracePeriods interface{}
+
+	if len(d.GracePeriods) > 0 {
+		gracePeriods, err = marshalJSON(d.GracePeriods)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	dnssec, err := marshalJSON(d.DNSSEC)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return transfer, gracePeriods, dnssec, nil
+}
+
+func (r *SQLRepository) insertDomainRelations(d *Domain) error {
+	for _, s := range d.Status {
+		if _, err := r.exec("INSERT INTO domain_statuses (domain_name, status) VALUES (?, ?)", d.Name, string(s)); err != nil {
+			return err
+		}
+	}
+
+	for i, c := range d.Contacts {
+		_, err := r.exec(
+			"INSERT INTO domain_contacts (domain_name, contact_id, contact_type, ordinal) VALUES (?, ?, ?, ?)",
+			d.Name, c.Name, c.Type, i,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, h := range d.Hosts {
+		if _, err := r.exec("INSERT INTO domain_hosts (domain_name, host_name, ordinal) VALUES (?, ?, ?)", d.Name, h, i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *SQLRepository) DomainsByHost(name string) ([]string, error) {
+	return r.selectStrings("SELECT DISTINCT domain_name FROM domain_hosts WHERE host_name = ? ORDER BY domain_name", name)
+}
+
+func (r *SQLRepository) DomainsByStatus(status types.DomainStatusType) ([]string, error) {
+	return r.selectStrings("SELECT domain_name FROM domain_statuses WHERE status = ? ORDER BY domain_name", string(status))
+}
+
+func (r *SQLRepository) DomainsExpiring(before time.Time) ([]string, error) {
+	return r.selectStrings("SELECT name FROM domains WHERE expire_date <= ? ORDER BY name", before)
+}
+
+func (r *SQLRepository) DomainsByContact(id string) ([]string, error) {
+	return r.selectStrings(`
+		SELECT name FROM domains WHERE registrant = ?
+		UNION
+		SELECT domain_name FROM domain_contacts WHERE contact_id = ?
+		ORDER BY 1`, id, id,
+	)
+}
+
+func (r *SQLRepository) Host(name string) (*Host, error) {
+	h := &Host{}
+
+	err := r.queryRow(`
+		SELECT name, roid, superordinate, client_id, create_id, create_date,
+			update_id, update_date, transfer_date
+		FROM hosts WHERE name = ?`, name,
+	).Scan(
+		&h.Name, &h.ROID, nullString{&h.Superordinate}, nullString{&h.ClientID},
+		nullString{&h.CreateID}, &h.CreateDate, nullString{&h.UpdateID},
+		nullTime{&h.UpdateDate}, nullTime{&h.TransferDate},
+	)
+	if err != nil {
+		return nil, notFoundError(err)
+	}
+
+	statuses, err := r.selectStrings("SELECT status FROM host_statuses WHERE host_name = ? ORDER BY status", name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range statuses {
+		h.Status = append(h.Status, types.HostStatusType(s))
+	}
+
+	rows, err := r.query("SELECT address, ip FROM host_addresses WHERE host_name = ? ORDER BY ordinal", name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var address, ip string
+
+		if err := rows.Scan(&address, nullString{&ip}); err != nil {
+			return nil, err
+		}
+
+		h.Addresses = append(h.Addresses, types.HostAddress{Address: address, IP: types.IPType(ip)})
+	}
+
+	return h, rows.Err()
+}
+
+func (r *SQLRepository) CreateHost(h *Host) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.insertHost(h); err != nil {
+			return err
+		}
+
+		return tx.insertHostRelations(h)
+	})
+}
+
+func (r *SQLRepository) UpdateHost(name string, h *Host) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("hosts", "name", name); err != nil {
+			return err
+		}
+
+		// 이름이 바뀔 수 있으므로 기존 호스트를 지우고 다시 생성합니다.
+		if err := tx.deleteHost(name); err != nil {
+			return err
+		}
+
+		if err := tx.insertHost(h); err != nil {
+			return err
+		}
+
+		return tx.insertHostRelations(h)
+	})
+}
+
+func (r *SQLRepository) DeleteHost(name string) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("hosts", "name", name); err != nil {
+			return err
+		}
+
+		return tx.deleteHost(name)
+	})
+}
+
+func (r *SQLRepository) insertHost(h *Host) error {
+	_, err := r.exec(`
+		INSERT INTO hosts (name, roid, superordinate, client_id, create_id, create_date,
+			update_id, update_date, transfer_date)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		h.Name, h.ROID, h.Superordinate, h.ClientID, h.CreateID, h.CreateDate,
+		h.UpdateID, nullableTime(h.UpdateDate), nullableTime(h.TransferDate),
+	)
+
+	return err
+}
+
+func (r *SQLRepository) insertHostRelations(h *Host) error {
+	for _, s := range h.Status {
+		if _, err := r.exec("INSERT INTO host_statuses (host_name, status) VALUES (?, ?)", h.Name, string(s)); err != nil {
+			return err
+		}
+	}
+
+	for i, a := range h.Addresses {
+		_, err := r.exec(
+			"INSERT INTO host_addresses (host_name, address, ip, ordinal) VALUES (?, ?, ?, ?)",
+			h.Name, a.Address, string(a.IP), i,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *SQLRepository) deleteHost(name string) error {
+	if err := r.deleteRelations("host_name", name, "host_statuses", "host_addresses"); err != nil {
+		return err
+	}
+
+	_, err := r.exec("DELETE FROM hosts WHERE name = ?", name)
+
+	return err
+}
+
+func (r *SQLRepository) HostsBySuperordinate(domain string) ([]string, error) {
+	return r.selectStrings("SELECT name FROM hosts WHERE superordinate = ? ORDER BY name", domain)
+}
+
+func (r *SQLRepository) Contact(id string) (*Contact, error) {
+	c := &Contact{}
+
+	var (
+		voice, voiceExt, fax, faxExt   string
+		postalInfo, disclose, transfer string
+	)
+
+	err := r.queryRow(`
+		SELECT id, roid, voice, voice_ext, fax, fax_ext, email, client_id, create_id,
+			create_date, update_id, update_date, transfer_date, auth_info,
+			postal_info, disclose, transfer
+		FROM contacts WHERE id = ?`, id,
+	).Scan(
+		&c.ID, &c.ROID, nullString{&voice}, nullString{&voiceExt}, nullString{&fax},
+		nullString{&faxExt}, nullString{&c.Email}, nullString{&c.ClientID},
+		nullString{&c.CreateID}, &c.CreateDate, nullString{&c.UpdateID},
+		nullTime{&c.UpdateDate}, nullTime{&c.TransferDate}, nullString{&c.AuthInfo},
+		nullString{&postalInfo}, nullString{&disclose}, nullString{&transfer},
+	)
+	if err != nil {
+		return nil, notFoundError(err)
+	}
+
+	if voice != "" {
+		c.Voice = &types.E164Type{Value: voice, X: voiceExt}
+	}
+
+	if fax != "" {
+		c.Fax = &types.E164Type{Value: fax, X: faxExt}
+	}
+
+	if err := unmarshalJSON(postalInfo, &c.PostalInfo); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSON(disclose, &c.Disclose); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSON(transfer, &c.Transfer); err != nil {
+		return nil, err
+	}
+
+	statuses, err := r.selectStrings("SELECT status FROM contact_statuses WHERE contact_id = ? ORDER BY status", id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range statuses {
+		c.Status = append(c.Status, types.ContactStatusType(s))
+	}
+
+	return c, nil
+}
+
+func (r *SQLRepository) CreateContact(c *Contact) error {
+	return r.write(func(tx *SQLRepository) error {
+		args, err := contactArgs(c)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			INSERT INTO contacts (voice, voice_ext, fax, fax_ext, email, client_id, create_id,
+				create_date, update_id, update_date, transfer_date, auth_info,
+				postal_info, disclose, transfer, roid, id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+
+		return tx.insertContactStatuses(c)
+	})
+}
+
+func (r *SQLRepository) UpdateContact(c *Contact) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("contacts", "id", c.ID); err != nil {
+			return err
+		}
+
+		args, err := contactArgs(c)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			UPDATE contacts SET voice = ?, voice_ext = ?, fax = ?, fax_ext = ?, email = ?,
+				client_id = ?, create_id = ?, create_date = ?, update_id = ?, update_date = ?,
+				transfer_date = ?, auth_info = ?, postal_info = ?, disclose = ?, transfer = ?,
+				roid = ?
+			WHERE id = ?`,
+			args...,
+		)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.deleteRelations("contact_id", c.ID, "contact_statuses"); err != nil {
+			return err
+		}
+
+		return tx.insertContactStatuses(c)
+	})
+}
+
+func (r *SQLRepository) DeleteContact(id string) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("contacts", "id", id); err != nil {
+			return err
+		}
+
+		if err := tx.deleteRelations("contact_id", id, "contact_statuses"); err != nil {
+			return err
+		}
+
+		_, err := tx.exec("DELETE FROM contacts WHERE id = ?", id)
+
+		return err
+	})
+}
+
+// 연락처를 생성하고 갱신할 때 사용하는 인자입니다. 두 구문 모두 마지막 인자가 ID
+// 가 되도록 같은 순서를 사용합니다.
+func contactArgs(c *Contact) ([]interface{}, error) {
+	var voice, voiceExt, fax, faxExt string
+
+	if c.Voice != nil {
+		voice, voiceExt = c.Voice.Value, c.Voice.X
+	}
+
+	if c.Fax != nil {
+		fax, faxExt = c.Fax.Value, c.Fax.X
+	}
+
+	args := []interface{}{
+		voice, voiceExt, fax, faxExt, c.Email, c.ClientID, c.CreateID, c.CreateDate,
+		c.UpdateID, nullableTime(c.UpdateDate), nullableTime(c.TransferDate), c.AuthInfo,
+	}
+
+	for _, v := range []interface{}{c.PostalInfo, c.Disclose, c.Transfer} {
+		data, err := marshalJSON(v)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, data)
+	}
+
+	return append(args, c.ROID, c.ID), nil
+}
+
+func (r *SQLRepository) insertContactStatuses(c *Contact) error {
+	for _, s := range c.Status {
+		if _, err := r.exec("INSERT INTO contact_statuses (contact_id, status) VALUES (?, ?)", c.ID, string(s)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *SQLRepository) Application(id string) (*Application, error) {
+	a := &Application{}
+
+	var phase, domain, marks string
+
+	err := r.queryRow(`
+		SELECT id, phase, phase_name, status, months, client_id, create_date,
+			update_id, update_date, allocation_token, domain, marks
+		FROM applications WHERE id = ?`, id,
+	).Scan(
+		&a.ID, &phase, nullString{&a.Phase.Name}, &a.Status, &a.Months,
+		nullString{&a.ClientID}, &a.CreateDate, nullString{&a.UpdateID},
+		nullTime{&a.UpdateDate}, nullString{&a.AllocationToken},
+		nullString{&domain}, nullString{&marks},
+	)
+	if err != nil {
+		return nil, notFoundError(err)
+	}
+
+	a.Phase.Phase = types.LaunchPhaseType(phase)
+
+	if err := unmarshalJSON(domain, &a.Domain); err != nil {
+		return nil, err
+	}
+
+	if err := unmarshalJSON(marks, &a.Marks); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (r *SQLRepository) CreateApplication(a *Application) error {
+	args, err := applicationArgs(a)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.exec(`
+		INSERT INTO applications (domain_name, phase, phase_name, status, months, client_id,
+			create_date, update_id, update_date, allocation_token, domain, marks, id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		args...,
+	)
+
+	return err
+}
+
+func (r *SQLRepository) UpdateApplication(a *Application) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("applications", "id", a.ID); err != nil {
+			return err
+		}
+
+		args, err := applicationArgs(a)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			UPDATE applications SET domain_name = ?, phase = ?, phase_name = ?, status = ?,
+				months = ?, client_id = ?, create_date = ?, update_id = ?, update_date = ?,
+				allocation_token = ?, domain = ?, marks = ?
+			WHERE id = ?`,
+			args...,
+		)
+
+		return err
+	})
+}
+
+func (r *SQLRepository) DeleteApplication(id string) error {
+	return r.write(func(tx *SQLRepository) error {
+		if err := tx.exists("applications", "id", id); err != nil {
+			return err
+		}
+
+		_, err := tx.exec("DELETE FROM applications WHERE id = ?", id)
+
+		return err
+	})
+}
+
+// 신청을 생성하고 갱신할 때 사용하는 인자입니다. 두 구문 모두 마지막 인자가 ID
+// 가 되도록 같은 순서를 사용합니다.
+func applicationArgs(a *Application) ([]interface{}, error) {
+	args := []interface{}{
+		a.Domain.Name, string(a.Phase.Phase), a.Phase.Name, string(a.Status), a.Months,
+		a.ClientID, a.CreateDate, a.UpdateID, nullableTime(a.UpdateDate), a.AllocationToken,
+	}
+
+	for _, v := range []interface{}{a.Domain, a.Marks} {
+		data, err := marshalJSON(v)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, data)
+	}
+
+	return append(args, a.ID), nil
+}
+
+func (r *SQLRepository) Enqueue(m *PollMessage) error {
+	return r.write(func(tx *SQLRepository) error {
+		id, err := tx.nextSequence("poll")
+		if err != nil {
+			return err
+		}
+
+		data, err := marshalJSON(m.Data)
+		if err != nil {
+			return err
+		}
+
+		changeData, err := marshalJSON(m.ChangeData)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.exec(`
+			INSERT INTO poll_messages (id, client_id, queue_date, message, lang, data, change_data)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			id, m.ClientID, m.QueueDate, m.Message, m.Language, data, changeData,
+		)
+		if err != nil {
+			return err
+		}
+
+		m.ID = id
+
+		return nil
+	})
+}
+
+func (r *SQLRepository) Oldest(clientID string) (*PollMessage, int, error) {
+	var (
+		count int
+		id    sql.NullInt64
+	)
+
+	// LIMIT 은 데이터베이스마다 문법이 다르므로 가장 작은 ID 를 먼저 조회합니다.
+	err := r.queryRow(
+		"SELECT COUNT(*), MIN(id) FROM poll_messages WHERE client_id = ?", clientID,
+	).Scan(&count, &id)
+	if err != nil || count == 0 {
+		return nil, 0, err
+	}
+
+	m := &PollMessage{}
+
+	var data, changeData string
+
+	err = r.queryRow(`
+		SELECT id, client_id, queue_date, message, lang, data, change_data
+		FROM poll_messages WHERE id = ?`, id.Int64,
+	).Scan(
+		&m.ID, &m.ClientID, &m.QueueDate, nullString{&m.Message},
+		nullString{&m.Language}, nullString{&data}, nullString{&changeData},
+	)
+	if err != nil {
+		return nil, 0, notFoundError(err)
+	}
+
+	if err := unmarshalJSON(data, &m.Data); err != nil {
+		return nil, 0, err
+	}
+
+	if err := unmarshalJSON(changeData, &m.ChangeData); err != nil {
+		return nil, 0, err
+	}
+
+	return m, count, nil
+}
+
+func (r *SQLRepository) Acknowledge(clientID string, id int64) (int, error) {
+	var count int
+
+	err := r.write(func(tx *SQLRepository) error {
+		result, err := tx.exec("DELETE FROM poll_messages WHERE client_id = ? AND id = ?", clientID, id)
+		if err != nil {
+			return err
+		}
+
+		if n, err := result.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return ErrObjectNotFound
+		}
+
+		return tx.queryRow("SELECT COUNT(*) FROM poll_messages WHERE client_id = ?", clientID).Scan(&count)
+	})
+
+	return count, err
+}
+
+// 개체에 연결된 테이블의 행을 모두 지웁니다.
+func (r *SQLRepository) deleteRelations(column, key string, tables ...string) error {
+	for _, table := range tables {
+		if _, err := r.exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", table, column), key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// 개체가 없으면 ErrObjectNotFound 를 반환합니다.
+func (r *SQLRepository) exists(table, column, key string) error {
+	var count int
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s = ?", table, column)
+	if err := r.queryRow(query, key).Scan(&count); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return ErrObjectNotFound
+	}
+
+	return nil
+}
+
+// 첫 번째 컬럼의 값을 모두 문자열로 반환합니다.
+func (r *SQLRepository) selectStrings(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	values := []string{}
+
+	for rows.Next() {
+		var v string
+
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, rows.Err()
+}
+
+func (r *SQLRepository) exec(query string, args ...interface{}) (sql.Result, error) {
+	if r.tx != nil {
+		return r.tx.Exec(r.rebind(query), args...)
+	}
+
+	return r.db.Exec(r.rebind(query), args...)
+}
+
+func (r *SQLRepository) query(query string, args ...interface{}) (*sql.Rows, error) {
+	if r.tx != nil {
+		return r.tx.Query(r.rebind(query), args...)
+	}
+
+	return r.db.Query(r.rebind(query), args...)
+}
+
+func (r *SQLRepository) queryRow(query string, args ...interface{}) *sql.Row {
+	if r.tx != nil {
+		return r.tx.QueryRow(r.rebind(query), args...)
+	}
+
+	return r.db.QueryRow(r.rebind(query), args...)
+}
+
+// 구문의 ? 를 Dialect 의 placeholder 로 바꿉니다.
+func (r *SQLRepository) rebind(query string) string {
+	if r.dialect.Placeholder == nil {
+		return query
+	}
+
+	var (
+		sb strings.Builder
+		n  int
+	)
+
+	for _, c := range query {
+		if c != '?' {
+			sb.WriteRune(c)
+			continue
+		}
+
+		n++
+		sb.WriteString(r.dialect.Placeholder(n))
+	}
+
+	return sb.String()
+}
+
+func notFoundError(err error) error {
+	if err == sql.ErrNoRows {
+		return ErrObjectNotFound
+	}
+
+	return err
+}
+
+// 구조체를 JSON 문자열로 저장합니다. nil 인 값은 NULL 로 저장됩니다.
+func marshalJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if string(data) == "null" {
+		return nil, nil
+	}
+
+	return string(data), nil
+}
+
+func unmarshalJSON(data string, v interface{}) error {
+	if data == "" {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(data), v)
+}
+
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+
+	return *t
+}
+
+// NULL 을 빈 문자열로 읽습니다. Oracle 은 빈 문자열을 NULL 로 저장합니다.
+type nullString struct {
+	s *string
+}
+
+func (n nullString) Scan(value interface{}) error {
+	ns := sql.NullString{}
+
+	if err := ns.Scan(value); err != nil {
+		return err
+	}
+
+	*n.s = ns.String
+
+	return nil
+}
+
+// NULL 을 nil 로 읽습니다.
+type nullTime struct {
+	t **time.Time
+}
+
+func (n nullTime) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*n.t = nil
+	case time.Time:
+		*n.t = &v
+	default:
+		return errors.Errorf("could not scan %T as time", value)
+	}
+
+	return nil
+}
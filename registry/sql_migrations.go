@@ -0,0 +1,189 @@
+package registry
+
+import "strings"
+
+// 데이터베이스 스키마의 마이그레이션입니다. 마이그레이션은 schema_migrations 에
+// 저장된 버전 다음부터 순서대로 적용되므로 이미 배포된 마이그레이션은 수정하지 말고
+// 새로운 마이그레이션을 마지막에 추가해야 합니다.
+var migrations = []func(d *Dialect) []string{
+	// 1: 도메인, 호스트, 연락처, 상태와 서비스 메시지
+	func(d *Dialect) []string {
+		return dialectStatements(d, []string{
+			`CREATE TABLE sequences (
+				name VARCHAR(64) NOT NULL PRIMARY KEY,
+				current_id {bigint} NOT NULL
+			)`,
+			`INSERT INTO sequences (name, current_id) VALUES ('roid', 0)`,
+			`INSERT INTO sequences (name, current_id) VALUES ('poll', 0)`,
+
+			`CREATE TABLE domains (
+				name VARCHAR(255) NOT NULL PRIMARY KEY,
+				roid VARCHAR(89) NOT NULL,
+				registrant VARCHAR(255),
+				client_id VARCHAR(255),
+				create_id VARCHAR(255),
+				create_date {timestamp} NOT NULL,
+				update_id VARCHAR(255),
+				update_date {timestamp},
+				expire_date {timestamp} NOT NULL,
+				transfer_date {timestamp},
+				auth_info VARCHAR(255),
+				transfer {text}
+			)`,
+			`CREATE INDEX domains_registrant ON domains (registrant)`,
+			`CREATE TABLE domain_statuses (
+				domain_name VARCHAR(255) NOT NULL,
+				status VARCHAR(32) NOT NULL,
+				PRIMARY KEY (domain_name, status)
+			)`,
+			`CREATE TABLE domain_contacts (
+				domain_name VARCHAR(255) NOT NULL,
+				contact_id VARCHAR(255) NOT NULL,
+				contact_type VARCHAR(16) NOT NULL,
+				ordinal INTEGER NOT NULL,
+				PRIMARY KEY (domain_name, contact_id, contact_type)
+			)`,
+			`CREATE INDEX domain_contacts_contact ON domain_contacts (contact_id)`,
+			`CREATE TABLE domain_hosts (
+				domain_name VARCHAR(255) NOT NULL,
+				host_name VARCHAR(255) NOT NULL,
+				ordinal INTEGER NOT NULL,
+				PRIMARY KEY (domain_name, host_name)
+			)`,
+			`CREATE INDEX domain_hosts_host ON domain_hosts (host_name)`,
+
+			`CREATE TABLE hosts (
+				name VARCHAR(255) NOT NULL PRIMARY KEY,
+				roid VARCHAR(89) NOT NULL,
+				superordinate VARCHAR(255),
+				client_id VARCHAR(255),
+				create_id VARCHAR(255),
+				create_date {timestamp} NOT NULL,
+				update_id VARCHAR(255),
+				update_date {timestamp},
+				transfer_date {timestamp}
+			)`,
+			`CREATE INDEX hosts_superordinate ON hosts (superordinate)`,
+			`CREATE TABLE host_statuses (
+				host_name VARCHAR(255) NOT NULL,
+				status VARCHAR(32) NOT NULL,
+				PRIMARY KEY (host_name, status)
+			)`,
+			`CREATE TABLE host_addresses (
+				host_name VARCHAR(255) NOT NULL,
+				address VARCHAR(45) NOT NULL,
+				ip VARCHAR(2),
+				ordinal INTEGER NOT NULL,
+				PRIMARY KEY (host_name, address)
+			)`,
+
+			`CREATE TABLE contacts (
+				id VARCHAR(255) NOT NULL PRIMARY KEY,
+				roid VARCHAR(89) NOT NULL,
+				voice VARCHAR(64),
+				voice_ext VARCHAR(64),
+				fax VARCHAR(64),
+				fax_ext VARCHAR(64),
+				email VARCHAR(255),
+				client_id VARCHAR(255),
+				create_id VARCHAR(255),
+				create_date {timestamp} NOT NULL,
+				update_id VARCHAR(255),
+				update_date {timestamp},
+				transfer_date {timestamp},
+				auth_info VARCHAR(255),
+				postal_info {text},
+				disclose {text},
+				transfer {text}
+			)`,
+			`CREATE TABLE contact_statuses (
+				contact_id VARCHAR(255) NOT NULL,
+				status VARCHAR(32) NOT NULL,
+				PRIMARY KEY (contact_id, status)
+			)`,
+
+			`CREATE TABLE poll_messages (
+				id {bigint} NOT NULL PRIMARY KEY,
+				client_id VARCHAR(255) NOT NULL,
+				queue_date {timestamp} NOT NULL,
+				message VARCHAR(255),
+				lang VARCHAR(16),
+				data {text}
+			)`,
+			`CREATE INDEX poll_messages_client ON poll_messages (client_id, id)`,
+		})
+	},
+
+	// 2: 도메인 수명 주기
+	func(d *Dialect) []string {
+		return dialectStatements(d, []string{
+			`ALTER TABLE domains ADD grace_periods {text}`,
+			`ALTER TABLE domains ADD delete_date {timestamp}`,
+			`CREATE INDEX domains_expire_date ON domains (expire_date)`,
+		})
+	},
+
+	// 3: 런치 단계의 도메인 신청
+	func(d *Dialect) []string {
+		return dialectStatements(d, []string{
+			`CREATE TABLE applications (
+				id VARCHAR(64) NOT NULL PRIMARY KEY,
+				domain_name VARCHAR(255) NOT NULL,
+				phase VARCHAR(16) NOT NULL,
+				phase_name VARCHAR(255),
+				status VARCHAR(32) NOT NULL,
+				months INTEGER NOT NULL,
+				client_id VARCHAR(255),
+				create_date {timestamp} NOT NULL,
+				update_id VARCHAR(255),
+				update_date {timestamp},
+				domain {text},
+				marks {text}
+			)`,
+			`CREATE INDEX applications_domain_name ON applications (domain_name)`,
+		})
+	},
+
+	// 4: 서버에서 바꾼 개체의 서비스 메시지
+	func(d *Dialect) []string {
+		return dialectStatements(d, []string{
+			`ALTER TABLE poll_messages ADD change_data {text}`,
+		})
+	},
+
+	// 5: 인증 정보가 설정된 시간 (RFC 9154)
+	func(d *Dialect) []string {
+		return dialectStatements(d, []string{
+			`ALTER TABLE domains ADD auth_info_date {timestamp}`,
+		})
+	},
+
+	// 6: 도메인의 DNSSEC 위임 정보 (RFC 5910)
+	func(d *Dialect) []string {
+		return dialectStatements(d, []string{
+			`ALTER TABLE domains ADD dnssec {text}`,
+		})
+	},
+
+	// 7: 신청이 할당될 때 사용할 할당 토큰 (RFC 8495)
+	func(d *Dialect) []string {
+		return dialectStatements(d, []string{
+			`ALTER TABLE applications ADD allocation_token VARCHAR(255)`,
+		})
+	},
+}
+
+// 구문의 {text}, {timestamp}, {bigint} 를 Dialect 의 컬럼 타입으로 바꿉니다.
+func dialectStatements(d *Dialect, statements []string) []string {
+	r := strings.NewReplacer(
+		"{text}", d.Text,
+		"{timestamp}", d.Timestamp,
+		"{bigint}", d.BigInt,
+	)
+
+	for i := range statements {
+		statements[i] = r.Replace(statements[i])
+	}
+
+	return statements
+}
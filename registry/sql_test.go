@@ -0,0 +1,189 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLRepository(t *testing.T) Repository {
+	repo, err := OpenSQLRepository(epp.DatabaseInfo{Type: "sqlite", Database: ":memory:"})
+	require.Nil(t, err)
+
+	t.Cleanup(func() { repo.Close() })
+
+	return repo
+}
+
+func TestSQLRepository_Migrate(t *testing.T) {
+	repo := newTestSQLRepository(t).(*SQLRepository)
+
+	// Running the migrations again should be a no-op.
+	require.Nil(t, repo.Migrate())
+
+	var version int
+
+	require.Nil(t, repo.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&version))
+	assert.Equal(t, len(migrations), version)
+}
+
+func TestSQLRepository_objects(t *testing.T) {
+	repo := newTestSQLRepository(t)
+	now := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	expire := now.AddDate(1, 0, 0)
+
+	domain := &Domain{
+		Name:         "example.se",
+		ROID:         "D1-EPPGO",
+		Status:       []types.DomainStatusType{types.DomainStatusClientHold, types.DomainStatusInactive},
+		Registrant:   "jd1234",
+		Contacts:     []types.Contact{{Name: "sh8013", Type: "tech"}, {Name: "jd1234", Type: "admin"}},
+		Hosts:        []string{"ns2.example.se", "ns1.example.se"},
+		ClientID:     "ClientX",
+		CreateID:     "ClientX",
+		CreateDate:   now,
+		ExpireDate:   expire,
+		AuthInfo:     "2fooBAR",
+		AuthInfoDate: &now,
+		Transfer: &Transfer{
+			Status:         types.DomainTransferServerApproved,
+			RequestingID:   "ClientY",
+			RequestingDate: now,
+			ActingID:       "ClientX",
+			ActingDate:     now,
+			ExpireDate:     &expire,
+		},
+		GracePeriods: []GracePeriod{
+			{Status: GraceAdd, EndDate: now.AddDate(0, 0, 5), Months: 12},
+			{Status: GraceTransfer, EndDate: now.AddDate(0, 0, 5)},
+		},
+		DeleteDate: &now,
+	}
+
+	require.Nil(t, repo.CreateDomain(domain))
+
+	stored, err := repo.Domain("example.se")
+	require.Nil(t, err)
+	assert.Equal(t, domain, stored)
+
+	names, err := repo.DomainsByContact("jd1234")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"example.se"}, names)
+
+	names, err = repo.DomainsByHost("ns1.example.se")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"example.se"}, names)
+
+	domain.Hosts = []string{}
+	domain.UpdateDate = &now
+	domain.GracePeriods = nil
+	domain.DeleteDate = nil
+
+	require.Nil(t, repo.UpdateDomain(domain))
+
+	stored, err = repo.Domain("example.se")
+	require.Nil(t, err)
+	assert.Equal(t, domain, stored)
+
+	names, err = repo.DomainsByHost("ns1.example.se")
+	require.Nil(t, err)
+	assert.Empty(t, names)
+
+	names, err = repo.DomainsExpiring(expire)
+	require.Nil(t, err)
+	assert.Equal(t, []string{"example.se"}, names)
+
+	names, err = repo.DomainsExpiring(now)
+	require.Nil(t, err)
+	assert.Empty(t, names)
+
+	host := &Host{
+		Name:          "ns1.example.se",
+		ROID:          "H2-EPPGO",
+		Addresses:     []types.HostAddress{{Address: "192.0.2.1", IP: types.HostIPv4}},
+		Superordinate: "example.se",
+		ClientID:      "ClientX",
+		CreateDate:    now,
+	}
+
+	require.Nil(t, repo.CreateHost(host))
+
+	host.Name = "ns2.example.se"
+	host.Status = []types.HostStatusType{types.HostStatusClientDeleteProhibited}
+
+	require.Nil(t, repo.UpdateHost("ns1.example.se", host))
+
+	_, err = repo.Host("ns1.example.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+
+	storedHost, err := repo.Host("ns2.example.se")
+	require.Nil(t, err)
+	assert.Equal(t, host, storedHost)
+
+	names, err = repo.HostsBySuperordinate("example.se")
+	require.Nil(t, err)
+	assert.Equal(t, []string{"ns2.example.se"}, names)
+
+	contact := &Contact{
+		ID:   "jd1234",
+		ROID: "C3-EPPGO",
+		PostalInfo: []types.PostalInfo{
+			{Name: "John Doe", Address: types.Address{City: "Stockholm", CountryCode: "SE"}, Type: types.PostalInfoInternational},
+		},
+		Voice:      &types.E164Type{Value: "+46.123456", X: "1234"},
+		Email:      "jdoe@example.se",
+		ClientID:   "ClientX",
+		CreateDate: now,
+		Disclose:   &types.Disclose{Email: &types.EmptyTag{}},
+	}
+
+	require.Nil(t, repo.CreateContact(contact))
+
+	storedContact, err := repo.Contact("jd1234")
+	require.Nil(t, err)
+	assert.Equal(t, contact, storedContact)
+
+	require.Nil(t, repo.DeleteContact("jd1234"))
+	assert.Equal(t, ErrObjectNotFound, repo.DeleteContact("jd1234"))
+	assert.Equal(t, ErrObjectNotFound, repo.UpdateDomain(&Domain{Name: "example2.se"}))
+
+	first, err := repo.NextID()
+	require.Nil(t, err)
+
+	second, err := repo.NextID()
+	require.Nil(t, err)
+	assert.Equal(t, first+1, second)
+}
+
+func TestSQLRepository_Transaction(t *testing.T) {
+	repo := newTestSQLRepository(t)
+
+	require.Nil(t, repo.CreateDomain(&Domain{Name: "example.se", ROID: "D1-EPPGO", ClientID: "ClientX"}))
+
+	err := repo.Transaction(func(tx Repository) error {
+		d, err := tx.Domain("example.se")
+		require.Nil(t, err)
+
+		d.ClientID = "ClientY"
+
+		require.Nil(t, tx.UpdateDomain(d))
+		require.Nil(t, tx.DeleteDomain("example.se"))
+		require.Nil(t, tx.CreateHost(&Host{Name: "ns1.example.se", ROID: "H2-EPPGO"}))
+
+		return errorf(epp.EppCommandFailed, "rollback")
+	})
+
+	require.NotNil(t, err)
+
+	d, err := repo.Domain("example.se")
+	require.Nil(t, err)
+	assert.Equal(t, "ClientX", d.ClientID)
+
+	_, err = repo.Host("ns1.example.se")
+	assert.Equal(t, ErrObjectNotFound, err)
+}
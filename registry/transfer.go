@@ -0,0 +1,321 @@
+package registry
+
+import (
+	"fmt"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/pkg/errors"
+)
+
+// 이전 요청이 서버에서 자동으로 승인되기까지의 기본 기간입니다.
+const defaultTransferPeriod = 5 * 24 * time.Hour
+
+// 도메인 이전 명령어를 처리합니다. 이전 요청은 관리 클라이언트가 승인 또는 거절하거나
+// 요청한 클라이언트가 취소할 때까지 pendingTransfer 상태로 대기하며, TransferPeriod 가
+// 지나면 서버에서 승인됩니다.
+func (r *Registry) transferDomain(s *epp.Session, data []byte) (*epp.ResponseBuilder, error) {
+	cmd := types.DomainTransferType{}
+
+	if err := epp.Decode(data, &cmd); err != nil {
+		return nil, err
+	}
+
+	name := normalize(cmd.Transfer.Domain.Name)
+	authInfo := cmd.Transfer.Domain.Authinfo
+
+	// 명령어를 처리하기 전에 자동 승인 기간이 지난 이전 요청을 먼저 완료합니다.
+	if err := r.expireDomainTransfer(name); err != nil {
+		return nil, err
+	}
+
+	var (
+		d       *Domain
+		fee     *types.FeeTransformResult
+		token   string
+		notices []notice
+		code    = epp.EppOk
+	)
+
+	// 이전 요청에는 transfer 요금이 부과되고, 할당 토큰이 주어지면 토큰을 확인합니다.
+	if cmd.Transfer.Operation == types.TransferOperationRequest {
+		var err error
+
+		token, err = r.allocationToken(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if token != "" {
+			if err := r.verifyAllocationToken(token, name, s.ClientID); err != nil {
+				return nil, err
+			}
+		}
+
+		fee, err = r.chargeFee(data, name, types.FeeCommandTransfer, cmd.Transfer.Domain.Period)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		var err error
+
+		d, err = tx.Domain(name)
+		if err != nil {
+			return notFound(err, "domain %s does not exist", name)
+		}
+
+		switch cmd.Transfer.Operation {
+		case types.TransferOperationQuery:
+			if d.Transfer == nil {
+				return errorf(epp.EppObjectNotPendingTransfer, "domain %s has no transfer", name)
+			}
+
+			if d.ClientID != s.ClientID && d.Transfer.RequestingID != s.ClientID && d.Transfer.ActingID != s.ClientID && !r.validDomainAuthInfo(authInfo, d) {
+				return errorf(epp.EppAuthorisationError, "not authorized to query transfer for domain %s", name)
+			}
+
+			return nil
+		case types.TransferOperationRequest:
+			notices, err = r.requestDomainTransfer(tx, s, d, cmd.Transfer.Domain)
+			if err != nil {
+				return err
+			}
+
+			if d.Transfer.Status == types.DomainTransferPending {
+				code = epp.EppOkPending
+			}
+
+			return r.useAllocationToken(token, name)
+		}
+
+		if d.Transfer == nil || d.Transfer.Status != types.DomainTransferPending {
+			return errorf(epp.EppObjectNotPendingTransfer, "domain %s is not pending transfer", name)
+		}
+
+		switch cmd.Transfer.Operation {
+		case types.TransferOperationApprove, types.TransferOperationReject:
+			if d.ClientID != s.ClientID {
+				return errorf(epp.EppAuthorisationError, "domain %s is not sponsored by %s", name, s.ClientID)
+			}
+
+			status := types.DomainTransferClientApproved
+			if cmd.Transfer.Operation == types.TransferOperationReject {
+				status = types.DomainTransferClientRejected
+			}
+
+			notices, err = r.completeDomainTransfer(tx, d, status, s.ClientID)
+		case types.TransferOperationCancel:
+			if d.Transfer.RequestingID != s.ClientID {
+				return errorf(epp.EppAuthorisationError, "transfer for domain %s was not requested by %s", name, s.ClientID)
+			}
+
+			notices, err = r.completeDomainTransfer(tx, d, types.DomainTransferClientCancelled, s.ClientID)
+		default:
+			return errorf(epp.EppParamPolicyError, "unknown transfer operation %s", cmd.Transfer.Operation)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.sendNotices(notices)
+
+	response := epp.NewResponse(code).WithResData(types.DomainTransferDataType{
+		TransferData: domainTransferData(d),
+	})
+
+	return withFee(response, types.FeeCommandTransfer, fee), nil
+}
+
+// 도메인 이전을 요청합니다. TransferPeriod 가 0 이면 요청은 서버에서 즉시 승인됩니다.
+func (r *Registry) requestDomainTransfer(tx Repository, s *epp.Session, d *Domain, transfer types.DomainTransfer) ([]notice, error) {
+	if d.ClientID == s.ClientID {
+		return nil, errorf(epp.EppNotTransferrable, "domain %s is already sponsored by %s", d.Name, s.ClientID)
+	}
+
+	if !r.validDomainAuthInfo(transfer.Authinfo, d) {
+		return nil, errorf(epp.EppInvalidAuthInfo, "invalid authorization information")
+	}
+
+	if d.hasStatus(types.DomainStatusClientTransferProhibited, types.DomainStatusServerTransferProhibited) {
+		return nil, errorf(epp.EppStatusProhibitsOp, "domain %s has status transfer prohibited", d.Name)
+	}
+
+	if d.hasStatus(types.DomainStatusPendingTransfer) {
+		return nil, errorf(epp.EppObjectPendingTransfer, "domain %s is already pending transfer", d.Name)
+	}
+
+	if d.hasStatus(pendingDomainStatuses...) {
+		return nil, errorf(epp.EppStatusProhibitsOp, "domain %s has a pending status", d.Name)
+	}
+
+	months, err := periodMonths(transfer.Period)
+	if err != nil {
+		return nil, err
+	}
+
+	now := r.now()
+	expireDate := d.ExpireDate.AddDate(0, months, 0)
+
+	if expireDate.After(now.AddDate(0, maxPeriodMonths, 0)) {
+		return nil, errorf(epp.EppParamPolicyError, "domain can not be registered for more than %d years", maxPeriodMonths/12)
+	}
+
+	// 대기중인 요청의 acID 와 acDate 는 요청을 처리해야 하는 클라이언트와 기한입니다.
+	d.Transfer = &Transfer{
+		Status:         types.DomainTransferPending,
+		RequestingID:   s.ClientID,
+		RequestingDate: now,
+		ActingID:       d.ClientID,
+		ActingDate:     now.Add(r.TransferPeriod),
+		ExpireDate:     &expireDate,
+	}
+
+	if r.TransferPeriod <= 0 {
+		return r.completeDomainTransfer(tx, d, types.DomainTransferServerApproved, d.ClientID)
+	}
+
+	d.Status = append(d.Status, types.DomainStatusPendingTransfer)
+
+	if err := tx.UpdateDomain(d); err != nil {
+		return nil, err
+	}
+
+	// 요청은 관리 클라이언트에게만 알립니다. 요청한 클라이언트는 응답으로 알 수 있습니다.
+	return domainTransferNotices(d, d.ClientID), nil
+}
+
+// 대기중인 이전 요청을 주어진 상태로 완료합니다. 승인된 경우 도메인과 종속된 호스트의
+// 관리 클라이언트가 바뀌고 요청한 기간만큼 만료일이 연장됩니다.
+func (r *Registry) completeDomainTransfer(tx Repository, d *Domain, status types.DomainTransferStatusType, actingID string) ([]notice, error) {
+	now := r.now()
+	losingID := d.ClientID
+
+	d.Transfer.Status = status
+	d.Transfer.ActingID = actingID
+	d.Transfer.ActingDate = now
+
+	if i := indexOfDomainStatus(d.Status, types.DomainStatusPendingTransfer); i >= 0 {
+		d.Status = append(d.Status[:i:i], d.Status[i+1:]...)
+	}
+
+	switch status {
+	case types.DomainTransferClientApproved, types.DomainTransferServerApproved:
+		r.addGracePeriod(d, GraceTransfer, now, r.Lifecycle.TransferGracePeriod, monthsBetween(d.ExpireDate, *d.Transfer.ExpireDate))
+
+		d.ClientID = d.Transfer.RequestingID
+		d.ExpireDate = *d.Transfer.ExpireDate
+		d.TransferDate = &now
+
+		r.clearDomainAuthInfo(d)
+	default:
+		// 이전되지 않은 도메인의 만료일은 바뀌지 않습니다.
+		d.Transfer.ExpireDate = nil
+	}
+
+	if err := tx.UpdateDomain(d); err != nil {
+		return nil, err
+	}
+
+	if d.ClientID != losingID {
+		// 종속된 호스트는 도메인과 함께 이전됩니다.
+		if err := transferSubordinateHosts(tx, d.Name, d.ClientID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return domainTransferNotices(d, d.Transfer.RequestingID, losingID), nil
+}
+
+// 자동 승인 기간이 지난 도메인의 이전 요청을 서버 승인으로 완료합니다.
+func (r *Registry) expireDomainTransfer(name string) error {
+	var notices []notice
+
+	err := r.Repository.Transaction(func(tx Repository) error {
+		d, err := tx.Domain(name)
+		if err != nil {
+			if errors.Cause(err) == ErrObjectNotFound {
+				return nil
+			}
+
+			return err
+		}
+
+		if d.Transfer == nil || d.Transfer.Status != types.DomainTransferPending || r.now().Before(d.Transfer.ActingDate) {
+			return nil
+		}
+
+		notices, err = r.completeDomainTransfer(tx, d, types.DomainTransferServerApproved, d.ClientID)
+
+		return err
+	})
+
+	if err != nil {
+		return err
+	}
+
+	r.sendNotices(notices)
+
+	return nil
+}
+
+// 자동 승인 기간이 지난 모든 도메인 이전 요청을 완료합니다. 주기적으로 실행해서
+// 이전 명령어를 받지 않은 도메인도 기간이 지나면 이전되도록 할 수 있습니다.
+func (r *Registry) ProcessTransfers() error {
+	names, err := r.Repository.DomainsByStatus(types.DomainStatusPendingTransfer)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := r.expireDomainTransfer(name); err != nil {
+			return errors.Wrapf(err, "could not process transfer for domain %s", name)
+		}
+	}
+
+	return nil
+}
+
+// 도메인의 현재 이전 상태를 클라이언트들에게 알리는 메시지를 생성합니다.
+func domainTransferNotices(d *Domain, clientIDs ...string) []notice {
+	data := domainTransferData(d)
+	notices := []notice{}
+
+	for _, clientID := range clientIDs {
+		notices = append(notices, notice{
+			clientID: clientID,
+			message:  fmt.Sprintf("Transfer %s.", d.Transfer.Status),
+			data:     &types.PollResultData{DomainTransferData: &data},
+		})
+	}
+
+	return notices
+}
+
+func domainTransferData(d *Domain) types.DomainTransferData {
+	return types.DomainTransferData{
+		Name:           d.Name,
+		TransferStatus: d.Transfer.Status,
+		RequestingID:   d.Transfer.RequestingID,
+		RequestingDate: d.Transfer.RequestingDate,
+		ActingID:       d.Transfer.ActingID,
+		ActingDate:     d.Transfer.ActingDate,
+		ExpireDate:     d.Transfer.ExpireDate,
+	}
+}
+
+func indexOfDomainStatus(status []types.DomainStatusType, want types.DomainStatusType) int {
+	for i, s := range status {
+		if s == want {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,152 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func domainTransfer(name string, op types.TransferOperation, password string) types.DomainTransferType {
+	cmd := types.DomainTransferType{
+		Transfer: types.DomainTransferCommand{
+			Operation: op,
+			Domain:    types.DomainTransfer{Name: name},
+		},
+	}
+
+	if password != "" {
+		cmd.Transfer.Domain.Authinfo = &types.AuthInfo{Password: password}
+	}
+
+	return cmd
+}
+
+// example.se 의 이전 명령어를 전송하고 응답의 resData 를 반환합니다.
+func (tr *testRegistry) transfer(s *epp.Session, want epp.ResultCode, op types.TransferOperation, password string) types.DomainTransferData {
+	tr.t.Helper()
+
+	result := types.DomainTransferDataType{}
+	response := tr.send(s, want, domainTransfer("example.se", op, password))
+
+	if want.Code() < 2000 {
+		decodeResData(tr.t, response, &result)
+	}
+
+	return result.TransferData
+}
+
+func (tr *testRegistry) messageCount(clientID string) int {
+	_, count, err := tr.registry.Poll.Oldest(clientID)
+	require.Nil(tr.t, err)
+
+	return count
+}
+
+func testRegistryTransferWorkflow(t *testing.T, tr *testRegistry) {
+	s := tr.login("ClientX")
+	other := tr.login("ClientY")
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+	tr.send(s, epp.EppOk, hostCreate("ns1.example.se", "192.0.2.1"))
+
+	expireDate := tr.now.AddDate(2, 0, 0)
+
+	// A request waits for the sponsoring client until the transfer period ends.
+	pending := tr.transfer(other, epp.EppOkPending, types.TransferOperationRequest, "2fooBAR")
+
+	assert.Equal(t, types.DomainTransferPending, pending.TransferStatus)
+	assert.Equal(t, "ClientY", pending.RequestingID)
+	assert.Equal(t, "ClientX", pending.ActingID)
+	assert.True(t, tr.now.Add(defaultTransferPeriod).Equal(pending.ActingDate))
+	assert.True(t, expireDate.AddDate(1, 0, 0).Equal(*pending.ExpireDate))
+	assert.Equal(t, 1, tr.messageCount("ClientX"))
+	assert.Equal(t, 0, tr.messageCount("ClientY"))
+
+	d, err := tr.registry.Repository.Domain("example.se")
+	require.Nil(t, err)
+	assert.True(t, d.hasStatus(types.DomainStatusPendingTransfer))
+
+	tr.transfer(other, epp.EppObjectPendingTransfer, types.TransferOperationRequest, "2fooBAR")
+	tr.send(s, epp.EppStatusProhibitsOp, types.DomainRenewType{
+		Renew: types.DomainRenew{Name: "example.se", ExpireDate: types.Date{Time: expireDate}},
+	})
+
+	// The sponsoring client rejects the request.
+	tr.transfer(other, epp.EppAuthorisationError, types.TransferOperationReject, "")
+
+	rejected := tr.transfer(s, epp.EppOk, types.TransferOperationReject, "")
+
+	assert.Equal(t, types.DomainTransferClientRejected, rejected.TransferStatus)
+	assert.Equal(t, "ClientX", rejected.ActingID)
+	assert.Nil(t, rejected.ExpireDate)
+	assert.Equal(t, 2, tr.messageCount("ClientX"))
+	assert.Equal(t, 1, tr.messageCount("ClientY"))
+
+	d, err = tr.registry.Repository.Domain("example.se")
+	require.Nil(t, err)
+	assert.False(t, d.hasStatus(types.DomainStatusPendingTransfer))
+	assert.Equal(t, "ClientX", d.ClientID)
+
+	// Only the requesting client may cancel.
+	tr.transfer(other, epp.EppOkPending, types.TransferOperationRequest, "2fooBAR")
+	tr.transfer(s, epp.EppAuthorisationError, types.TransferOperationCancel, "")
+
+	cancelled := tr.transfer(other, epp.EppOk, types.TransferOperationCancel, "")
+
+	assert.Equal(t, types.DomainTransferClientCancelled, cancelled.TransferStatus)
+	assert.Equal(t, "ClientY", cancelled.ActingID)
+	tr.transfer(other, epp.EppObjectNotPendingTransfer, types.TransferOperationCancel, "")
+
+	// Requests are approved by the server when the transfer period ends.
+	tr.transfer(other, epp.EppOkPending, types.TransferOperationRequest, "2fooBAR")
+
+	tr.now = tr.now.Add(defaultTransferPeriod - time.Second)
+	require.Nil(t, tr.registry.ProcessTransfers())
+	assert.Equal(t, types.DomainTransferPending, tr.transfer(other, epp.EppOk, types.TransferOperationQuery, "").TransferStatus)
+
+	tr.now = tr.now.Add(time.Second)
+	require.Nil(t, tr.registry.ProcessTransfers())
+
+	approved := tr.transfer(other, epp.EppOk, types.TransferOperationQuery, "")
+
+	assert.Equal(t, types.DomainTransferServerApproved, approved.TransferStatus)
+	assert.Equal(t, "ClientX", approved.ActingID)
+	assert.True(t, tr.now.Equal(approved.ActingDate))
+
+	d, err = tr.registry.Repository.Domain("example.se")
+	require.Nil(t, err)
+	assert.Equal(t, "ClientY", d.ClientID)
+	assert.True(t, expireDate.AddDate(1, 0, 0).Equal(d.ExpireDate))
+	assert.True(t, tr.now.Equal(*d.TransferDate))
+	assert.False(t, d.hasStatus(types.DomainStatusPendingTransfer))
+
+	h, err := tr.registry.Repository.Host("ns1.example.se")
+	require.Nil(t, err)
+	assert.Equal(t, "ClientY", h.ClientID)
+
+	// Both clients are notified of every completed transfer.
+	assert.Equal(t, 6, tr.messageCount("ClientX"))
+	assert.Equal(t, 3, tr.messageCount("ClientY"))
+
+	m, _, err := tr.registry.Poll.Oldest("ClientY")
+	require.Nil(t, err)
+	assert.Equal(t, types.DomainTransferClientRejected, m.Data.DomainTransferData.TransferStatus)
+
+	// Expired requests are also completed when the domain is transferred again.
+	tr.transfer(s, epp.EppOkPending, types.TransferOperationRequest, "2fooBAR")
+	tr.now = tr.now.Add(defaultTransferPeriod)
+	tr.transfer(other, epp.EppObjectNotPendingTransfer, types.TransferOperationApprove, "")
+	assert.Equal(t, types.DomainTransferServerApproved, tr.transfer(s, epp.EppOk, types.TransferOperationQuery, "").TransferStatus)
+
+	// Without a transfer period requests are approved immediately.
+	tr.registry.TransferPeriod = 0
+
+	immediate := tr.transfer(other, epp.EppOk, types.TransferOperationRequest, "2fooBAR")
+	assert.Equal(t, types.DomainTransferServerApproved, immediate.TransferStatus)
+	assert.True(t, expireDate.AddDate(3, 0, 0).Equal(*immediate.ExpireDate))
+}
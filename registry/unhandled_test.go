@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"testing"
+
+	epp "github.com/bombsimon/epp-go"
+	"github.com/bombsimon/epp-go/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRegistryUnhandledNamespaces(t *testing.T, tr *testRegistry) {
+	login := func(clientID string, extensions ...string) *epp.Session {
+		s := &epp.Session{}
+
+		tr.send(s, epp.EppOk, types.Login{
+			ClientID: clientID,
+			Password: "secret",
+			Options:  types.LoginOptions{Version: "1.0", Language: "en"},
+			Services: types.LoginServices{
+				ObjectURI:        []string{types.NameSpaceDomain, types.NameSpaceContact},
+				ServiceExtension: &types.LoginServiceExtension{ExtensionURI: extensions},
+			},
+		})
+
+		return s
+	}
+
+	s := login("ClientX", types.NameSpaceUnhandledNamespaces10)
+
+	greeting, err := tr.registry.Greeting(s)
+	require.Nil(t, err)
+	assert.Contains(t, string(greeting), types.NameSpaceUnhandledNamespaces10)
+
+	tr.send(s, epp.EppOk, contactCreate("jd1234"))
+	tr.send(s, epp.EppOk, domainCreate("example.se"))
+
+	info := types.DomainInfoType{Info: types.DomainInfo{Name: types.DomainInfoName{Name: "example.se"}}}
+
+	// The rgp extension is not in the login services and is returned as
+	// extValue in the result instead.
+	response := tr.send(s, epp.EppOk, info)
+	assert.NotContains(t, string(response), "<extension>")
+	assert.Empty(t, rgpStatus(t, response))
+
+	result := types.Response{}
+	require.Nil(t, epp.Decode(response, &result))
+	require.Len(t, result.Result[0].ExternalValue, 1)
+	assert.Equal(t, types.NameSpaceRGP10+" not in login services", result.Result[0].ExternalValue[0].Reason)
+
+	infoData := types.DomainInfoDataType{}
+	decodeResData(t, response, &infoData)
+	assert.Equal(t, "example.se", infoData.InfoData.Name)
+
+	// Clients with the extension in the login services get the extension.
+	handled := login("ClientX", types.NameSpaceUnhandledNamespaces10, types.NameSpaceRGP10)
+
+	response = tr.send(handled, epp.EppOk, info)
+	assert.NotContains(t, string(response), "<extValue>")
+	assert.Equal(t, []types.RGPStatusType{types.RGPStatusAddPeriod}, rgpStatus(t, response))
+}
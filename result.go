@@ -149,8 +149,11 @@ func CreateErrorResponse(code ResultCode, reason string) types.Response {
 			{
 				Code:    code.Code(),
 				Message: code.Message(),
-				ExternalValue: &types.ExternalErrorValue{
-					Reason: reason,
+				ExternalValue: []types.ExternalErrorValue{
+					{
+						Value:  types.UndefinedValue{Undefined: types.Empty()},
+						Reason: reason,
+					},
 				},
 			},
 		},
@@ -24,6 +24,10 @@ type Server struct {
 	// 인증서나 클라이언트 인증 등과 같은 설정을 가진 TLS 설정입니다.
 	TLSConfig *tls.Config
 
+	// 설정되면 TLSConfig 의 인증서와 클라이언트 CA 대신 사용하며, 서버가 실행되는 동안
+	// SIGHUP 을 받거나 파일이 변경되면 새로운 세션에 사용할 인증서를 다시 불러옵니다.
+	Certificates *CertificateReloader
+
 	// 현재 활성화되어 있는 모든 세션입니다.
 	Sessions map[string]*Session
 
@@ -79,6 +83,12 @@ func (s *Server) Serve(l *net.TCPListener) error {
 		tlsConfig = s.TLSConfig.Clone()
 	}
 
+	if s.Certificates != nil {
+		tlsConfig = s.Certificates.TLSConfig(tlsConfig)
+
+		go s.Certificates.Watch(s.stopChan)
+	}
+
 	// 서버가 실행될 때마다 수행할 사용자 정의 함수를 실행합니다.
 	for _, f := range s.OnStarteds {
 		f()
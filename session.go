@@ -2,10 +2,12 @@ package epp
 
 import (
 	"crypto/tls"
+	"crypto/x509"
 	"log"
 	"net"
 	"time"
 
+	"github.com/bombsimon/epp-go/types"
 	"github.com/google/uuid"
 	xsd "github.com/lestrrat-go/libxml2/xsd"
 )
@@ -38,6 +40,18 @@ type SessionConfig struct {
 	// libxml2 바인딩을 사용하여 구현한 type 인터페이스를 라이브러리에서 사용할 수 있습니다.
 	Validator Validator
 
+	// 헤더를 포함하여 클라이언트가 보낼 수 있는 메시지의 최대 크기입니다.
+	// 0 이면 제한하지 않으며 더 큰 메시지를 받으면 세션을 종료합니다.
+	MaxFrameSize int
+
+	// 설정되면 greeting 을 보내기 전에 클라이언트 인증서를 검증하고, 로그인 명령어를
+	// 핸들러에 전달하기 전에 인증서와 주소가 로그인하는 clID 의 것인지 확인합니다.
+	ClientCertPolicy *ClientCertPolicy
+
+	// 서버가 지원하는 확장의 네임스페이스입니다. 세션의 Extensions 로 전달되어 Greeting
+	// 함수가 svcExtension 에 알릴 수 있습니다.
+	Extensions []string
+
 	// 각 명령어를 통해 실행될 함수들입니다.
 	// 각 명령어 뒤에 처리할 외부 코드를 넣는 곳입니다.
 	OnCommands []func(sess *Session)
@@ -51,6 +65,18 @@ type Session struct {
 	// 특정 세션을 구별하기 위해 사용되는 고유한 ID입니다.
 	SessionID string
 
+	// 로그인에 성공한 클라이언트의 ID 입니다. 로그인 전에는 빈 문자열입니다.
+	// 로그인 명령어를 처리하는 핸들러에서 설정해야 합니다.
+	ClientID string
+
+	// 로그인에서 클라이언트가 협상한 개체와 확장의 네임스페이스입니다. 로그인 전에는
+	// nil 입니다. ResponseBuilder.WithLoginServices 에 전달해 협상하지 않은
+	// 네임스페이스를 extValue 로 옮길 수 있습니다.
+	Services *types.LoginServices
+
+	// 서버가 지원하는 확장의 네임스페이스입니다. SessionConfig.Extensions 로 설정됩니다.
+	Extensions []string
+
 	// 클라이언트와의 TCP 연결을 유지하는데 사용됩니다.
 	conn net.Conn
 
@@ -64,6 +90,8 @@ type Session struct {
 	handler        HandlerFunc
 	onCommands     []func(sess *Session)
 	validator      Validator
+	maxFrameSize   int
+	certPolicy     *ClientCertPolicy
 }
 
 // 새로운 세션을 생성합니다.
@@ -81,6 +109,9 @@ func NewSession(conn *tls.Conn, cfg SessionConfig) *Session {
 		handler:         cfg.Handler,
 		onCommands:      cfg.OnCommands,
 		validator:       cfg.Validator,
+		maxFrameSize:    cfg.MaxFrameSize,
+		certPolicy:      cfg.ClientCertPolicy,
+		Extensions:      cfg.Extensions,
 	}
 
 	return s
@@ -90,6 +121,12 @@ func NewSession(conn *tls.Conn, cfg SessionConfig) *Session {
 func (s *Session) run() error {
 	defer s.conn.Close()
 
+	if s.certPolicy != nil {
+		if err := s.certPolicy.VerifyConnection(s.ConnectionState()); err != nil {
+			return err
+		}
+	}
+
 	// greeting 프로세스를 처리하기 위해 클라이언트에게 보낼 greeting 을 생성합니다. (RFC5730 2.4)
 	response, err := s.greeting(s)
 	if err != nil {
@@ -137,7 +174,7 @@ func (s *Session) run() error {
 		}
 
 		// Socket을 읽었을 때 오류가 있다면 Socket에 아무런 활동이 없는한 무시합니다.
-		message, err := ReadMessage(s.conn)
+		message, err := readMessage(s.conn, s.maxFrameSize)
 		if err != nil {
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 				continue
@@ -157,6 +194,13 @@ func (s *Session) run() error {
 			return err
 		}
 
+		// 인증서가 로그인하는 clID 의 것이 아니면 핸들러를 실행하지 않고 연결을 종료합니다.
+		if s.certPolicy != nil {
+			if response, ok := s.verifyLogin(message); !ok {
+				return WriteMessage(s.conn, response)
+			}
+		}
+
 		// 핸들러에 내용을 전달하여 작업을 수행하게 하거나 라우팅하게 만듭니다.
 		response, err = s.handler(s, message)
 		if err != nil {
@@ -208,3 +252,47 @@ func (s *Session) validate(data []byte) error {
 
 	return nil
 }
+
+// 클라이언트가 보낸 인증서를 반환합니다. 인증서가 없으면 nil 을 반환합니다.
+func (s *Session) peerCertificate() *x509.Certificate {
+	if s.ConnectionState == nil {
+		return nil
+	}
+
+	certs := s.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil
+	}
+
+	return certs[0]
+}
+
+// 로그인 명령어이면 인증서 정책으로 clID 를 확인합니다. 확인에 실패하면 클라이언트에게
+// 보낼 응답과 false 를 반환합니다.
+func (s *Session) verifyLogin(message []byte) ([]byte, bool) {
+	login := types.Login{}
+
+	if err := Decode(message, &login); err != nil || login.ClientID == "" {
+		return nil, true
+	}
+
+	err := s.certPolicy.VerifyLogin(login.ClientID, s.peerCertificate(), s.conn.RemoteAddr())
+	if err == nil {
+		return nil, true
+	}
+
+	log.Printf("login as %s rejected: %s", login.ClientID, err.Error())
+
+	trID := types.ClientTransactionIDType{}
+	_ = Decode(message, &trID)
+
+	response, err := NewResponse(EppAuthFailedBye).
+		WithReason("client certificate or address is not allowed").
+		WithTrID(trID.ClientTransactionID, s.SessionID).
+		Encode()
+	if err != nil {
+		log.Println(err.Error())
+	}
+
+	return response, false
+}
@@ -0,0 +1,26 @@
+package types
+
+// Name space constant for the extension.
+const (
+	NameSpaceAllocationToken10 = "urn:ietf:params:xml:ns:allocationToken-1.0"
+)
+
+// AllocationTokenExtensionType represents the allocationToken tag from the
+// allocationToken-1.0 extension. The same tag is used for the check, create,
+// transfer and update commands.
+type AllocationTokenExtensionType struct {
+	AllocationToken string `xml:"urn:ietf:params:xml:ns:allocationToken-1.0 command>extension>allocationToken"`
+}
+
+// AllocationTokenExtensionInfoType represents the info tag from the
+// allocationToken-1.0 extension used to request the allocation token of an
+// object.
+type AllocationTokenExtensionInfoType struct {
+	Info *EmptyTag `xml:"urn:ietf:params:xml:ns:allocationToken-1.0 command>extension>info"`
+}
+
+// AllocationTokenExtensionInfoDataType represents the allocationToken tag
+// returned in an info response.
+type AllocationTokenExtensionInfoDataType struct {
+	AllocationToken string `xml:"urn:ietf:params:xml:ns:allocationToken-1.0 allocationToken"`
+}
@@ -0,0 +1,24 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/allocationtoken.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// AllocationTokenExtensionTypeIn represents a namespace agnostic version of AllocationTokenExtensionType
+type AllocationTokenExtensionTypeIn struct {
+	AllocationToken string `xml:"command>extension>allocationToken"`
+}
+
+// AllocationTokenExtensionInfoTypeIn represents a namespace agnostic version of AllocationTokenExtensionInfoType
+type AllocationTokenExtensionInfoTypeIn struct {
+	Info *EmptyTag `xml:"command>extension>info"`
+}
+
+// AllocationTokenExtensionInfoDataTypeIn represents a namespace agnostic version of AllocationTokenExtensionInfoDataType
+type AllocationTokenExtensionInfoDataTypeIn struct {
+	AllocationToken string `xml:"allocationToken"`
+}
@@ -0,0 +1,78 @@
+package types
+
+import "time"
+
+// Name space constant for the extension.
+const (
+	NameSpaceChangePoll10 = "urn:ietf:params:xml:ns:changePoll-1.0"
+)
+
+// Constants representing the operations from RFC 8590.
+const (
+	ChangePollOperationCreate     ChangePollOperationType = "create"
+	ChangePollOperationDelete     ChangePollOperationType = "delete"
+	ChangePollOperationRenew      ChangePollOperationType = "renew"
+	ChangePollOperationTransfer   ChangePollOperationType = "transfer"
+	ChangePollOperationUpdate     ChangePollOperationType = "update"
+	ChangePollOperationRestore    ChangePollOperationType = "restore"
+	ChangePollOperationAutoRenew  ChangePollOperationType = "autoRenew"
+	ChangePollOperationAutoDelete ChangePollOperationType = "autoDelete"
+	ChangePollOperationAutoPurge  ChangePollOperationType = "autoPurge"
+	ChangePollOperationCustom     ChangePollOperationType = "custom"
+)
+
+// Constants representing if the object data is from before or after the
+// change.
+const (
+	ChangePollStateBefore ChangePollStateType = "before"
+	ChangePollStateAfter  ChangePollStateType = "after"
+)
+
+// Constants representing the type of a case.
+const (
+	ChangePollCaseUDRP   ChangePollCaseType = "udrp"
+	ChangePollCaseURS    ChangePollCaseType = "urs"
+	ChangePollCaseCustom ChangePollCaseType = "custom"
+)
+
+// ChangePollOperationType represents the operation that changed an object.
+type ChangePollOperationType string
+
+// ChangePollStateType represents the state of the object data.
+type ChangePollStateType string
+
+// ChangePollCaseType represents the type of a case.
+type ChangePollCaseType string
+
+// ChangePollExtensionChangeDataType represents the changeData tag from the
+// changePoll-1.0 extension.
+type ChangePollExtensionChangeDataType struct {
+	ChangeData ChangePollChangeData `xml:"urn:ietf:params:xml:ns:changePoll-1.0 changeData"`
+}
+
+// ChangePollChangeData represents a change of an object made by the server.
+// The object data is passed as result data in the same poll message.
+type ChangePollChangeData struct {
+	State               ChangePollStateType  `xml:"state,attr,omitempty"`
+	Operation           ChangePollOperation  `xml:"operation"`
+	Date                time.Time            `xml:"date"`
+	ServerTransactionID string               `xml:"svTRID"`
+	Who                 string               `xml:"who"`
+	CaseID              *ChangePollCaseID    `xml:"caseId,omitempty"`
+	Reason              *MessageQueueMessage `xml:"reason,omitempty"`
+}
+
+// ChangePollOperation represents the operation. The op attribute holds the
+// name of a custom operation or a sub operation.
+type ChangePollOperation struct {
+	Operation ChangePollOperationType `xml:",chardata"`
+	Op        string                  `xml:"op,attr,omitempty"`
+}
+
+// ChangePollCaseID represents the case that caused the change. The name is
+// used when the type is custom.
+type ChangePollCaseID struct {
+	Type  ChangePollCaseType `xml:"type,attr"`
+	Name  string             `xml:"name,attr,omitempty"`
+	Value string             `xml:",chardata"`
+}
@@ -0,0 +1,14 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/changepoll.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// ChangePollExtensionChangeDataTypeIn represents a namespace agnostic version of ChangePollExtensionChangeDataType
+type ChangePollExtensionChangeDataTypeIn struct {
+	ChangeData ChangePollChangeData `xml:"changeData"`
+}
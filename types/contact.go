@@ -65,12 +65,12 @@ type ContactInfoType struct {
 
 // ContactTransferType represents a contact transfer command.
 type ContactTransferType struct {
-	Transfer ContactTransfer `xml:"urn:ietf:params:xml:ns:contact-1.0 command>transfer>transfer"`
+	Transfer ContactTransferCommand `xml:"command>transfer"`
 }
 
 // ContactUpdateType represents a contact update command.
 type ContactUpdateType struct {
-	Update ContactUpdate `xml:"urn:ietf:params:xml:ns:contact-1.0 command>transfer>transfer"`
+	Update ContactUpdate `xml:"urn:ietf:params:xml:ns:contact-1.0 command>update>update"`
 }
 
 // ContactCheckDataType represents contact check data.
@@ -108,11 +108,11 @@ type ContactCheck struct {
 type ContactCreate struct {
 	ID         string       `xml:"id"`
 	PostalInfo []PostalInfo `xml:"postalInfo"`
-	Voice      E164Type     `xml:"voice,omitempty"`
-	Fax        E164Type     `xml:"fax,omitempty"`
+	Voice      *E164Type    `xml:"voice,omitempty"`
+	Fax        *E164Type    `xml:"fax,omitempty"`
 	Email      string       `xml:"email"`
 	AuthInfo   AuthInfo     `xml:"authInfo"`
-	Disclose   Disclose     `xml:"disclose,omitempty"`
+	Disclose   *Disclose    `xml:"disclose,omitempty"`
 }
 
 // ContactDelete represents a contact delete command.
@@ -122,14 +122,21 @@ type ContactDelete struct {
 
 // ContactInfo represents a contact info command.
 type ContactInfo struct {
-	Name     string   `xml:"id"`
-	AuthInfo AuthInfo `xml:"authInfo,omitempty"`
+	Name     string    `xml:"id"`
+	AuthInfo *AuthInfo `xml:"authInfo,omitempty"`
+}
+
+// ContactTransferCommand represents the transfer command tag holding the
+// operation to perform and the contact to transfer.
+type ContactTransferCommand struct {
+	Operation TransferOperation `xml:"op,attr"`
+	Contact   ContactTransfer   `xml:"urn:ietf:params:xml:ns:contact-1.0 transfer"`
 }
 
 // ContactTransfer represents a contact transfer command.
 type ContactTransfer struct {
-	Name     string   `xml:"id"`
-	AuthInfo AuthInfo `xml:"authInfo,omitempty"`
+	Name     string    `xml:"id"`
+	AuthInfo *AuthInfo `xml:"authInfo,omitempty"`
 }
 
 // ContactUpdate represents a contact update command.
@@ -137,7 +144,7 @@ type ContactUpdate struct {
 	Name   string            `xml:"id"`
 	Add    *ContactAddRemove `xml:"add,omitempty"`
 	Remove *ContactAddRemove `xml:"rem,omitempty"`
-	Change *ContactChange    `xml:"chg>name,omitempty"`
+	Change *ContactChange    `xml:"chg,omitempty"`
 }
 
 // ContactCheckData represents the data returned from a contact check command.
@@ -157,24 +164,24 @@ type ContactInfoData struct {
 	ROID         string          `xml:"roid"`
 	Status       []ContactStatus `xml:"status"`
 	PostalInfo   []PostalInfo    `xml:"postalInfo"`
-	Voice        E164Type        `xml:"voice,omitempty"`
-	Fax          E164Type        `xml:"fax,omitempty"`
+	Voice        *E164Type       `xml:"voice,omitempty"`
+	Fax          *E164Type       `xml:"fax,omitempty"`
 	Email        string          `xml:"email"`
 	ClientID     string          `xml:"clID"`
 	CreateID     string          `xml:"crID"`
 	CreateDate   time.Time       `xml:"crDate"`
 	UpdateID     string          `xml:"upID,omitempty"`
-	UpdateDate   time.Time       `xml:"upDate,omitempty"`
-	TransferDate time.Time       `xml:"trDate,omitempty"`
-	AuthInfo     AuthInfo        `xml:"authInfo,omitempty"`
-	Disclose     Disclose        `xml:"disclose,omitempty"`
+	UpdateDate   *time.Time      `xml:"upDate,omitempty"`
+	TransferDate *time.Time      `xml:"trDate,omitempty"`
+	AuthInfo     *AuthInfo       `xml:"authInfo,omitempty"`
+	Disclose     *Disclose       `xml:"disclose,omitempty"`
 }
 
 // ContactPendingActivationNotificationData represents the data returned from a
 // contact pending activation notification command.
 type ContactPendingActivationNotificationData struct {
 	Name          PendingActivationNotificationName `xml:"id"`
-	TransactionID string                            `xml:"paTRID"`
+	TransactionID PendingActivationTransactionID    `xml:"paTRID"`
 	Date          time.Time                         `xml:"paDate"`
 }
 
@@ -205,23 +212,24 @@ type ContactAddRemove struct {
 // contact.
 type ContactChange struct {
 	PostalInfo []PostalInfo `xml:"postalInfo,omitempty"`
-	Voice      E164Type     `xml:"voice,omitempty"`
-	Fax        E164Type     `xml:"fax,omitempty"`
+	Voice      *E164Type    `xml:"voice,omitempty"`
+	Fax        *E164Type    `xml:"fax,omitempty"`
 	Email      string       `xml:"email,omitempty"`
-	AuthInfo   AuthInfo     `xml:"authInfo,omitempty"`
-	Disclose   Disclose     `xml:"disclose,omitempty,omitempty"`
+	AuthInfo   *AuthInfo    `xml:"authInfo,omitempty"`
+	Disclose   *Disclose    `xml:"disclose,omitempty"`
 }
 
 // ContactStatus represents statuses for a contact.
 type ContactStatus struct {
 	Status            string            `xml:",chardata"`
 	ContactStatusType ContactStatusType `xml:"s,attr"`
-	Language          string            `xml:"lang,attr"`
+	Language          string            `xml:"lang,attr,omitempty"`
 }
 
-// PostalInfo represents potal information for a contact.
+// PostalInfo represents potal information for a contact. The name is only
+// optional when changing postal info in a contact update.
 type PostalInfo struct {
-	Name         string         `xml:"name"`
+	Name         string         `xml:"name,omitempty"`
 	Organization string         `xml:"org,omitempty"`
 	Address      Address        `xml:"addr"`
 	Type         PostalInfoType `xml:"type,attr"`
@@ -242,15 +250,16 @@ type E164Type struct {
 	X     string `xml:"x,attr"`
 }
 
-// Disclose represents fields that may be disclosed to the public.
+// Disclose represents fields that may be disclosed to the public. Each field is
+// included in the policy if the tag is set.
 type Disclose struct {
-	Name         InternationalOrLocalType `xml:"name,omitempty"`
-	Organization InternationalOrLocalType `xml:"org,omitempty"`
-	Address      InternationalOrLocalType `xml:"addr,omitempty"`
-	Voice        bool                     `xml:"voice,omitempty"`
-	Fax          bool                     `xml:"fax,omitempty"`
-	Email        bool                     `xml:"email,omitempty"`
-	Flag         bool                     `xml:"flag,attr"`
+	Name         []InternationalOrLocalType `xml:"name,omitempty"`
+	Organization []InternationalOrLocalType `xml:"org,omitempty"`
+	Address      []InternationalOrLocalType `xml:"addr,omitempty"`
+	Voice        *EmptyTag                  `xml:"voice,omitempty"`
+	Fax          *EmptyTag                  `xml:"fax,omitempty"`
+	Email        *EmptyTag                  `xml:"email,omitempty"`
+	Flag         bool                       `xml:"flag,attr"`
 }
 
 // InternationalOrLocalType represents a value with a type set to an available
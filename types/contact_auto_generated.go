@@ -28,14 +28,9 @@ type ContactInfoTypeIn struct {
 	Info ContactInfo `xml:"command>info>info"`
 }
 
-// ContactTransferTypeIn represents a namespace agnostic version of ContactTransferType
-type ContactTransferTypeIn struct {
-	Transfer ContactTransfer `xml:"command>transfer>transfer"`
-}
-
 // ContactUpdateTypeIn represents a namespace agnostic version of ContactUpdateType
 type ContactUpdateTypeIn struct {
-	Update ContactUpdate `xml:"command>transfer>transfer"`
+	Update ContactUpdate `xml:"command>update>update"`
 }
 
 // ContactCheckDataTypeIn represents a namespace agnostic version of ContactCheckDataType
@@ -24,23 +24,23 @@ type DNSSECExtensionInfoDataType struct {
 // DNSSECOrKeyData represents DNSSEC data or key data.
 type DNSSECOrKeyData struct {
 	MaxSignatureLife int             `xml:"maxSigLife,omitempty"`
-	DNSSECData       []DNSSEC        `xml:"dsData"`
-	KeyData          []DNSSECKeyData `xml:"keyData"`
+	DNSSECData       []DNSSEC        `xml:"dsData,omitempty"`
+	KeyData          []DNSSECKeyData `xml:"keyData,omitempty"`
 }
 
 // DNSSECExtensionUpdate implements extension for update from secDNS-1.1
 type DNSSECExtensionUpdate struct {
-	Remove                 DNSSECRemove    `xml:"rem,omitempty"`
-	Add                    DNSSECOrKeyData `xml:"add,omitempty"`
-	ChangeMaxSignatureLife int             `xml:"chg>maxSigLife,omitempty"`
-	Urgent                 bool            `xml:"urgent,attr"`
+	Remove                 *DNSSECRemove    `xml:"rem,omitempty"`
+	Add                    *DNSSECOrKeyData `xml:"add,omitempty"`
+	ChangeMaxSignatureLife int              `xml:"chg>maxSigLife,omitempty"`
+	Urgent                 bool             `xml:"urgent,attr,omitempty"`
 }
 
 // DNSSECRemove represents remove block for DNSSEC extension.
 type DNSSECRemove struct {
 	All        bool            `xml:"all,omitempty"`
-	DNSSECdata []DNSSEC        `xml:"dsData"`
-	KeyData    []DNSSECKeyData `xml:"keyData"`
+	DNSSECdata []DNSSEC        `xml:"dsData,omitempty"`
+	KeyData    []DNSSECKeyData `xml:"keyData,omitempty"`
 }
 
 // DNSSEC represents DNSSEC data.
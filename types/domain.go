@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"encoding/xml"
+	"time"
+)
 
 // DomainStatusType represents available status values.
 type DomainStatusType string
@@ -62,7 +65,7 @@ type DomainCreateType struct {
 
 // DomainDeleteType implements extension for delete from domain-1.0.
 type DomainDeleteType struct {
-	Delete DomainDelete `xml:"urn:ietf:params:xml:ns:domain-1.0 command>create>delete"`
+	Delete DomainDelete `xml:"urn:ietf:params:xml:ns:domain-1.0 command>delete>delete"`
 }
 
 // DomainInfoType implements extension for info from domain-1.0.
@@ -77,7 +80,7 @@ type DomainRenewType struct {
 
 // DomainTransferType implements extension for transfer from domain-1.0.
 type DomainTransferType struct {
-	Transfer DomainTransfer `xml:"urn:ietf:params:xml:ns:domain-1.0 command>transfer>transfer"`
+	Transfer DomainTransferCommand `xml:"command>transfer"`
 }
 
 // DomainUpdateType implements extension for update from domain-1.0.
@@ -123,12 +126,12 @@ type DomainCheck struct {
 
 // DomainCreate represents a domain create command.
 type DomainCreate struct {
-	Name       string     `xml:"name"`
-	Period     Period     `xml:"period,omitempty"`
-	NameServer NameServer `xml:"ns,omitempty"`
-	Registrant string     `xml:"registrant,omitempty"`
-	Contacts   []Contact  `xml:"contact,omitempty"`
-	AuthInfo   *AuthInfo  `xml:"authInfo,omitempty"`
+	Name       string      `xml:"name"`
+	Period     *Period     `xml:"period,omitempty"`
+	NameServer *NameServer `xml:"ns,omitempty"`
+	Registrant string      `xml:"registrant,omitempty"`
+	Contacts   []Contact   `xml:"contact,omitempty"`
+	AuthInfo   *AuthInfo   `xml:"authInfo,omitempty"`
 }
 
 // DomainDelete represents a domain delete command.
@@ -150,29 +153,36 @@ type DomainInfoName struct {
 
 // DomainRenew represents a domain renew command.
 type DomainRenew struct {
-	Name       string    `xml:"name"`
-	ExpireDate time.Time `xml:"curExpDate"`
-	Period     Period    `xml:"period,omitempty"`
+	Name       string  `xml:"name"`
+	ExpireDate Date    `xml:"curExpDate"`
+	Period     *Period `xml:"period,omitempty"`
 }
 
 // DomainTransfer represents a domain transfer command.
 type DomainTransfer struct {
-	Name     string    `xml:"command>transfer>transfer>name"`
-	Period   Period    `xml:"command>transfer>transfer>period,omitempty"`
-	Authinfo *AuthInfo `xml:"command>transfer>transfer>authInfo,omitempty"`
+	Name     string    `xml:"name"`
+	Period   *Period   `xml:"period,omitempty"`
+	Authinfo *AuthInfo `xml:"authInfo,omitempty"`
+}
+
+// DomainTransferCommand represents the transfer command tag holding the
+// operation to perform and the domain to transfer.
+type DomainTransferCommand struct {
+	Operation TransferOperation `xml:"op,attr"`
+	Domain    DomainTransfer    `xml:"urn:ietf:params:xml:ns:domain-1.0 transfer"`
 }
 
 // DomainUpdate represents a domain update command.
 type DomainUpdate struct {
-	Name   string           `xml:"command>update>update>name"`
-	Add    *DomainAddRemove `xml:"command>update>update>add,omitempty"`
-	Remove *DomainAddRemove `xml:"command>update>update>rem,omitempty"`
-	Change *DomainChange    `xml:"command>update>update>chg,omitempty"`
+	Name   string           `xml:"name"`
+	Add    *DomainAddRemove `xml:"add,omitempty"`
+	Remove *DomainAddRemove `xml:"rem,omitempty"`
+	Change *DomainChange    `xml:"chg,omitempty"`
 }
 
 // DomainAddRemove ...
 type DomainAddRemove struct {
-	NameServer NameServer     `xml:"ns,omitempty"`
+	NameServer *NameServer    `xml:"ns,omitempty"`
 	Contact    []Contact      `xml:"contact,omitempty"`
 	Status     []DomainStatus `xml:"status,omitempty"`
 }
@@ -211,6 +221,24 @@ type Contact struct {
 type AuthInfo struct {
 	Password  string `xml:"pw,omitempty"`
 	Extension string `xml:"ext,omitempty"`
+
+	// Null unsets the authorization information in a domain update.
+	Null *EmptyTag `xml:"null,omitempty"`
+}
+
+// MarshalXML encodes authorization information without a password, extension
+// or null as an empty pw element. Info responses use it to tell the sponsoring
+// client that authorization information is set without returning it (RFC 9154).
+func (a AuthInfo) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if a.Password == "" && a.Extension == "" && a.Null == nil {
+		return e.EncodeElement(struct {
+			Password string `xml:"pw"`
+		}{}, start)
+	}
+
+	type authInfo AuthInfo
+
+	return e.EncodeElement(authInfo(a), start)
 }
 
 // DomainCheckData represents the response data for a domain check command.
@@ -220,9 +248,9 @@ type DomainCheckData struct {
 
 // DomainCreateData represents the response data for a domain create command.
 type DomainCreateData struct {
-	Name       string    `xml:"name"`
-	CreateDate time.Time `xml:"crDate"`
-	ExpireDate time.Time `xml:"exDate"`
+	Name       string     `xml:"name"`
+	CreateDate time.Time  `xml:"crDate"`
+	ExpireDate *time.Time `xml:"exDate,omitempty"`
 }
 
 // DomainInfoData represents the response data for a domain info command.
@@ -248,7 +276,7 @@ type DomainInfoData struct {
 // domain pan command.
 type DomainPendingActivationNotificationData struct {
 	Name          PendingActivationNotificationName `xml:"name"`
-	TransactionID string                            `xml:"paTRID"`
+	TransactionID PendingActivationTransactionID    `xml:"paTRID"`
 	Date          time.Time                         `xml:"paDate"`
 }
 
@@ -263,10 +291,10 @@ type DomainTransferData struct {
 	Name           string                   `xml:"name"`
 	TransferStatus DomainTransferStatusType `xml:"trStatus"`
 	RequestingID   string                   `xml:"reID"`
-	RequestingDate string                   `xml:"reDate"`
+	RequestingDate time.Time                `xml:"reDate"`
 	ActingID       string                   `xml:"acID"`
-	ActingDate     string                   `xml:"acDate"`
-	ExpireDate     string                   `xml:"exDate,omitempty"`
+	ActingDate     time.Time                `xml:"acDate"`
+	ExpireDate     *time.Time               `xml:"exDate,omitempty"`
 }
 
 // DomainStatus represents statuses for a domain.
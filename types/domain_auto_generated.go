@@ -20,7 +20,7 @@ type DomainCreateTypeIn struct {
 
 // DomainDeleteTypeIn represents a namespace agnostic version of DomainDeleteType
 type DomainDeleteTypeIn struct {
-	Delete DomainDelete `xml:"command>create>delete"`
+	Delete DomainDelete `xml:"command>delete>delete"`
 }
 
 // DomainInfoTypeIn represents a namespace agnostic version of DomainInfoType
@@ -33,11 +33,6 @@ type DomainRenewTypeIn struct {
 	Renew DomainRenew `xml:"command>renew>renew"`
 }
 
-// DomainTransferTypeIn represents a namespace agnostic version of DomainTransferType
-type DomainTransferTypeIn struct {
-	Transfer DomainTransfer `xml:"command>transfer>transfer"`
-}
-
 // DomainUpdateTypeIn represents a namespace agnostic version of DomainUpdateType
 type DomainUpdateTypeIn struct {
 	Update DomainUpdate `xml:"command>update>update"`
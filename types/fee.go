@@ -0,0 +1,180 @@
+package types
+
+// Name space constant for the extension.
+const (
+	NameSpaceFee10 = "urn:ietf:params:xml:ns:epp:fee-1.0"
+)
+
+// Constants representing the commands a fee can be queried for.
+const (
+	FeeCommandCreate   FeeCommandName = "create"
+	FeeCommandDelete   FeeCommandName = "delete"
+	FeeCommandRenew    FeeCommandName = "renew"
+	FeeCommandUpdate   FeeCommandName = "update"
+	FeeCommandTransfer FeeCommandName = "transfer"
+	FeeCommandRestore  FeeCommandName = "restore"
+	FeeCommandCustom   FeeCommandName = "custom"
+)
+
+// Constants representing when a fee is applied.
+const (
+	FeeAppliedImmediate FeeAppliedType = "immediate"
+	FeeAppliedDelayed   FeeAppliedType = "delayed"
+)
+
+// FeeCommandName represents the name of a command in the fee extension.
+type FeeCommandName string
+
+// FeeAppliedType represents when a fee is applied.
+type FeeAppliedType string
+
+// FeeExtensionCheckType represents the check tag from the fee-1.0 extension.
+type FeeExtensionCheckType struct {
+	Check FeeCheck `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 command>extension>check"`
+}
+
+// FeeExtensionCreateType represents the create tag from the fee-1.0
+// extension.
+type FeeExtensionCreateType struct {
+	Create FeeTransformCommand `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 command>extension>create"`
+}
+
+// FeeExtensionRenewType represents the renew tag from the fee-1.0 extension.
+type FeeExtensionRenewType struct {
+	Renew FeeTransformCommand `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 command>extension>renew"`
+}
+
+// FeeExtensionTransferType represents the transfer tag from the fee-1.0
+// extension.
+type FeeExtensionTransferType struct {
+	Transfer FeeTransformCommand `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 command>extension>transfer"`
+}
+
+// FeeExtensionUpdateType represents the update tag from the fee-1.0
+// extension.
+type FeeExtensionUpdateType struct {
+	Update FeeTransformCommand `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 command>extension>update"`
+}
+
+// FeeExtensionCheckDataType represents the chkData tag from the fee-1.0
+// extension.
+type FeeExtensionCheckDataType struct {
+	CheckData FeeCheckData `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 chkData"`
+}
+
+// FeeExtensionCreateDataType represents the creData tag from the fee-1.0
+// extension.
+type FeeExtensionCreateDataType struct {
+	CreateData FeeTransformResult `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 creData"`
+}
+
+// FeeExtensionRenewDataType represents the renData tag from the fee-1.0
+// extension.
+type FeeExtensionRenewDataType struct {
+	RenewData FeeTransformResult `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 renData"`
+}
+
+// FeeExtensionTransferDataType represents the trnData tag from the fee-1.0
+// extension.
+type FeeExtensionTransferDataType struct {
+	TransferData FeeTransformResult `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 trnData"`
+}
+
+// FeeExtensionUpdateDataType represents the upData tag from the fee-1.0
+// extension.
+type FeeExtensionUpdateDataType struct {
+	UpdateData FeeTransformResult `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 upData"`
+}
+
+// FeeExtensionDeleteDataType represents the delData tag from the fee-1.0
+// extension.
+type FeeExtensionDeleteDataType struct {
+	DeleteData FeeTransformResult `xml:"urn:ietf:params:xml:ns:epp:fee-1.0 delData"`
+}
+
+// FeeCheck represents the extension data for check.
+type FeeCheck struct {
+	Currency string       `xml:"currency,omitempty"`
+	Command  []FeeCommand `xml:"command"`
+}
+
+// FeeCommand represents a command to query the fee for. The custom name is
+// used when the command is custom.
+type FeeCommand struct {
+	Name       FeeCommandName `xml:"name,attr"`
+	CustomName string         `xml:"customName,attr,omitempty"`
+	Phase      string         `xml:"phase,attr,omitempty"`
+	Subphase   string         `xml:"subphase,attr,omitempty"`
+	Period     *Period        `xml:"period,omitempty"`
+}
+
+// FeeCheckData represents the response data for check.
+type FeeCheckData struct {
+	Currency  string               `xml:"currency"`
+	CheckData []FeeObjectCheckData `xml:"cd"`
+}
+
+// FeeObjectCheckData represents the fees for one object. If the fees can not
+// be determined the object is not available and a reason is given.
+type FeeObjectCheckData struct {
+	Available bool             `xml:"avail,attr"`
+	ObjectID  string           `xml:"objID"`
+	Class     string           `xml:"class,omitempty"`
+	Command   []FeeCommandData `xml:"command,omitempty"`
+	Reason    *FeeReason       `xml:"reason,omitempty"`
+}
+
+// FeeCommandData represents the fees for one command. Standard is set when
+// the fees are the standard fees for the command.
+type FeeCommandData struct {
+	Name       FeeCommandName `xml:"name,attr"`
+	CustomName string         `xml:"customName,attr,omitempty"`
+	Phase      string         `xml:"phase,attr,omitempty"`
+	Subphase   string         `xml:"subphase,attr,omitempty"`
+	Standard   bool           `xml:"standard,attr,omitempty"`
+	Period     *Period        `xml:"period,omitempty"`
+	Fee        []FeeFee       `xml:"fee,omitempty"`
+	Credit     []FeeCredit    `xml:"credit,omitempty"`
+	Reason     *FeeReason     `xml:"reason,omitempty"`
+}
+
+// FeeFee represents a fee. The value is a non negative decimal number.
+type FeeFee struct {
+	Value       string         `xml:",chardata"`
+	Description string         `xml:"description,attr,omitempty"`
+	Language    string         `xml:"lang,attr,omitempty"`
+	Refundable  *bool          `xml:"refundable,attr,omitempty"`
+	GracePeriod string         `xml:"grace-period,attr,omitempty"`
+	Applied     FeeAppliedType `xml:"applied,attr,omitempty"`
+}
+
+// FeeCredit represents a credit. The value is a negative decimal number.
+type FeeCredit struct {
+	Value       string `xml:",chardata"`
+	Description string `xml:"description,attr,omitempty"`
+	Language    string `xml:"lang,attr,omitempty"`
+}
+
+// FeeReason represents the reason why fees could not be determined.
+type FeeReason struct {
+	Value    string `xml:",chardata"`
+	Language string `xml:"lang,attr,omitempty"`
+}
+
+// FeeTransformCommand represents the fees acknowledged by the client for a
+// transform command.
+type FeeTransformCommand struct {
+	Currency string      `xml:"currency,omitempty"`
+	Fee      []FeeFee    `xml:"fee"`
+	Credit   []FeeCredit `xml:"credit,omitempty"`
+}
+
+// FeeTransformResult represents the fees charged for a transform command.
+type FeeTransformResult struct {
+	Currency    string      `xml:"currency,omitempty"`
+	Period      *Period     `xml:"period,omitempty"`
+	Fee         []FeeFee    `xml:"fee,omitempty"`
+	Credit      []FeeCredit `xml:"credit,omitempty"`
+	Balance     string      `xml:"balance,omitempty"`
+	CreditLimit string      `xml:"creditLimit,omitempty"`
+}
@@ -0,0 +1,64 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/fee.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// FeeExtensionCheckTypeIn represents a namespace agnostic version of FeeExtensionCheckType
+type FeeExtensionCheckTypeIn struct {
+	Check FeeCheck `xml:"command>extension>check"`
+}
+
+// FeeExtensionCreateTypeIn represents a namespace agnostic version of FeeExtensionCreateType
+type FeeExtensionCreateTypeIn struct {
+	Create FeeTransformCommand `xml:"command>extension>create"`
+}
+
+// FeeExtensionRenewTypeIn represents a namespace agnostic version of FeeExtensionRenewType
+type FeeExtensionRenewTypeIn struct {
+	Renew FeeTransformCommand `xml:"command>extension>renew"`
+}
+
+// FeeExtensionTransferTypeIn represents a namespace agnostic version of FeeExtensionTransferType
+type FeeExtensionTransferTypeIn struct {
+	Transfer FeeTransformCommand `xml:"command>extension>transfer"`
+}
+
+// FeeExtensionUpdateTypeIn represents a namespace agnostic version of FeeExtensionUpdateType
+type FeeExtensionUpdateTypeIn struct {
+	Update FeeTransformCommand `xml:"command>extension>update"`
+}
+
+// FeeExtensionCheckDataTypeIn represents a namespace agnostic version of FeeExtensionCheckDataType
+type FeeExtensionCheckDataTypeIn struct {
+	CheckData FeeCheckData `xml:"chkData"`
+}
+
+// FeeExtensionCreateDataTypeIn represents a namespace agnostic version of FeeExtensionCreateDataType
+type FeeExtensionCreateDataTypeIn struct {
+	CreateData FeeTransformResult `xml:"creData"`
+}
+
+// FeeExtensionRenewDataTypeIn represents a namespace agnostic version of FeeExtensionRenewDataType
+type FeeExtensionRenewDataTypeIn struct {
+	RenewData FeeTransformResult `xml:"renData"`
+}
+
+// FeeExtensionTransferDataTypeIn represents a namespace agnostic version of FeeExtensionTransferDataType
+type FeeExtensionTransferDataTypeIn struct {
+	TransferData FeeTransformResult `xml:"trnData"`
+}
+
+// FeeExtensionUpdateDataTypeIn represents a namespace agnostic version of FeeExtensionUpdateDataType
+type FeeExtensionUpdateDataTypeIn struct {
+	UpdateData FeeTransformResult `xml:"upData"`
+}
+
+// FeeExtensionDeleteDataTypeIn represents a namespace agnostic version of FeeExtensionDeleteDataType
+type FeeExtensionDeleteDataTypeIn struct {
+	DeleteData FeeTransformResult `xml:"delData"`
+}
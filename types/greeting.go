@@ -2,6 +2,11 @@ package types
 
 import "time"
 
+// Hello represents a hello command sent by the client to get a new greeting.
+type Hello struct {
+	Hello *EmptyTag `xml:"hello"`
+}
+
 // EPPGreeting is the type to represent a greeting from the server.
 type EPPGreeting struct {
 	Greeting Greeting `xml:"greeting"`
@@ -17,15 +22,15 @@ type Greeting struct {
 
 // ServiceMenu represents tags that may occur in the greeting service tag.
 type ServiceMenu struct {
-	Version           []string           `xml:"version"`
-	Language          []string           `xml:"lang"`
-	ObjectURI         []string           `xml:"objURI"`
-	ServiceExtentions []ServiceExtension `xml:"svcExtention"`
+	Version          []string          `xml:"version"`
+	Language         []string          `xml:"lang"`
+	ObjectURI        []string          `xml:"objURI"`
+	ServiceExtension *ServiceExtension `xml:"svcExtension,omitempty"`
 }
 
-// ServiceExtension represent extension to the service.
+// ServiceExtension represent extensions to the service.
 type ServiceExtension struct {
-	ExtensionURI string `xml:"extURI"`
+	ExtensionURI []string `xml:"extURI"`
 }
 
 // DCP (data collection policy) represents the policy declared in the greeting
@@ -48,7 +53,7 @@ type DCPAccess struct {
 
 // DCPExpiry represent DCP expiry.
 type DCPExpiry struct {
-	Absolute *time.Time `xml:"absoulte"`
+	Absolute *time.Time `xml:"absolute,omitempty"`
 	Relative string     `xml:"relative,omitempty"` // Format "PnYnMnDTnHnMnS"
 }
 
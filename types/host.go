@@ -75,8 +75,8 @@ type HostCheck struct {
 
 // HostCreate represents a host create request to the EPP server.
 type HostCreate struct {
-	Name    string      `xml:"name"`
-	Address HostAddress `xml:"addr,omitempty"`
+	Name    string        `xml:"name"`
+	Address []HostAddress `xml:"addr,omitempty"`
 }
 
 // HostDelete represents a host delete request to the EPP server.
@@ -94,7 +94,12 @@ type HostUpdate struct {
 	Name   string         `xml:"name"`
 	Add    *HostAddRemove `xml:"add,omitempty"`
 	Remove *HostAddRemove `xml:"rem,omitempty"`
-	Change string         `xml:"chg>name,omitempty"`
+	Change *HostChange    `xml:"chg,omitempty"`
+}
+
+// HostChange represents the data that may be changed while updating a host.
+type HostChange struct {
+	Name string `xml:"name"`
 }
 
 // HostCheckData represents the response for a host check command.
@@ -118,25 +123,26 @@ type HostInfoData struct {
 	CreateID     string        `xml:"crID"`
 	CreateDate   time.Time     `xml:"crDate"`
 	UpdateID     string        `xml:"upID,omitempty"`
-	UpdateDate   time.Time     `xml:"upDate,omitempty"`
-	TransferDate time.Time     `xml:"trDate,omitempty"`
+	UpdateDate   *time.Time    `xml:"upDate,omitempty"`
+	TransferDate *time.Time    `xml:"trDate,omitempty"`
 }
 
 // HostAddRemove represents data that can be added or removed while updating a
 // domain.
 type HostAddRemove struct {
 	Address []HostAddress `xml:"addr,omitempty"`
+	Status  []HostStatus  `xml:"status,omitempty"`
 }
 
 // HostAddress represents an IP address beloning to a host.
 type HostAddress struct {
-	Address string `xml:",chardata,omitempty"`
-	IP      IPType `xml:"ip,attr"`
+	Address string `xml:",chardata"`
+	IP      IPType `xml:"ip,attr,omitempty"`
 }
 
 // HostStatus represents statuses for a host.
 type HostStatus struct {
 	Status         string         `xml:",chardata"`
 	HostStatusType HostStatusType `xml:"s,attr"`
-	Language       string         `xml:"lang,attr"`
+	Language       string         `xml:"lang,attr,omitempty"`
 }
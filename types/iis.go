@@ -19,7 +19,7 @@ type IISExtensionUpdateType struct {
 
 // IISExtensionTransferType represents the transfer tag from iis-1.2 extension.
 type IISExtensionTransferType struct {
-	Update IISExtensionUpdate `xml:"urn:se:iis:xml:epp:iis-1.2 command>extension>transfer"`
+	Transfer IISExtensionTransfer `xml:"urn:se:iis:xml:epp:iis-1.2 command>extension>transfer"`
 }
 
 // IISExtensionInfoDataType represents the infData tag from iis-1.2 extension.
@@ -20,7 +20,7 @@ type IISExtensionUpdateTypeIn struct {
 
 // IISExtensionTransferTypeIn represents a namespace agnostic version of IISExtensionTransferType
 type IISExtensionTransferTypeIn struct {
-	Update IISExtensionUpdate `xml:"command>extension>transfer"`
+	Transfer IISExtensionTransfer `xml:"command>extension>transfer"`
 }
 
 // IISExtensionInfoDataTypeIn represents a namespace agnostic version of IISExtensionInfoDataType
@@ -0,0 +1,75 @@
+package types
+
+import "time"
+
+// Name space constant for the key relay mapping.
+const (
+	NameSpaceKeyRelay10 = "urn:ietf:params:xml:ns:keyrelay-1.0"
+)
+
+// KeyRelayType represents the keyrelay command from RFC 8063. The command is
+// a protocol extension so it's placed in the extension tag directly under the
+// epp tag together with the client transaction ID.
+type KeyRelayType struct {
+	Command KeyRelayCommand `xml:"urn:ietf:params:xml:ns:keyrelay-1.0 extension>command"`
+}
+
+// KeyRelayInfoDataType represents the info data for a key relay, it's
+// delivered to the sponsoring client in a poll message.
+type KeyRelayInfoDataType struct {
+	InfoData KeyRelayInfoData `xml:"urn:ietf:params:xml:ns:keyrelay-1.0 infData"`
+}
+
+// KeyRelayCommand represents the command tag in the keyrelay-1.0 namespace.
+type KeyRelayCommand struct {
+	KeyRelay            KeyRelay `xml:"keyrelay"`
+	ClientTransactionID string   `xml:"clTRID,omitempty"`
+}
+
+// KeyRelay represents a request to relay key data to the sponsoring client
+// of a domain. The authorization information of the domain is required.
+type KeyRelay struct {
+	Name         string           `xml:"name"`
+	AuthInfo     KeyRelayAuthInfo `xml:"authInfo"`
+	KeyRelayData []KeyRelayData   `xml:"keyRelayData"`
+}
+
+// KeyRelayAuthInfo represents the authorization information of the domain.
+// The password is in the domain-1.0 namespace.
+type KeyRelayAuthInfo struct {
+	Password string `xml:"urn:ietf:params:xml:ns:domain-1.0 pw"`
+}
+
+// KeyRelayData represents the key to relay and for how long the key is
+// valid.
+type KeyRelayData struct {
+	KeyData KeyRelayKeyData `xml:"keyData"`
+	Expiry  *KeyRelayExpiry `xml:"expiry,omitempty"`
+}
+
+// KeyRelayKeyData represents a DNSKEY. The fields are in the secDNS-1.1
+// namespace and can be converted to DNSSECKeyData.
+type KeyRelayKeyData struct {
+	Flags     uint   `xml:"urn:ietf:params:xml:ns:secDNS-1.1 flags"`
+	Protocol  uint   `xml:"urn:ietf:params:xml:ns:secDNS-1.1 protocol"`
+	Algorithm uint   `xml:"urn:ietf:params:xml:ns:secDNS-1.1 alg"`
+	PublicKey string `xml:"urn:ietf:params:xml:ns:secDNS-1.1 pubKey"`
+}
+
+// KeyRelayExpiry represents when the key expires, either as an absolute date
+// or as a duration relative to when the key relay was received. Only one of
+// the fields will be set.
+type KeyRelayExpiry struct {
+	Absolute *time.Time `xml:"absolute,omitempty"`
+	Relative string     `xml:"relative,omitempty"`
+}
+
+// KeyRelayInfoData represents a relayed key with the client that requested
+// the relay and the sponsoring client receiving it.
+type KeyRelayInfoData struct {
+	Name         string         `xml:"name"`
+	KeyRelayData []KeyRelayData `xml:"keyRelayData"`
+	CreateDate   time.Time      `xml:"crDate"`
+	RequestingID string         `xml:"reID"`
+	ActingID     string         `xml:"acID"`
+}
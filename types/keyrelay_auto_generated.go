@@ -0,0 +1,19 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/keyrelay.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// KeyRelayTypeIn represents a namespace agnostic version of KeyRelayType
+type KeyRelayTypeIn struct {
+	Command KeyRelayCommand `xml:"extension>command"`
+}
+
+// KeyRelayInfoDataTypeIn represents a namespace agnostic version of KeyRelayInfoDataType
+type KeyRelayInfoDataTypeIn struct {
+	InfoData KeyRelayInfoData `xml:"infData"`
+}
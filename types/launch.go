@@ -0,0 +1,194 @@
+package types
+
+import "time"
+
+// Name space constant for the extension.
+const (
+	NameSpaceLaunch10 = "urn:ietf:params:xml:ns:launch-1.0"
+)
+
+// Constants representing the launch phases from RFC 8334.
+const (
+	LaunchPhaseSunrise  LaunchPhaseType = "sunrise"
+	LaunchPhaseLandrush LaunchPhaseType = "landrush"
+	LaunchPhaseClaims   LaunchPhaseType = "claims"
+	LaunchPhaseOpen     LaunchPhaseType = "open"
+	LaunchPhaseCustom   LaunchPhaseType = "custom"
+)
+
+// Constants representing the forms of a launch check.
+const (
+	LaunchCheckClaims    LaunchCheckFormType = "claims"
+	LaunchCheckAvail     LaunchCheckFormType = "avail"
+	LaunchCheckTrademark LaunchCheckFormType = "trademark"
+)
+
+// Constants representing the object created by a launch create.
+const (
+	LaunchObjectApplication  LaunchObjectType = "application"
+	LaunchObjectRegistration LaunchObjectType = "registration"
+)
+
+// Constants representing the status of a launch application.
+const (
+	LaunchStatusPendingValidation LaunchStatusType = "pendingValidation"
+	LaunchStatusValidated         LaunchStatusType = "validated"
+	LaunchStatusInvalid           LaunchStatusType = "invalid"
+	LaunchStatusPendingAllocation LaunchStatusType = "pendingAllocation"
+	LaunchStatusAllocated         LaunchStatusType = "allocated"
+	LaunchStatusRejected          LaunchStatusType = "rejected"
+	LaunchStatusCustom            LaunchStatusType = "custom"
+)
+
+// LaunchPhaseType represents a launch phase value.
+type LaunchPhaseType string
+
+// LaunchCheckFormType represents the form of a launch check.
+type LaunchCheckFormType string
+
+// LaunchObjectType represents the object created by a launch create.
+type LaunchObjectType string
+
+// LaunchStatusType represents the status of a launch application.
+type LaunchStatusType string
+
+// LaunchExtensionCheckType represents the check tag from the launch-1.0
+// extension.
+type LaunchExtensionCheckType struct {
+	Check LaunchCheck `xml:"urn:ietf:params:xml:ns:launch-1.0 command>extension>check"`
+}
+
+// LaunchExtensionInfoType represents the info tag from the launch-1.0
+// extension.
+type LaunchExtensionInfoType struct {
+	Info LaunchInfo `xml:"urn:ietf:params:xml:ns:launch-1.0 command>extension>info"`
+}
+
+// LaunchExtensionCreateType represents the create tag from the launch-1.0
+// extension.
+type LaunchExtensionCreateType struct {
+	Create LaunchCreate `xml:"urn:ietf:params:xml:ns:launch-1.0 command>extension>create"`
+}
+
+// LaunchExtensionUpdateType represents the update tag from the launch-1.0
+// extension.
+type LaunchExtensionUpdateType struct {
+	Update LaunchApplication `xml:"urn:ietf:params:xml:ns:launch-1.0 command>extension>update"`
+}
+
+// LaunchExtensionDeleteType represents the delete tag from the launch-1.0
+// extension.
+type LaunchExtensionDeleteType struct {
+	Delete LaunchApplication `xml:"urn:ietf:params:xml:ns:launch-1.0 command>extension>delete"`
+}
+
+// LaunchExtensionCheckDataType represents the chkData tag from the launch-1.0
+// extension.
+type LaunchExtensionCheckDataType struct {
+	CheckData LaunchCheckData `xml:"urn:ietf:params:xml:ns:launch-1.0 chkData"`
+}
+
+// LaunchExtensionCreateDataType represents the creData tag from the
+// launch-1.0 extension.
+type LaunchExtensionCreateDataType struct {
+	CreateData LaunchApplication `xml:"urn:ietf:params:xml:ns:launch-1.0 creData"`
+}
+
+// LaunchExtensionInfoDataType represents the infData tag from the launch-1.0
+// extension.
+type LaunchExtensionInfoDataType struct {
+	InfoData LaunchInfoData `xml:"urn:ietf:params:xml:ns:launch-1.0 infData"`
+}
+
+// LaunchPhase represents a launch phase. The name is used for sub phases or
+// when the phase is custom.
+type LaunchPhase struct {
+	Phase LaunchPhaseType `xml:",chardata"`
+	Name  string          `xml:"name,attr,omitempty"`
+}
+
+// LaunchCheck represents the extension data for check.
+type LaunchCheck struct {
+	Type  LaunchCheckFormType `xml:"type,attr,omitempty"`
+	Phase *LaunchPhase        `xml:"phase,omitempty"`
+}
+
+// LaunchInfo represents the extension data for info.
+type LaunchInfo struct {
+	IncludeMark   bool        `xml:"includeMark,attr,omitempty"`
+	Phase         LaunchPhase `xml:"phase"`
+	ApplicationID string      `xml:"applicationID,omitempty"`
+}
+
+// LaunchApplication represents the phase and identifier of an application
+// used for update, delete and create responses.
+type LaunchApplication struct {
+	Phase         LaunchPhase `xml:"phase"`
+	ApplicationID string      `xml:"applicationID"`
+}
+
+// LaunchCreate represents the extension data for create. Marks are passed
+// either as code marks or as encoded signed marks, and claims notices are
+// passed as notices.
+type LaunchCreate struct {
+	Type              LaunchObjectType    `xml:"type,attr,omitempty"`
+	Phase             LaunchPhase         `xml:"phase"`
+	CodeMark          []LaunchCodeMark    `xml:"codeMark,omitempty"`
+	EncodedSignedMark []EncodedSignedMark `xml:"urn:ietf:params:xml:ns:signedMark-1.0 encodedSignedMark,omitempty"`
+	Notice            []LaunchNotice      `xml:"notice,omitempty"`
+}
+
+// LaunchCodeMark represents a mark code with an optional mark.
+type LaunchCodeMark struct {
+	Code *LaunchValidatorValue `xml:"code,omitempty"`
+	Mark *Mark                 `xml:"urn:ietf:params:xml:ns:mark-1.0 mark,omitempty"`
+}
+
+// LaunchValidatorValue represents a value with an optional validator ID,
+// used for codes, notice IDs and claim keys.
+type LaunchValidatorValue struct {
+	Value       string `xml:",chardata"`
+	ValidatorID string `xml:"validatorID,attr,omitempty"`
+}
+
+// LaunchNotice represents an accepted claims notice.
+type LaunchNotice struct {
+	NoticeID     LaunchValidatorValue `xml:"noticeID"`
+	NotAfter     time.Time            `xml:"notAfter"`
+	AcceptedDate time.Time            `xml:"acceptedDate"`
+}
+
+// LaunchCheckData represents the response data for a claims check.
+type LaunchCheckData struct {
+	Phase     *LaunchPhase          `xml:"phase,omitempty"`
+	CheckData []LaunchCheckDataItem `xml:"cd"`
+}
+
+// LaunchCheckDataItem represents the claims of one domain name.
+type LaunchCheckDataItem struct {
+	Name     LaunchCheckName        `xml:"name"`
+	ClaimKey []LaunchValidatorValue `xml:"claimKey,omitempty"`
+}
+
+// LaunchCheckName represents a domain name and if it has any claims.
+type LaunchCheckName struct {
+	Name   string `xml:",chardata"`
+	Exists bool   `xml:"exists,attr"`
+}
+
+// LaunchInfoData represents the response data for info.
+type LaunchInfoData struct {
+	Phase         LaunchPhase   `xml:"phase"`
+	ApplicationID string        `xml:"applicationID,omitempty"`
+	Status        *LaunchStatus `xml:"status,omitempty"`
+	Mark          []Mark        `xml:"urn:ietf:params:xml:ns:mark-1.0 mark,omitempty"`
+}
+
+// LaunchStatus represents the status of an application. The name is used
+// when the status is custom.
+type LaunchStatus struct {
+	Status   LaunchStatusType `xml:"s,attr"`
+	Language string           `xml:"lang,attr,omitempty"`
+	Name     string           `xml:"name,attr,omitempty"`
+	Message  string           `xml:",chardata"`
+}
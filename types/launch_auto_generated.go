@@ -0,0 +1,49 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/launch.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// LaunchExtensionCheckTypeIn represents a namespace agnostic version of LaunchExtensionCheckType
+type LaunchExtensionCheckTypeIn struct {
+	Check LaunchCheck `xml:"command>extension>check"`
+}
+
+// LaunchExtensionInfoTypeIn represents a namespace agnostic version of LaunchExtensionInfoType
+type LaunchExtensionInfoTypeIn struct {
+	Info LaunchInfo `xml:"command>extension>info"`
+}
+
+// LaunchExtensionCreateTypeIn represents a namespace agnostic version of LaunchExtensionCreateType
+type LaunchExtensionCreateTypeIn struct {
+	Create LaunchCreate `xml:"command>extension>create"`
+}
+
+// LaunchExtensionUpdateTypeIn represents a namespace agnostic version of LaunchExtensionUpdateType
+type LaunchExtensionUpdateTypeIn struct {
+	Update LaunchApplication `xml:"command>extension>update"`
+}
+
+// LaunchExtensionDeleteTypeIn represents a namespace agnostic version of LaunchExtensionDeleteType
+type LaunchExtensionDeleteTypeIn struct {
+	Delete LaunchApplication `xml:"command>extension>delete"`
+}
+
+// LaunchExtensionCheckDataTypeIn represents a namespace agnostic version of LaunchExtensionCheckDataType
+type LaunchExtensionCheckDataTypeIn struct {
+	CheckData LaunchCheckData `xml:"chkData"`
+}
+
+// LaunchExtensionCreateDataTypeIn represents a namespace agnostic version of LaunchExtensionCreateDataType
+type LaunchExtensionCreateDataTypeIn struct {
+	CreateData LaunchApplication `xml:"creData"`
+}
+
+// LaunchExtensionInfoDataTypeIn represents a namespace agnostic version of LaunchExtensionInfoDataType
+type LaunchExtensionInfoDataTypeIn struct {
+	InfoData LaunchInfoData `xml:"infData"`
+}
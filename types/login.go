@@ -9,6 +9,11 @@ type Login struct {
 	Services    LoginServices `xml:"command>login>svcs,omitempty"`
 }
 
+// Logout represents a logout command.
+type Logout struct {
+	Logout *EmptyTag `xml:"command>logout"`
+}
+
 // LoginOptions represents options that belongs to the login command.
 type LoginOptions struct {
 	Version  string `xml:"version"`
@@ -17,8 +22,8 @@ type LoginOptions struct {
 
 // LoginServices represents services used while logging in
 type LoginServices struct {
-	ObjectURI        []string              `xml:"objURI"`
-	ServiceExtension LoginServiceExtension `xml:"svcExtension"`
+	ObjectURI        []string               `xml:"objURI"`
+	ServiceExtension *LoginServiceExtension `xml:"svcExtension,omitempty"`
 }
 
 // LoginServiceExtension represents extension URIs.
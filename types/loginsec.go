@@ -0,0 +1,80 @@
+package types
+
+import "time"
+
+// Name space constant for the extension.
+const (
+	NameSpaceLoginSec10 = "urn:ietf:params:xml:ns:epp:loginSec-1.0"
+)
+
+// LoginSecPassword is the value of pw and newPW in the login command when the
+// actual password is passed in the loginSec-1.0 extension.
+const LoginSecPassword = "[LOGIN-SECURITY]"
+
+// Constants representing the security event types from RFC 8807.
+const (
+	LoginSecEventPassword    LoginSecEventType = "password"
+	LoginSecEventCertificate LoginSecEventType = "certificate"
+	LoginSecEventCipher      LoginSecEventType = "cipher"
+	LoginSecEventTLSProtocol LoginSecEventType = "tlsProtocol"
+	LoginSecEventNewPassword LoginSecEventType = "newPW"
+	LoginSecEventStat        LoginSecEventType = "stat"
+	LoginSecEventCustom      LoginSecEventType = "custom"
+)
+
+// Constants representing the level of a security event.
+const (
+	LoginSecLevelWarning LoginSecLevelType = "warning"
+	LoginSecLevelError   LoginSecLevelType = "error"
+)
+
+// LoginSecEventType represents the type of a security event.
+type LoginSecEventType string
+
+// LoginSecLevelType represents the level of a security event.
+type LoginSecLevelType string
+
+// LoginSecExtensionType represents the loginSec tag from the loginSec-1.0
+// extension.
+type LoginSecExtensionType struct {
+	LoginSec LoginSec `xml:"urn:ietf:params:xml:ns:epp:loginSec-1.0 command>extension>loginSec"`
+}
+
+// LoginSecExtensionDataType represents the loginSecData tag from the
+// loginSec-1.0 extension.
+type LoginSecExtensionDataType struct {
+	LoginSecData LoginSecData `xml:"urn:ietf:params:xml:ns:epp:loginSec-1.0 loginSecData"`
+}
+
+// LoginSec represents the extension data for login. The passwords are only
+// used when the password in the login command is LoginSecPassword.
+type LoginSec struct {
+	UserAgent   *LoginSecUserAgent `xml:"userAgent,omitempty"`
+	Password    string             `xml:"pw,omitempty"`
+	NewPassword string             `xml:"newPW,omitempty"`
+}
+
+// LoginSecUserAgent represents the software used by the client.
+type LoginSecUserAgent struct {
+	Application     string `xml:"app,omitempty"`
+	Technology      string `xml:"tech,omitempty"`
+	OperatingSystem string `xml:"os,omitempty"`
+}
+
+// LoginSecData represents the security events returned from login.
+type LoginSecData struct {
+	Event []LoginSecEvent `xml:"event"`
+}
+
+// LoginSecEvent represents a security event. The name is used when the type
+// is stat or custom.
+type LoginSecEvent struct {
+	Type       LoginSecEventType `xml:"type,attr"`
+	Name       string            `xml:"name,attr,omitempty"`
+	Level      LoginSecLevelType `xml:"level,attr"`
+	ExpireDate *time.Time        `xml:"exDate,attr,omitempty"`
+	Value      string            `xml:"value,attr,omitempty"`
+	Duration   string            `xml:"duration,attr,omitempty"`
+	Language   string            `xml:"lang,attr,omitempty"`
+	Message    string            `xml:",chardata"`
+}
@@ -0,0 +1,19 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/loginsec.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// LoginSecExtensionTypeIn represents a namespace agnostic version of LoginSecExtensionType
+type LoginSecExtensionTypeIn struct {
+	LoginSec LoginSec `xml:"command>extension>loginSec"`
+}
+
+// LoginSecExtensionDataTypeIn represents a namespace agnostic version of LoginSecExtensionDataType
+type LoginSecExtensionDataTypeIn struct {
+	LoginSecData LoginSecData `xml:"loginSecData"`
+}
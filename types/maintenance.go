@@ -0,0 +1,175 @@
+package types
+
+import "time"
+
+// Name space constant for the extension.
+const (
+	NameSpaceMaintenance10 = "urn:ietf:params:xml:ns:epp:maintenance-1.0"
+)
+
+// MaintenancePollType represents the reason a maintenance poll message was
+// sent.
+type MaintenancePollType string
+
+// Constants representing the poll types from RFC 9167.
+const (
+	MaintenancePollCreate   MaintenancePollType = "create"
+	MaintenancePollUpdate   MaintenancePollType = "update"
+	MaintenancePollDelete   MaintenancePollType = "delete"
+	MaintenancePollCourtesy MaintenancePollType = "courtesy"
+	MaintenancePollEnd      MaintenancePollType = "end"
+)
+
+// MaintenanceImpactType represents the impact a maintenance has on a system.
+type MaintenanceImpactType string
+
+// Constants representing the impact of a maintenance.
+const (
+	MaintenanceImpactFull    MaintenanceImpactType = "full"
+	MaintenanceImpactPartial MaintenanceImpactType = "partial"
+	MaintenanceImpactNone    MaintenanceImpactType = "none"
+)
+
+// MaintenanceEnvironmentType represents the environment affected by a
+// maintenance.
+type MaintenanceEnvironmentType string
+
+// Constants representing the environment of a maintenance. The name attribute
+// is used for custom environments.
+const (
+	MaintenanceEnvironmentProduction MaintenanceEnvironmentType = "production"
+	MaintenanceEnvironmentOTE        MaintenanceEnvironmentType = "ote"
+	MaintenanceEnvironmentStaging    MaintenanceEnvironmentType = "staging"
+	MaintenanceEnvironmentDev        MaintenanceEnvironmentType = "dev"
+	MaintenanceEnvironmentCustom     MaintenanceEnvironmentType = "custom"
+)
+
+// MaintenanceReasonType represents the reason for a maintenance.
+type MaintenanceReasonType string
+
+// Constants representing the reason for a maintenance.
+const (
+	MaintenanceReasonPlanned   MaintenanceReasonType = "planned"
+	MaintenanceReasonEmergency MaintenanceReasonType = "emergency"
+)
+
+// MaintenanceDescriptionType represents the format of a maintenance
+// description.
+type MaintenanceDescriptionType string
+
+// Constants representing the format of a description.
+const (
+	MaintenanceDescriptionPlain MaintenanceDescriptionType = "plain"
+	MaintenanceDescriptionHTML  MaintenanceDescriptionType = "html"
+)
+
+// MaintenanceInfoType represents the info command from the maintenance-1.0
+// extension.
+type MaintenanceInfoType struct {
+	Info MaintenanceInfo `xml:"urn:ietf:params:xml:ns:epp:maintenance-1.0 command>info>info"`
+}
+
+// MaintenanceInfoDataType represents the info data returned for the info
+// command and in maintenance poll messages.
+type MaintenanceInfoDataType struct {
+	InfoData MaintenanceInfoData `xml:"urn:ietf:params:xml:ns:epp:maintenance-1.0 infData"`
+}
+
+// MaintenanceInfo represents an info request for either a single maintenance
+// by ID or a list of all maintenances.
+type MaintenanceInfo struct {
+	ID   string    `xml:"id,omitempty"`
+	List *EmptyTag `xml:"list,omitempty"`
+}
+
+// MaintenanceInfoData represents the info data, only one of the fields will be
+// set.
+type MaintenanceInfoData struct {
+	List *MaintenanceList `xml:"list,omitempty"`
+	Item *MaintenanceItem `xml:"item,omitempty"`
+}
+
+// MaintenanceList represents a list of maintenances.
+type MaintenanceList struct {
+	Items []MaintenanceListItem `xml:"listItem"`
+}
+
+// MaintenanceListItem represents a maintenance in a list.
+type MaintenanceListItem struct {
+	ID         MaintenanceID `xml:"id"`
+	Start      time.Time     `xml:"start"`
+	End        time.Time     `xml:"end"`
+	CreateDate time.Time     `xml:"crDate"`
+	UpdateDate *time.Time    `xml:"upDate,omitempty"`
+}
+
+// MaintenanceItem represents the details of a maintenance. The poll type is
+// only set in poll messages.
+type MaintenanceItem struct {
+	ID           MaintenanceID            `xml:"id"`
+	Type         []MaintenanceText        `xml:"type,omitempty"`
+	PollType     MaintenancePollType      `xml:"pollType,omitempty"`
+	Systems      MaintenanceSystems       `xml:"systems"`
+	Environment  MaintenanceEnvironment   `xml:"environment"`
+	Start        time.Time                `xml:"start"`
+	End          time.Time                `xml:"end"`
+	Reason       MaintenanceReasonType    `xml:"reason"`
+	Detail       string                   `xml:"detail,omitempty"`
+	Description  []MaintenanceDescription `xml:"description,omitempty"`
+	TLDs         *MaintenanceTLDs         `xml:"tlds,omitempty"`
+	Intervention *MaintenanceIntervention `xml:"intervention,omitempty"`
+	CreateDate   time.Time                `xml:"crDate"`
+	UpdateDate   *time.Time               `xml:"upDate,omitempty"`
+}
+
+// MaintenanceID represents the server unique ID of a maintenance with an
+// optional human readable message.
+type MaintenanceID struct {
+	ID       string `xml:",chardata"`
+	Message  string `xml:"msg,attr,omitempty"`
+	Language string `xml:"lang,attr,omitempty"`
+}
+
+// MaintenanceText represents a text with an optional language.
+type MaintenanceText struct {
+	Value    string `xml:",chardata"`
+	Language string `xml:"lang,attr,omitempty"`
+}
+
+// MaintenanceSystems represents the systems affected by a maintenance.
+type MaintenanceSystems struct {
+	System []MaintenanceSystem `xml:"system"`
+}
+
+// MaintenanceSystem represents a system affected by a maintenance.
+type MaintenanceSystem struct {
+	Name   string                `xml:"name"`
+	Host   string                `xml:"host,omitempty"`
+	Impact MaintenanceImpactType `xml:"impact"`
+}
+
+// MaintenanceEnvironment represents the environment affected by a
+// maintenance.
+type MaintenanceEnvironment struct {
+	Type MaintenanceEnvironmentType `xml:"type,attr"`
+	Name string                     `xml:"name,attr,omitempty"`
+}
+
+// MaintenanceDescription represents a free text description of a maintenance.
+type MaintenanceDescription struct {
+	Value    string                     `xml:",chardata"`
+	Type     MaintenanceDescriptionType `xml:"type,attr,omitempty"`
+	Language string                     `xml:"lang,attr,omitempty"`
+}
+
+// MaintenanceTLDs represents the top level domains affected by a maintenance.
+type MaintenanceTLDs struct {
+	TLD []string `xml:"tld"`
+}
+
+// MaintenanceIntervention represents if the clients must act on a
+// maintenance, either by reconnecting or by changing their implementation.
+type MaintenanceIntervention struct {
+	Connection     bool `xml:"connection"`
+	Implementation bool `xml:"implementation"`
+}
@@ -0,0 +1,19 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/maintenance.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// MaintenanceInfoTypeIn represents a namespace agnostic version of MaintenanceInfoType
+type MaintenanceInfoTypeIn struct {
+	Info MaintenanceInfo `xml:"command>info>info"`
+}
+
+// MaintenanceInfoDataTypeIn represents a namespace agnostic version of MaintenanceInfoDataType
+type MaintenanceInfoDataTypeIn struct {
+	InfoData MaintenanceInfoData `xml:"infData"`
+}
@@ -0,0 +1,79 @@
+package types
+
+import "time"
+
+// Name space constants for marks and signed marks.
+const (
+	NameSpaceMark10       = "urn:ietf:params:xml:ns:mark-1.0"
+	NameSpaceSignedMark10 = "urn:ietf:params:xml:ns:signedMark-1.0"
+)
+
+// Constants representing the entitlement of a mark holder.
+const (
+	MarkEntitlementOwner    MarkEntitlementType = "owner"
+	MarkEntitlementAssignee MarkEntitlementType = "assignee"
+	MarkEntitlementLicensee MarkEntitlementType = "licensee"
+)
+
+// MarkEntitlementType represents the entitlement of a mark holder.
+type MarkEntitlementType string
+
+// Mark represents a mark from mark-1.0. Only trademarks are supported, marks
+// protected by treaty, statute or court are not.
+type Mark struct {
+	Trademark []MarkTrademark `xml:"urn:ietf:params:xml:ns:mark-1.0 trademark"`
+}
+
+// MarkTrademark represents a registered trademark.
+type MarkTrademark struct {
+	ID                 string        `xml:"id"`
+	MarkName           string        `xml:"markName"`
+	Holder             []MarkHolder  `xml:"holder"`
+	Contact            []MarkContact `xml:"contact,omitempty"`
+	Jurisdiction       string        `xml:"jurisdiction"`
+	Class              []int         `xml:"class,omitempty"`
+	Label              []string      `xml:"label,omitempty"`
+	GoodsAndServices   string        `xml:"goodsAndServices"`
+	ApplicationID      string        `xml:"apId,omitempty"`
+	ApplicationDate    *time.Time    `xml:"apDate,omitempty"`
+	RegistrationNumber string        `xml:"regNum"`
+	RegistrationDate   time.Time     `xml:"regDate"`
+	ExpireDate         *time.Time    `xml:"exDate,omitempty"`
+}
+
+// MarkHolder represents the holder of a mark.
+type MarkHolder struct {
+	Entitlement  MarkEntitlementType `xml:"entitlement,attr,omitempty"`
+	Name         string              `xml:"name,omitempty"`
+	Organization string              `xml:"org,omitempty"`
+	Address      MarkAddress         `xml:"addr"`
+	Voice        *E164Type           `xml:"voice,omitempty"`
+	Fax          *E164Type           `xml:"fax,omitempty"`
+	Email        string              `xml:"email,omitempty"`
+}
+
+// MarkContact represents a contact for a mark.
+type MarkContact struct {
+	Type         string      `xml:"type,attr,omitempty"`
+	Name         string      `xml:"name"`
+	Organization string      `xml:"org,omitempty"`
+	Address      MarkAddress `xml:"addr"`
+	Voice        E164Type    `xml:"voice"`
+	Fax          *E164Type   `xml:"fax,omitempty"`
+	Email        string      `xml:"email"`
+}
+
+// MarkAddress represents the address of a mark holder or contact.
+type MarkAddress struct {
+	Street        []string `xml:"street"`
+	City          string   `xml:"city"`
+	StateProvince string   `xml:"sp,omitempty"`
+	PostalCode    string   `xml:"pc,omitempty"`
+	CountryCode   string   `xml:"cc"`
+}
+
+// EncodedSignedMark represents a base64 encoded signed mark data (SMD) file.
+type EncodedSignedMark struct {
+	Encoding string `xml:"encoding,attr,omitempty"`
+	Value    string `xml:",chardata"`
+}
@@ -0,0 +1,231 @@
+package types
+
+import "time"
+
+// Name space constant for the organization mapping.
+const (
+	NameSpaceOrg10 = "urn:ietf:params:xml:ns:epp:org-1.0"
+)
+
+// OrgStatusType represents organization status types.
+type OrgStatusType string
+
+// Constants representing organization status types.
+const (
+	OrgStatusOk                     OrgStatusType = "ok"
+	OrgStatusHold                   OrgStatusType = "hold"
+	OrgStatusTerminated             OrgStatusType = "terminated"
+	OrgStatusClientDeleteProhibited OrgStatusType = "clientDeleteProhibited"
+	OrgStatusClientUpdateProhibited OrgStatusType = "clientUpdateProhibited"
+	OrgStatusClientLinkProhibited   OrgStatusType = "clientLinkProhibited"
+	OrgStatusLinked                 OrgStatusType = "linked"
+	OrgStatusPendingCreate          OrgStatusType = "pendingCreate"
+	OrgStatusPendingUpdate          OrgStatusType = "pendingUpdate"
+	OrgStatusPendingDelete          OrgStatusType = "pendingDelete"
+	OrgStatusServerDeleteProhibited OrgStatusType = "serverDeleteProhibited"
+	OrgStatusServerUpdateProhibited OrgStatusType = "serverUpdateProhibited"
+	OrgStatusServerLinkProhibited   OrgStatusType = "serverLinkProhibited"
+)
+
+// OrgRoleStatusType represents the status of an organization role.
+type OrgRoleStatusType string
+
+// Constants representing organization role status types.
+const (
+	OrgRoleStatusOk                   OrgRoleStatusType = "ok"
+	OrgRoleStatusClientLinkProhibited OrgRoleStatusType = "clientLinkProhibited"
+	OrgRoleStatusServerLinkProhibited OrgRoleStatusType = "serverLinkProhibited"
+)
+
+// Constants representing the roles registered in the IANA EPP organization
+// role values registry.
+const (
+	OrgRoleRegistrar    = "registrar"
+	OrgRoleReseller     = "reseller"
+	OrgRolePrivacyProxy = "privacyproxy"
+	OrgRoleDNSOperator  = "dns-operator"
+)
+
+// OrgContactType represents the type of an organization contact.
+type OrgContactType string
+
+// Constants representing organization contact types.
+const (
+	OrgContactAdmin   OrgContactType = "admin"
+	OrgContactBilling OrgContactType = "billing"
+	OrgContactTech    OrgContactType = "tech"
+	OrgContactAbuse   OrgContactType = "abuse"
+	OrgContactCustom  OrgContactType = "custom"
+)
+
+// OrgCheckType represents an organization check command.
+type OrgCheckType struct {
+	Check OrgCheck `xml:"urn:ietf:params:xml:ns:epp:org-1.0 command>check>check"`
+}
+
+// OrgCreateType represents an organization create command.
+type OrgCreateType struct {
+	Create OrgCreate `xml:"urn:ietf:params:xml:ns:epp:org-1.0 command>create>create"`
+}
+
+// OrgDeleteType represents an organization delete command.
+type OrgDeleteType struct {
+	Delete OrgDelete `xml:"urn:ietf:params:xml:ns:epp:org-1.0 command>delete>delete"`
+}
+
+// OrgInfoType represents an organization info command.
+type OrgInfoType struct {
+	Info OrgInfo `xml:"urn:ietf:params:xml:ns:epp:org-1.0 command>info>info"`
+}
+
+// OrgUpdateType represents an organization update command.
+type OrgUpdateType struct {
+	Update OrgUpdate `xml:"urn:ietf:params:xml:ns:epp:org-1.0 command>update>update"`
+}
+
+// OrgCheckDataType represents organization check data.
+type OrgCheckDataType struct {
+	CheckData OrgCheckData `xml:"urn:ietf:params:xml:ns:epp:org-1.0 chkData"`
+}
+
+// OrgCreateDataType represents organization create data.
+type OrgCreateDataType struct {
+	CreateData OrgCreateData `xml:"urn:ietf:params:xml:ns:epp:org-1.0 creData"`
+}
+
+// OrgInfoDataType represents organization info data.
+type OrgInfoDataType struct {
+	InfoData OrgInfoData `xml:"urn:ietf:params:xml:ns:epp:org-1.0 infData"`
+}
+
+// OrgCheck represents a check for organization(s).
+type OrgCheck struct {
+	IDs []string `xml:"id"`
+}
+
+// OrgCreate represents an organization create command.
+type OrgCreate struct {
+	ID         string          `xml:"id"`
+	Role       []OrgRole       `xml:"role"`
+	Status     []OrgStatus     `xml:"status,omitempty"`
+	ParentID   string          `xml:"parentId,omitempty"`
+	PostalInfo []OrgPostalInfo `xml:"postalInfo,omitempty"`
+	Voice      *E164Type       `xml:"voice,omitempty"`
+	Fax        *E164Type       `xml:"fax,omitempty"`
+	Email      string          `xml:"email,omitempty"`
+	URL        string          `xml:"url,omitempty"`
+	Contact    []OrgContact    `xml:"contact,omitempty"`
+}
+
+// OrgDelete represents an organization delete command.
+type OrgDelete struct {
+	ID string `xml:"id"`
+}
+
+// OrgInfo represents an organization info command.
+type OrgInfo struct {
+	ID string `xml:"id"`
+}
+
+// OrgUpdate represents an organization update command.
+type OrgUpdate struct {
+	ID     string        `xml:"id"`
+	Add    *OrgAddRemove `xml:"add,omitempty"`
+	Remove *OrgAddRemove `xml:"rem,omitempty"`
+	Change *OrgChange    `xml:"chg,omitempty"`
+}
+
+// OrgAddRemove represents the fields that holds data to add or remove for an
+// organization.
+type OrgAddRemove struct {
+	Contact []OrgContact `xml:"contact,omitempty"`
+	Role    []OrgRole    `xml:"role,omitempty"`
+	Status  []OrgStatus  `xml:"status,omitempty"`
+}
+
+// OrgChange represents the data that may be changed while updating an
+// organization. An empty parent ID removes the parent organization.
+type OrgChange struct {
+	ParentID   *string         `xml:"parentId,omitempty"`
+	PostalInfo []OrgPostalInfo `xml:"postalInfo,omitempty"`
+	Voice      *E164Type       `xml:"voice,omitempty"`
+	Fax        *E164Type       `xml:"fax,omitempty"`
+	Email      string          `xml:"email,omitempty"`
+	URL        string          `xml:"url,omitempty"`
+}
+
+// OrgCheckData represents the data returned from an organization check
+// command.
+type OrgCheckData struct {
+	CheckData []CheckOrg `xml:"cd"`
+}
+
+// CheckOrg represents the data from an organization check command ID.
+type CheckOrg struct {
+	ID     CheckName `xml:"id"`
+	Reason string    `xml:"reason,omitempty"`
+}
+
+// OrgCreateData represents the data returned from an organization create
+// command.
+type OrgCreateData struct {
+	ID         string    `xml:"id"`
+	CreateDate time.Time `xml:"crDate"`
+}
+
+// OrgInfoData represents the data returned from an organization info command.
+type OrgInfoData struct {
+	ID         string          `xml:"id"`
+	ROID       string          `xml:"roid"`
+	Role       []OrgRole       `xml:"role"`
+	Status     []OrgStatus     `xml:"status"`
+	ParentID   string          `xml:"parentId,omitempty"`
+	PostalInfo []OrgPostalInfo `xml:"postalInfo,omitempty"`
+	Voice      *E164Type       `xml:"voice,omitempty"`
+	Fax        *E164Type       `xml:"fax,omitempty"`
+	Email      string          `xml:"email,omitempty"`
+	URL        string          `xml:"url,omitempty"`
+	Contact    []OrgContact    `xml:"contact,omitempty"`
+	ClientID   string          `xml:"clID,omitempty"`
+	CreateID   string          `xml:"crID"`
+	CreateDate time.Time       `xml:"crDate"`
+	UpdateID   string          `xml:"upID,omitempty"`
+	UpdateDate *time.Time      `xml:"upDate,omitempty"`
+}
+
+// OrgRole represents a role of an organization. The role ID is a third party
+// assigned identifier such as the IANA ID of a registrar.
+type OrgRole struct {
+	Type   string          `xml:"type"`
+	Status []OrgRoleStatus `xml:"status,omitempty"`
+	RoleID string          `xml:"roleID,omitempty"`
+}
+
+// OrgStatus represents statuses for an organization.
+type OrgStatus struct {
+	Status        string        `xml:",chardata"`
+	OrgStatusType OrgStatusType `xml:"s,attr"`
+	Language      string        `xml:"lang,attr,omitempty"`
+}
+
+// OrgRoleStatus represents statuses for an organization role.
+type OrgRoleStatus struct {
+	Status            string            `xml:",chardata"`
+	OrgRoleStatusType OrgRoleStatusType `xml:"s,attr"`
+	Language          string            `xml:"lang,attr,omitempty"`
+}
+
+// OrgPostalInfo represents postal information for an organization.
+type OrgPostalInfo struct {
+	Name    string         `xml:"name,omitempty"`
+	Address Address        `xml:"addr"`
+	Type    PostalInfoType `xml:"type,attr"`
+}
+
+// OrgContact represents a contact of an organization. The type name is used for
+// custom contact types.
+type OrgContact struct {
+	Name     string         `xml:",chardata"`
+	Type     OrgContactType `xml:"type,attr"`
+	TypeName string         `xml:"typeName,attr,omitempty"`
+}
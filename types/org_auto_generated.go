@@ -0,0 +1,49 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/org.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// OrgCheckTypeIn represents a namespace agnostic version of OrgCheckType
+type OrgCheckTypeIn struct {
+	Check OrgCheck `xml:"command>check>check"`
+}
+
+// OrgCreateTypeIn represents a namespace agnostic version of OrgCreateType
+type OrgCreateTypeIn struct {
+	Create OrgCreate `xml:"command>create>create"`
+}
+
+// OrgDeleteTypeIn represents a namespace agnostic version of OrgDeleteType
+type OrgDeleteTypeIn struct {
+	Delete OrgDelete `xml:"command>delete>delete"`
+}
+
+// OrgInfoTypeIn represents a namespace agnostic version of OrgInfoType
+type OrgInfoTypeIn struct {
+	Info OrgInfo `xml:"command>info>info"`
+}
+
+// OrgUpdateTypeIn represents a namespace agnostic version of OrgUpdateType
+type OrgUpdateTypeIn struct {
+	Update OrgUpdate `xml:"command>update>update"`
+}
+
+// OrgCheckDataTypeIn represents a namespace agnostic version of OrgCheckDataType
+type OrgCheckDataTypeIn struct {
+	CheckData OrgCheckData `xml:"chkData"`
+}
+
+// OrgCreateDataTypeIn represents a namespace agnostic version of OrgCreateDataType
+type OrgCreateDataTypeIn struct {
+	CreateData OrgCreateData `xml:"creData"`
+}
+
+// OrgInfoDataTypeIn represents a namespace agnostic version of OrgInfoDataType
+type OrgInfoDataTypeIn struct {
+	InfoData OrgInfoData `xml:"infData"`
+}
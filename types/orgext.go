@@ -0,0 +1,44 @@
+package types
+
+// Name space constant for the extension.
+const (
+	NameSpaceOrgExt10 = "urn:ietf:params:xml:ns:epp:orgext-1.0"
+)
+
+// OrgExtensionCreateType represents the create tag from the orgext-1.0
+// extension.
+type OrgExtensionCreateType struct {
+	Create OrgExtensionIDs `xml:"urn:ietf:params:xml:ns:epp:orgext-1.0 command>extension>create"`
+}
+
+// OrgExtensionUpdateType represents the update tag from the orgext-1.0
+// extension.
+type OrgExtensionUpdateType struct {
+	Update OrgExtensionUpdate `xml:"urn:ietf:params:xml:ns:epp:orgext-1.0 command>extension>update"`
+}
+
+// OrgExtensionInfoDataType represents the infData tag from the orgext-1.0
+// extension.
+type OrgExtensionInfoDataType struct {
+	InfoData OrgExtensionIDs `xml:"urn:ietf:params:xml:ns:epp:orgext-1.0 infData"`
+}
+
+// OrgExtensionIDs represents a list of organizations linked to an object.
+type OrgExtensionIDs struct {
+	ID []OrgExtensionID `xml:"id"`
+}
+
+// OrgExtensionID represents an organization linked to an object with a role.
+// An empty ID in a change removes the organization with the role.
+type OrgExtensionID struct {
+	ID   string `xml:",chardata"`
+	Role string `xml:"role,attr"`
+}
+
+// OrgExtensionUpdate represents the organizations to add, remove or change for
+// an object.
+type OrgExtensionUpdate struct {
+	Add    *OrgExtensionIDs `xml:"add,omitempty"`
+	Remove *OrgExtensionIDs `xml:"rem,omitempty"`
+	Change *OrgExtensionIDs `xml:"chg,omitempty"`
+}
@@ -0,0 +1,24 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/orgext.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// OrgExtensionCreateTypeIn represents a namespace agnostic version of OrgExtensionCreateType
+type OrgExtensionCreateTypeIn struct {
+	Create OrgExtensionIDs `xml:"command>extension>create"`
+}
+
+// OrgExtensionUpdateTypeIn represents a namespace agnostic version of OrgExtensionUpdateType
+type OrgExtensionUpdateTypeIn struct {
+	Update OrgExtensionUpdate `xml:"command>extension>update"`
+}
+
+// OrgExtensionInfoDataTypeIn represents a namespace agnostic version of OrgExtensionInfoDataType
+type OrgExtensionInfoDataTypeIn struct {
+	InfoData OrgExtensionIDs `xml:"infData"`
+}
@@ -14,6 +14,22 @@ type Poll struct {
 	Poll PollCommand `xml:"command>poll"`
 }
 
+// PollResultData represents the result data from a poll request. The data is
+// object specific and only one of the fields will be set. Info data is used for
+// changes made by the server, see the changePoll-1.0 extension. To support
+// extensions or other objects, use a custom type as result data instead.
+type PollResultData struct {
+	ContactInfoData                          *ContactInfoData                          `xml:"urn:ietf:params:xml:ns:contact-1.0 infData,omitempty"`
+	ContactPendingActivationNotificationData *ContactPendingActivationNotificationData `xml:"urn:ietf:params:xml:ns:contact-1.0 panData,omitempty"`
+	ContactTransferData                      *ContactTransferData                      `xml:"urn:ietf:params:xml:ns:contact-1.0 trnData,omitempty"`
+	DomainInfoData                           *DomainInfoData                           `xml:"urn:ietf:params:xml:ns:domain-1.0 infData,omitempty"`
+	DomainPendingActivationNotificationData  *DomainPendingActivationNotificationData  `xml:"urn:ietf:params:xml:ns:domain-1.0 panData,omitempty"`
+	DomainTransferData                       *DomainTransferData                       `xml:"urn:ietf:params:xml:ns:domain-1.0 trnData,omitempty"`
+	HostInfoData                             *HostInfoData                             `xml:"urn:ietf:params:xml:ns:host-1.0 infData,omitempty"`
+	KeyRelayInfoData                         *KeyRelayInfoData                         `xml:"urn:ietf:params:xml:ns:keyrelay-1.0 infData,omitempty"`
+	MaintenanceInfoData                      *MaintenanceInfoData                      `xml:"urn:ietf:params:xml:ns:epp:maintenance-1.0 infData,omitempty"`
+}
+
 // PollCommand represents the (attribute) data from a poll command tag.
 type PollCommand struct {
 	Operation PollOperation `xml:"op,attr"`
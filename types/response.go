@@ -19,22 +19,36 @@ type TransactionID struct {
 
 // MessageQueue represents a message queue for client retrieval.
 type MessageQueue struct {
-	QueueDate *time.Time `xml:"qDate,omitempty"`
-	Message   string     `xml:"msg,omitempty"`
-	Count     int        `xml:"count,attr"`
-	ID        string     `xml:"id,attr"`
+	QueueDate *time.Time           `xml:"qDate,omitempty"`
+	Message   *MessageQueueMessage `xml:"msg,omitempty"`
+	Count     int                  `xml:"count,attr"`
+	ID        string               `xml:"id,attr"`
+}
+
+// MessageQueueMessage represents the human readable message for a message in
+// the queue.
+type MessageQueueMessage struct {
+	Value    string `xml:",chardata"`
+	Language string `xml:"lang,attr,omitempty"`
 }
 
 // Result represents the result in a EPP response.
 type Result struct {
-	Code          int                 `xml:"code,attr"`
-	Message       string              `xml:"msg"`
-	Value         interface{}         `xml:"value"`
-	ExternalValue *ExternalErrorValue `xml:"extValue,omitempty"`
+	Code          int                  `xml:"code,attr"`
+	Message       string               `xml:"msg"`
+	Value         interface{}          `xml:"value"`
+	ExternalValue []ExternalErrorValue `xml:"extValue,omitempty"`
 }
 
-// ExternalErrorValue represents the response in the extValeu tag.
+// ExternalErrorValue represents the response in the extValue tag. Besides
+// errors it's used to return unhandled namespaces (RFC 9038).
 type ExternalErrorValue struct {
 	Value  interface{} `xml:"value"`
 	Reason string      `xml:"reason"`
 }
+
+// UndefinedValue represents the <undef/> element used as value in an extValue
+// tag when there is no client provided element to return.
+type UndefinedValue struct {
+	Undefined *EmptyTag `xml:"undef"`
+}
@@ -0,0 +1,89 @@
+package types
+
+import "time"
+
+// Name space constant for the extension.
+const (
+	NameSpaceRGP10 = "urn:ietf:params:xml:ns:rgp-1.0"
+)
+
+// Constants representing the RGP status values from RFC 3915.
+const (
+	RGPStatusAddPeriod        RGPStatusType = "addPeriod"
+	RGPStatusAutoRenewPeriod  RGPStatusType = "autoRenewPeriod"
+	RGPStatusRenewPeriod      RGPStatusType = "renewPeriod"
+	RGPStatusTransferPeriod   RGPStatusType = "transferPeriod"
+	RGPStatusRedemptionPeriod RGPStatusType = "redemptionPeriod"
+	RGPStatusPendingRestore   RGPStatusType = "pendingRestore"
+	RGPStatusPendingDelete    RGPStatusType = "pendingDelete"
+)
+
+// Constants representing the restore operations.
+const (
+	RGPRestoreRequest RGPRestoreOperation = "request"
+	RGPRestoreReport  RGPRestoreOperation = "report"
+)
+
+// RGPStatusType represents an RGP status value.
+type RGPStatusType string
+
+// RGPRestoreOperation represents the operation of a restore command.
+type RGPRestoreOperation string
+
+// RGPExtensionUpdateType represents the update tag from the rgp-1.0 extension.
+type RGPExtensionUpdateType struct {
+	Update RGPUpdate `xml:"urn:ietf:params:xml:ns:rgp-1.0 command>extension>update"`
+}
+
+// RGPExtensionInfoDataType represents the infData tag from the rgp-1.0
+// extension.
+type RGPExtensionInfoDataType struct {
+	InfoData RGPResponseData `xml:"urn:ietf:params:xml:ns:rgp-1.0 infData"`
+}
+
+// RGPExtensionUpdateDataType represents the upData tag from the rgp-1.0
+// extension.
+type RGPExtensionUpdateDataType struct {
+	UpdateData RGPResponseData `xml:"urn:ietf:params:xml:ns:rgp-1.0 upData"`
+}
+
+// RGPUpdate represents the extension data for update.
+type RGPUpdate struct {
+	Restore RGPRestore `xml:"restore"`
+}
+
+// RGPRestore represents a restore request or report.
+type RGPRestore struct {
+	Operation RGPRestoreOperation `xml:"op,attr"`
+	Report    *RGPReport          `xml:"report,omitempty"`
+}
+
+// RGPReport represents the restore report sent to complete a restore.
+type RGPReport struct {
+	PreData       string          `xml:"preData"`
+	PostData      string          `xml:"postData"`
+	DeleteTime    time.Time       `xml:"delTime"`
+	RestoreTime   time.Time       `xml:"resTime"`
+	RestoreReason RGPReportText   `xml:"resReason"`
+	Statement     []RGPReportText `xml:"statement"`
+	Other         string          `xml:"other,omitempty"`
+}
+
+// RGPReportText represents a text in the restore report with an optional
+// language.
+type RGPReportText struct {
+	Value    string `xml:",chardata"`
+	Language string `xml:"lang,attr,omitempty"`
+}
+
+// RGPResponseData represents the extension data for infData and upData.
+type RGPResponseData struct {
+	Status []RGPStatus `xml:"rgpStatus"`
+}
+
+// RGPStatus represents an RGP status with an optional message.
+type RGPStatus struct {
+	Status   RGPStatusType `xml:"s,attr"`
+	Language string        `xml:"lang,attr,omitempty"`
+	Message  string        `xml:",chardata"`
+}
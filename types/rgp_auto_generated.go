@@ -0,0 +1,24 @@
+package types
+
+/*
+NOTE! This file is auto generated from another file - DO NOT EDIT!
+
+This file contents has it's source in types/rgp.go. All structs with only one
+field and the suffix 'Type' is being added here. The difference is that the
+field XML tag won't have a namespace.
+*/
+
+// RGPExtensionUpdateTypeIn represents a namespace agnostic version of RGPExtensionUpdateType
+type RGPExtensionUpdateTypeIn struct {
+	Update RGPUpdate `xml:"command>extension>update"`
+}
+
+// RGPExtensionInfoDataTypeIn represents a namespace agnostic version of RGPExtensionInfoDataType
+type RGPExtensionInfoDataTypeIn struct {
+	InfoData RGPResponseData `xml:"infData"`
+}
+
+// RGPExtensionUpdateDataTypeIn represents a namespace agnostic version of RGPExtensionUpdateDataType
+type RGPExtensionUpdateDataTypeIn struct {
+	UpdateData RGPResponseData `xml:"upData"`
+}
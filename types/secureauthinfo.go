@@ -0,0 +1,8 @@
+package types
+
+// Name space constant for the secure authorization information for transfer
+// extension (RFC 9154). The extension has no XML elements, it's only used to
+// signal the support in the greeting and login.
+const (
+	NameSpaceSecureAuthInfoTransfer10 = "urn:ietf:params:xml:ns:epp:secure-authinfo-transfer-1.0"
+)
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"strings"
+	"time"
+)
+
 /*
 This package defines all the types used from the RFCs used to implement EPP.
 Types are based of the XSDs based on the RFC but takes no
@@ -38,6 +43,45 @@ func Empty() *EmptyTag {
 	return &EmptyTag{}
 }
 
+// dateLayout is the layout used for xsd:date values.
+const dateLayout = "2006-01-02"
+
+// Date represents a xsd:date value, that is a date without any time of day.
+// This is used where the XSD uses the date type instead of dateTime, e.g. for
+// the current expire date in a domain renew command.
+type Date struct {
+	time.Time
+}
+
+// MarshalText implements the encoding.TextMarshaler interface.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.Format(dateLayout)), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (d *Date) UnmarshalText(data []byte) error {
+	t, err := time.Parse(dateLayout, strings.TrimSpace(string(data)))
+	if err != nil {
+		return err
+	}
+
+	d.Time = t
+
+	return nil
+}
+
+// TransferOperation represents the operation to perform in a transfer command.
+type TransferOperation string
+
+// Constants representing available transfer operations.
+const (
+	TransferOperationApprove TransferOperation = "approve"
+	TransferOperationCancel  TransferOperation = "cancel"
+	TransferOperationQuery   TransferOperation = "query"
+	TransferOperationReject  TransferOperation = "reject"
+	TransferOperationRequest TransferOperation = "request"
+)
+
 // EmptyTag represents a tag that can not have any value. This is used for
 // instances to know where a tag was set or not by assigning the parent tag to a
 // pointer to this type.
@@ -55,9 +99,31 @@ type CheckName struct {
 	Available bool   `xml:"avail,attr"`
 }
 
+// ClientTransactionIDType represents the client transaction ID for a command.
+// It's used when a command is combined with extensions so the transaction ID is
+// placed after the extension.
+type ClientTransactionIDType struct {
+	ClientTransactionID string `xml:"command>clTRID,omitempty"`
+}
+
+// ExtensionClientTransactionIDType represents the client transaction ID for a
+// command defined as a protocol extension, like keyrelay. The command and the
+// transaction ID are placed in the extension tag instead of the command tag.
+type ExtensionClientTransactionIDType struct {
+	ClientTransactionID string `xml:"extension>command>clTRID,omitempty"`
+}
+
+// PendingActivationTransactionID represents the transaction IDs for the
+// command that was pending in pending activation notification data sets. The
+// elements are defined in the EPP name space even though the parent isn't.
+type PendingActivationTransactionID struct {
+	ClientTransactionID string `xml:"urn:ietf:params:xml:ns:epp-1.0 clTRID,omitempty"`
+	ServerTransactionID string `xml:"urn:ietf:params:xml:ns:epp-1.0 svTRID"`
+}
+
 // PendingActivationNotificationName represents the name in pending activation
 // notification data sets.
 type PendingActivationNotificationName struct {
 	Name                    string `xml:",chardata"`
-	PendingActivationResult bool   `xml:"paResult,attr,omitempty"`
+	PendingActivationResult bool   `xml:"paResult,attr"`
 }
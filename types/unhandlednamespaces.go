@@ -0,0 +1,9 @@
+package types
+
+// Name space constant for unhandled namespaces (RFC 9038). Like the secure
+// authorization information extension it has no XML elements, when negotiated
+// at login the server returns extensions and objects the client didn't
+// negotiate in extValue elements of the result.
+const (
+	NameSpaceUnhandledNamespaces10 = "urn:ietf:params:xml:ns:epp:unhandled-namespaces-1.0"
+)
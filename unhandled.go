@@ -0,0 +1,117 @@
+package epp
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"aqwari.net/xml/xmltree"
+	"github.com/bombsimon/epp-go/types"
+)
+
+// 클라이언트가 처리할 수 있는 네임스페이스를 반환합니다. 클라이언트가 로그인에서
+// unhandled-namespaces-1.0 을 협상하지 않았으면 nil 을 반환합니다.
+func handledNamespaces(services *types.LoginServices) map[string]struct{} {
+	if services == nil || services.ServiceExtension == nil {
+		return nil
+	}
+
+	handled := map[string]struct{}{}
+
+	for _, uri := range services.ObjectURI {
+		handled[uri] = struct{}{}
+	}
+
+	for _, uri := range services.ServiceExtension.ExtensionURI {
+		handled[uri] = struct{}{}
+	}
+
+	if _, ok := handled[types.NameSpaceUnhandledNamespaces10]; !ok {
+		return nil
+	}
+
+	return handled
+}
+
+// 응답에서 클라이언트가 협상하지 않은 네임스페이스의 resData 와 확장을 첫 번째 결과의
+// extValue 로 옮깁니다. (RFC 9038) 옮긴 후 비어있는 resData 와 extension 은
+// 제거합니다.
+func moveUnhandledNamespaces(document *xmltree.Element, handled map[string]struct{}) {
+	response := childElement(document, "response")
+	if response == nil {
+		return
+	}
+
+	var (
+		values   []xmltree.Element
+		children []xmltree.Element
+	)
+
+	for _, child := range response.Children {
+		if child.Name.Local != "resData" && child.Name.Local != "extension" {
+			children = append(children, child)
+			continue
+		}
+
+		kept := []xmltree.Element{}
+
+		for _, el := range child.Children {
+			if _, ok := handled[el.Name.Space]; ok {
+				kept = append(kept, el)
+				continue
+			}
+
+			values = append(values, el)
+		}
+
+		if len(kept) > 0 {
+			child.Children = kept
+			children = append(children, child)
+		}
+	}
+
+	if len(values) == 0 {
+		return
+	}
+
+	response.Children = children
+
+	result := childElement(response, "result")
+	if result == nil {
+		return
+	}
+
+	for _, value := range values {
+		result.Children = append(result.Children, unhandledValue(result, value))
+	}
+}
+
+// 협상하지 않은 네임스페이스의 요소를 담은 extValue 요소를 생성합니다.
+func unhandledValue(result *xmltree.Element, value xmltree.Element) xmltree.Element {
+	element := func(local string, children ...xmltree.Element) xmltree.Element {
+		return xmltree.Element{
+			StartElement: xml.StartElement{Name: xml.Name{Space: result.Name.Space, Local: local}},
+			Scope:        result.Scope,
+			Children:     children,
+		}
+	}
+
+	var reason bytes.Buffer
+
+	// 네임스페이스 URI 는 항상 Escape 할 수 있으므로 오류를 무시합니다.
+	_ = xml.EscapeText(&reason, []byte(value.Name.Space+" not in login services"))
+
+	reasonElement := element("reason")
+	reasonElement.Content = reason.Bytes()
+
+	return element("extValue", element("value", value), reasonElement)
+}
+
+func childElement(parent *xmltree.Element, local string) *xmltree.Element {
+	for i := range parent.Children {
+		if parent.Children[i].Name.Local == local {
+			return &parent.Children[i]
+		}
+	}
+
+	return nil
+}